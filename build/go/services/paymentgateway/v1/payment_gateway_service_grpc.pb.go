@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PaymentGatewayServiceClient is the client API for PaymentGatewayService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PaymentGatewayServiceClient interface {
+	// Authorize creates a payment authorizing the amount against the card.
+	Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeResponse, error)
+	// Capture captures funds from an authorized payment.
+	Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*CaptureResponse, error)
+	// Refund refunds captured funds of a payment.
+	Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundResponse, error)
+	// Void cancels an authorized payment.
+	Void(ctx context.Context, in *VoidRequest, opts ...grpc.CallOption) (*VoidResponse, error)
+	// IncrementAuthorization increases the authorized amount of an authorized payment.
+	IncrementAuthorization(ctx context.Context, in *IncrementAuthorizationRequest, opts ...grpc.CallOption) (*IncrementAuthorizationResponse, error)
+	// Reverse releases part or all of the authorized amount of a payment that is yet to be captured.
+	Reverse(ctx context.Context, in *ReverseRequest, opts ...grpc.CallOption) (*ReverseResponse, error)
+	// GetPayment fetches a payment along with its actions and balances.
+	GetPayment(ctx context.Context, in *GetPaymentRequest, opts ...grpc.CallOption) (*GetPaymentResponse, error)
+}
+
+type paymentGatewayServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentGatewayServiceClient(cc grpc.ClientConnInterface) PaymentGatewayServiceClient {
+	return &paymentGatewayServiceClient{cc}
+}
+
+func (c *paymentGatewayServiceClient) Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeResponse, error) {
+	out := new(AuthorizeResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/Authorize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGatewayServiceClient) Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*CaptureResponse, error) {
+	out := new(CaptureResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/Capture", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGatewayServiceClient) Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundResponse, error) {
+	out := new(RefundResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/Refund", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGatewayServiceClient) Void(ctx context.Context, in *VoidRequest, opts ...grpc.CallOption) (*VoidResponse, error) {
+	out := new(VoidResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/Void", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGatewayServiceClient) IncrementAuthorization(ctx context.Context, in *IncrementAuthorizationRequest, opts ...grpc.CallOption) (*IncrementAuthorizationResponse, error) {
+	out := new(IncrementAuthorizationResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/IncrementAuthorization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGatewayServiceClient) Reverse(ctx context.Context, in *ReverseRequest, opts ...grpc.CallOption) (*ReverseResponse, error) {
+	out := new(ReverseResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/Reverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentGatewayServiceClient) GetPayment(ctx context.Context, in *GetPaymentRequest, opts ...grpc.CallOption) (*GetPaymentResponse, error) {
+	out := new(GetPaymentResponse)
+	err := c.cc.Invoke(ctx, "/services.paymentgateway.v1.PaymentGatewayService/GetPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentGatewayServiceServer is the server API for PaymentGatewayService service.
+// All implementations must embed UnimplementedPaymentGatewayServiceServer
+// for forward compatibility
+type PaymentGatewayServiceServer interface {
+	// Authorize creates a payment authorizing the amount against the card.
+	Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error)
+	// Capture captures funds from an authorized payment.
+	Capture(context.Context, *CaptureRequest) (*CaptureResponse, error)
+	// Refund refunds captured funds of a payment.
+	Refund(context.Context, *RefundRequest) (*RefundResponse, error)
+	// Void cancels an authorized payment.
+	Void(context.Context, *VoidRequest) (*VoidResponse, error)
+	// IncrementAuthorization increases the authorized amount of an authorized payment.
+	IncrementAuthorization(context.Context, *IncrementAuthorizationRequest) (*IncrementAuthorizationResponse, error)
+	// Reverse releases part or all of the authorized amount of a payment that is yet to be captured.
+	Reverse(context.Context, *ReverseRequest) (*ReverseResponse, error)
+	// GetPayment fetches a payment along with its actions and balances.
+	GetPayment(context.Context, *GetPaymentRequest) (*GetPaymentResponse, error)
+	mustEmbedUnimplementedPaymentGatewayServiceServer()
+}
+
+// UnimplementedPaymentGatewayServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPaymentGatewayServiceServer struct {
+}
+
+func (UnimplementedPaymentGatewayServiceServer) Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) Capture(context.Context, *CaptureRequest) (*CaptureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Capture not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) Refund(context.Context, *RefundRequest) (*RefundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refund not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) Void(context.Context, *VoidRequest) (*VoidResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Void not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) IncrementAuthorization(context.Context, *IncrementAuthorizationRequest) (*IncrementAuthorizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IncrementAuthorization not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) Reverse(context.Context, *ReverseRequest) (*ReverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reverse not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) GetPayment(context.Context, *GetPaymentRequest) (*GetPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPayment not implemented")
+}
+func (UnimplementedPaymentGatewayServiceServer) mustEmbedUnimplementedPaymentGatewayServiceServer() {}
+
+// UnsafePaymentGatewayServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentGatewayServiceServer will
+// result in compilation errors.
+type UnsafePaymentGatewayServiceServer interface {
+	mustEmbedUnimplementedPaymentGatewayServiceServer()
+}
+
+func RegisterPaymentGatewayServiceServer(s grpc.ServiceRegistrar, srv PaymentGatewayServiceServer) {
+	s.RegisterService(&PaymentGatewayService_ServiceDesc, srv)
+}
+
+func _PaymentGatewayService_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/Authorize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).Authorize(ctx, req.(*AuthorizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGatewayService_Capture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).Capture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/Capture",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).Capture(ctx, req.(*CaptureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGatewayService_Refund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).Refund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/Refund",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).Refund(ctx, req.(*RefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGatewayService_Void_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).Void(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/Void",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).Void(ctx, req.(*VoidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGatewayService_IncrementAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).IncrementAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/IncrementAuthorization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).IncrementAuthorization(ctx, req.(*IncrementAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGatewayService_Reverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).Reverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/Reverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).Reverse(ctx, req.(*ReverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentGatewayService_GetPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentGatewayServiceServer).GetPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/services.paymentgateway.v1.PaymentGatewayService/GetPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentGatewayServiceServer).GetPayment(ctx, req.(*GetPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentGatewayService_ServiceDesc is the grpc.ServiceDesc for PaymentGatewayService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentGatewayService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "services.paymentgateway.v1.PaymentGatewayService",
+	HandlerType: (*PaymentGatewayServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Authorize",
+			Handler:    _PaymentGatewayService_Authorize_Handler,
+		},
+		{
+			MethodName: "Capture",
+			Handler:    _PaymentGatewayService_Capture_Handler,
+		},
+		{
+			MethodName: "Refund",
+			Handler:    _PaymentGatewayService_Refund_Handler,
+		},
+		{
+			MethodName: "Void",
+			Handler:    _PaymentGatewayService_Void_Handler,
+		},
+		{
+			MethodName: "IncrementAuthorization",
+			Handler:    _PaymentGatewayService_IncrementAuthorization_Handler,
+		},
+		{
+			MethodName: "Reverse",
+			Handler:    _PaymentGatewayService_Reverse_Handler,
+		},
+		{
+			MethodName: "GetPayment",
+			Handler:    _PaymentGatewayService_GetPayment_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "services/paymentgateway/v1/payment_gateway_service.proto",
+}
@@ -36,6 +36,10 @@ const (
 	PaymentType_PAYMENT_TYPE_REFUND PaymentType = 3
 	// The payment type is a void type
 	PaymentType_PAYMENT_TYPE_VOID PaymentType = 4
+	// The payment type is an increment to the authorized amount.
+	PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION PaymentType = 5
+	// The payment type is a reversal releasing part of the authorized amount that is yet to be captured.
+	PaymentType_PAYMENT_TYPE_REVERSAL PaymentType = 6
 )
 
 // Enum value maps for PaymentType.
@@ -46,13 +50,17 @@ var (
 		2: "PAYMENT_TYPE_CAPTURE",
 		3: "PAYMENT_TYPE_REFUND",
 		4: "PAYMENT_TYPE_VOID",
+		5: "PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION",
+		6: "PAYMENT_TYPE_REVERSAL",
 	}
 	PaymentType_value = map[string]int32{
-		"PAYMENT_TYPE_UNSPECIFIED":   0,
-		"PAYMENT_TYPE_AUTHORIZATION": 1,
-		"PAYMENT_TYPE_CAPTURE":       2,
-		"PAYMENT_TYPE_REFUND":        3,
-		"PAYMENT_TYPE_VOID":          4,
+		"PAYMENT_TYPE_UNSPECIFIED":               0,
+		"PAYMENT_TYPE_AUTHORIZATION":             1,
+		"PAYMENT_TYPE_CAPTURE":                   2,
+		"PAYMENT_TYPE_REFUND":                    3,
+		"PAYMENT_TYPE_VOID":                      4,
+		"PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION": 5,
+		"PAYMENT_TYPE_REVERSAL":                  6,
 	}
 )
 
@@ -83,6 +91,71 @@ func (PaymentType) EnumDescriptor() ([]byte, []int) {
 	return file_shared_payment_v1_payment_action_proto_rawDescGZIP(), []int{0}
 }
 
+// The category of an ISO 8583 response code.
+type ResponseCategory int32
+
+const (
+	// The response category is unspecified, the action has no response yet.
+	ResponseCategory_RESPONSE_CATEGORY_UNSPECIFIED ResponseCategory = 0
+	// The action was approved.
+	ResponseCategory_RESPONSE_CATEGORY_APPROVED ResponseCategory = 1
+	// The action was declined but may succeed if retried.
+	ResponseCategory_RESPONSE_CATEGORY_SOFT_DECLINE ResponseCategory = 2
+	// The action was declined and will not succeed if retried.
+	ResponseCategory_RESPONSE_CATEGORY_HARD_DECLINE ResponseCategory = 3
+	// The issuer requires the cardholder to contact them.
+	ResponseCategory_RESPONSE_CATEGORY_REFERRAL ResponseCategory = 4
+	// The action was declined as the card is lost, stolen or suspected of fraud.
+	ResponseCategory_RESPONSE_CATEGORY_FRAUD ResponseCategory = 5
+)
+
+// Enum value maps for ResponseCategory.
+var (
+	ResponseCategory_name = map[int32]string{
+		0: "RESPONSE_CATEGORY_UNSPECIFIED",
+		1: "RESPONSE_CATEGORY_APPROVED",
+		2: "RESPONSE_CATEGORY_SOFT_DECLINE",
+		3: "RESPONSE_CATEGORY_HARD_DECLINE",
+		4: "RESPONSE_CATEGORY_REFERRAL",
+		5: "RESPONSE_CATEGORY_FRAUD",
+	}
+	ResponseCategory_value = map[string]int32{
+		"RESPONSE_CATEGORY_UNSPECIFIED":  0,
+		"RESPONSE_CATEGORY_APPROVED":     1,
+		"RESPONSE_CATEGORY_SOFT_DECLINE": 2,
+		"RESPONSE_CATEGORY_HARD_DECLINE": 3,
+		"RESPONSE_CATEGORY_REFERRAL":     4,
+		"RESPONSE_CATEGORY_FRAUD":        5,
+	}
+)
+
+func (x ResponseCategory) Enum() *ResponseCategory {
+	p := new(ResponseCategory)
+	*p = x
+	return p
+}
+
+func (x ResponseCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResponseCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_payment_v1_payment_action_proto_enumTypes[1].Descriptor()
+}
+
+func (ResponseCategory) Type() protoreflect.EnumType {
+	return &file_shared_payment_v1_payment_action_proto_enumTypes[1]
+}
+
+func (x ResponseCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResponseCategory.Descriptor instead.
+func (ResponseCategory) EnumDescriptor() ([]byte, []int) {
+	return file_shared_payment_v1_payment_action_proto_rawDescGZIP(), []int{1}
+}
+
 // The action made towards a payment
 type PaymentAction struct {
 	state         protoimpl.MessageState
@@ -103,6 +176,15 @@ type PaymentAction struct {
 	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	// The time in which the action was successfully processed.
 	ProcessedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	// The category of the response code.
+	ResponseCategory ResponseCategory `protobuf:"varint,8,opt,name=response_category,json=responseCategory,proto3,enum=shared.payment.v1.ResponseCategory" json:"response_category,omitempty"`
+	// The reason the issuer gave for not approving the action.
+	DeclineReason string `protobuf:"bytes,9,opt,name=decline_reason,json=declineReason,proto3" json:"decline_reason,omitempty"`
+	// The acquirer the action was sent to.
+	Acquirer string `protobuf:"bytes,10,opt,name=acquirer,proto3" json:"acquirer,omitempty"`
+	// The action amount converted to the payment's settlement currency by its locked FX rate, zero when the payment
+	// has no settlement currency.
+	SettlementAmount uint64 `protobuf:"varint,11,opt,name=settlement_amount,json=settlementAmount,proto3" json:"settlement_amount,omitempty"`
 }
 
 func (x *PaymentAction) Reset() {
@@ -186,6 +268,34 @@ func (x *PaymentAction) GetProcessedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *PaymentAction) GetResponseCategory() ResponseCategory {
+	if x != nil {
+		return x.ResponseCategory
+	}
+	return ResponseCategory_RESPONSE_CATEGORY_UNSPECIFIED
+}
+
+func (x *PaymentAction) GetDeclineReason() string {
+	if x != nil {
+		return x.DeclineReason
+	}
+	return ""
+}
+
+func (x *PaymentAction) GetAcquirer() string {
+	if x != nil {
+		return x.Acquirer
+	}
+	return ""
+}
+
+func (x *PaymentAction) GetSettlementAmount() uint64 {
+	if x != nil {
+		return x.SettlementAmount
+	}
+	return 0
+}
+
 var File_shared_payment_v1_payment_action_proto protoreflect.FileDescriptor
 
 var file_shared_payment_v1_payment_action_proto_rawDesc = []byte{
@@ -196,7 +306,7 @@ var file_shared_payment_v1_payment_action_proto_rawDesc = []byte{
 	0x72, 0x65, 0x64, 0x2f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x6f,
 	0x6e, 0x65, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb8, 0x02, 0x0a, 0x0d, 0x50,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xfa, 0x03, 0x0a, 0x0d, 0x50,
 	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06,
 	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d,
@@ -216,21 +326,51 @@ var file_shared_payment_v1_payment_action_proto_rawDesc = []byte{
 	0x73, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
 	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
-	0x73, 0x65, 0x64, 0x41, 0x74, 0x2a, 0x95, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54,
-	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
-	0x44, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x4f, 0x52, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f,
-	0x4e, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x43, 0x41, 0x50, 0x54, 0x55, 0x52, 0x45, 0x10, 0x02, 0x12, 0x17, 0x0a,
-	0x13, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45,
-	0x46, 0x55, 0x4e, 0x44, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e,
-	0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x4f, 0x49, 0x44, 0x10, 0x04, 0x42, 0x40, 0x5a,
-	0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x63, 0x6b,
-	0x74, 0x61, 0x6e, 0x74, 0x72, 0x61, 0x6d, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73,
-	0x2d, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x67, 0x6f, 0x2f, 0x73, 0x68,
-	0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x65, 0x64, 0x41, 0x74, 0x12, 0x50, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x23, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x10, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43,
+	0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x65, 0x63, 0x6c, 0x69,
+	0x6e, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x64, 0x65, 0x63, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x2a, 0xdc, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x41, 0x59, 0x4d, 0x45,
+	0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x4f, 0x52, 0x49, 0x5a, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x41, 0x50, 0x54, 0x55, 0x52, 0x45, 0x10, 0x02, 0x12,
+	0x17, 0x0a, 0x13, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x52, 0x45, 0x46, 0x55, 0x4e, 0x44, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x41, 0x59, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x4f, 0x49, 0x44, 0x10, 0x04, 0x12,
+	0x2a, 0x0a, 0x26, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x49, 0x4e, 0x43, 0x52, 0x45, 0x4d, 0x45, 0x4e, 0x54, 0x41, 0x4c, 0x5f, 0x41, 0x55, 0x54, 0x48,
+	0x4f, 0x52, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x05, 0x12, 0x19, 0x0a, 0x15, 0x50,
+	0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x56, 0x45,
+	0x52, 0x53, 0x41, 0x4c, 0x10, 0x06, 0x2a, 0xda, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x1d, 0x52,
+	0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1e,
+	0x0a, 0x1a, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47,
+	0x4f, 0x52, 0x59, 0x5f, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x01, 0x12, 0x22,
+	0x0a, 0x1e, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47,
+	0x4f, 0x52, 0x59, 0x5f, 0x53, 0x4f, 0x46, 0x54, 0x5f, 0x44, 0x45, 0x43, 0x4c, 0x49, 0x4e, 0x45,
+	0x10, 0x02, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e, 0x53, 0x45, 0x5f, 0x43,
+	0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x48, 0x41, 0x52, 0x44, 0x5f, 0x44, 0x45, 0x43,
+	0x4c, 0x49, 0x4e, 0x45, 0x10, 0x03, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e,
+	0x53, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x52, 0x45, 0x46, 0x45,
+	0x52, 0x52, 0x41, 0x4c, 0x10, 0x04, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x53, 0x50, 0x4f, 0x4e,
+	0x53, 0x45, 0x5f, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59, 0x5f, 0x46, 0x52, 0x41, 0x55,
+	0x44, 0x10, 0x05, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x6a, 0x61, 0x63, 0x6b, 0x74, 0x61, 0x6e, 0x74, 0x72, 0x61, 0x6d, 0x2f, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2f, 0x67, 0x6f, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -245,22 +385,24 @@ func file_shared_payment_v1_payment_action_proto_rawDescGZIP() []byte {
 	return file_shared_payment_v1_payment_action_proto_rawDescData
 }
 
-var file_shared_payment_v1_payment_action_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_shared_payment_v1_payment_action_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_shared_payment_v1_payment_action_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_shared_payment_v1_payment_action_proto_goTypes = []interface{}{
 	(PaymentType)(0),              // 0: shared.payment.v1.PaymentType
-	(*PaymentAction)(nil),         // 1: shared.payment.v1.PaymentAction
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(ResponseCategory)(0),         // 1: shared.payment.v1.ResponseCategory
+	(*PaymentAction)(nil),         // 2: shared.payment.v1.PaymentAction
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
 }
 var file_shared_payment_v1_payment_action_proto_depIdxs = []int32{
 	0, // 0: shared.payment.v1.PaymentAction.payment_type:type_name -> shared.payment.v1.PaymentType
-	2, // 1: shared.payment.v1.PaymentAction.created_at:type_name -> google.protobuf.Timestamp
-	2, // 2: shared.payment.v1.PaymentAction.processed_at:type_name -> google.protobuf.Timestamp
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3, // 1: shared.payment.v1.PaymentAction.created_at:type_name -> google.protobuf.Timestamp
+	3, // 2: shared.payment.v1.PaymentAction.processed_at:type_name -> google.protobuf.Timestamp
+	1, // 3: shared.payment.v1.PaymentAction.response_category:type_name -> shared.payment.v1.ResponseCategory
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_shared_payment_v1_payment_action_proto_init() }
@@ -287,7 +429,7 @@ func file_shared_payment_v1_payment_action_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_shared_payment_v1_payment_action_proto_rawDesc,
-			NumEnums:      1,
+			NumEnums:      2,
 			NumMessages:   1,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -0,0 +1,446 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.18.1
+// source: shared/payment/v1/payment_details.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Represents a payment along with its balances and the actions made towards it.
+type PaymentDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The payment.
+	Payment *Payment `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	// The balances derived from the successful payment actions.
+	Balance *PaymentBalance `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// The actions made towards the payment in the order they were created.
+	PaymentActions []*PaymentAction `protobuf:"bytes,3,rep,name=payment_actions,json=paymentActions,proto3" json:"payment_actions,omitempty"`
+}
+
+func (x *PaymentDetails) Reset() {
+	*x = PaymentDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shared_payment_v1_payment_details_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentDetails) ProtoMessage() {}
+
+func (x *PaymentDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_payment_v1_payment_details_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentDetails.ProtoReflect.Descriptor instead.
+func (*PaymentDetails) Descriptor() ([]byte, []int) {
+	return file_shared_payment_v1_payment_details_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PaymentDetails) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *PaymentDetails) GetBalance() *PaymentBalance {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *PaymentDetails) GetPaymentActions() []*PaymentAction {
+	if x != nil {
+		return x.PaymentActions
+	}
+	return nil
+}
+
+// Represents the balances of a payment in minor units.
+type PaymentBalance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The amount that has been successfully captured.
+	Captured uint64 `protobuf:"varint,1,opt,name=captured,proto3" json:"captured,omitempty"`
+	// The amount that has been successfully refunded.
+	Refunded uint64 `protobuf:"varint,2,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	// The authorized amount that is still available to capture.
+	Remaining uint64 `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// The amount authorized, the payment amount along with its successful incremental authorizations less the amount
+	// released by reversals, voids or expiry.
+	Authorized uint64 `protobuf:"varint,4,opt,name=authorized,proto3" json:"authorized,omitempty"`
+	// The authorized amount that has been successfully released by reversals.
+	Reversed uint64 `protobuf:"varint,5,opt,name=reversed,proto3" json:"reversed,omitempty"`
+}
+
+func (x *PaymentBalance) Reset() {
+	*x = PaymentBalance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shared_payment_v1_payment_details_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentBalance) ProtoMessage() {}
+
+func (x *PaymentBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_payment_v1_payment_details_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentBalance.ProtoReflect.Descriptor instead.
+func (*PaymentBalance) Descriptor() ([]byte, []int) {
+	return file_shared_payment_v1_payment_details_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PaymentBalance) GetCaptured() uint64 {
+	if x != nil {
+		return x.Captured
+	}
+	return 0
+}
+
+func (x *PaymentBalance) GetRefunded() uint64 {
+	if x != nil {
+		return x.Refunded
+	}
+	return 0
+}
+
+func (x *PaymentBalance) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *PaymentBalance) GetAuthorized() uint64 {
+	if x != nil {
+		return x.Authorized
+	}
+	return 0
+}
+
+func (x *PaymentBalance) GetReversed() uint64 {
+	if x != nil {
+		return x.Reversed
+	}
+	return 0
+}
+
+// Represents the actions made towards a payment.
+type PaymentActions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The actions in the order they were created.
+	PaymentActions []*PaymentAction `protobuf:"bytes,1,rep,name=payment_actions,json=paymentActions,proto3" json:"payment_actions,omitempty"`
+}
+
+func (x *PaymentActions) Reset() {
+	*x = PaymentActions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shared_payment_v1_payment_details_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentActions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentActions) ProtoMessage() {}
+
+func (x *PaymentActions) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_payment_v1_payment_details_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentActions.ProtoReflect.Descriptor instead.
+func (*PaymentActions) Descriptor() ([]byte, []int) {
+	return file_shared_payment_v1_payment_details_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PaymentActions) GetPaymentActions() []*PaymentAction {
+	if x != nil {
+		return x.PaymentActions
+	}
+	return nil
+}
+
+// Represents a page of payments.
+type PaymentList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The payments ordered newest first.
+	Payments []*Payment `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	// The cursor to fetch the next page with. Empty when there are no more payments.
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+}
+
+func (x *PaymentList) Reset() {
+	*x = PaymentList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_shared_payment_v1_payment_details_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentList) ProtoMessage() {}
+
+func (x *PaymentList) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_payment_v1_payment_details_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentList.ProtoReflect.Descriptor instead.
+func (*PaymentList) Descriptor() ([]byte, []int) {
+	return file_shared_payment_v1_payment_details_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PaymentList) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *PaymentList) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_shared_payment_v1_payment_details_proto protoreflect.FileDescriptor
+
+var file_shared_payment_v1_payment_details_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x64, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x26, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31,
+	0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xce, 0x01, 0x0a, 0x0e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x64, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x3b,
+	0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x49, 0x0a, 0x0f, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa2, 0x01, 0x0a, 0x0e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x70,
+	0x74, 0x75, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x61, 0x70,
+	0x74, 0x75, 0x72, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x65,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12,
+	0x1e, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x22, 0x5b, 0x0a, 0x0e, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x49, 0x0a,
+	0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x66, 0x0a, 0x0b, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x64, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72,
+	0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a,
+	0x61, 0x63, 0x6b, 0x74, 0x61, 0x6e, 0x74, 0x72, 0x61, 0x6d, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x67, 0x6f,
+	0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2f,
+	0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_shared_payment_v1_payment_details_proto_rawDescOnce sync.Once
+	file_shared_payment_v1_payment_details_proto_rawDescData = file_shared_payment_v1_payment_details_proto_rawDesc
+)
+
+func file_shared_payment_v1_payment_details_proto_rawDescGZIP() []byte {
+	file_shared_payment_v1_payment_details_proto_rawDescOnce.Do(func() {
+		file_shared_payment_v1_payment_details_proto_rawDescData = protoimpl.X.CompressGZIP(file_shared_payment_v1_payment_details_proto_rawDescData)
+	})
+	return file_shared_payment_v1_payment_details_proto_rawDescData
+}
+
+var file_shared_payment_v1_payment_details_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_shared_payment_v1_payment_details_proto_goTypes = []interface{}{
+	(*PaymentDetails)(nil), // 0: shared.payment.v1.PaymentDetails
+	(*PaymentBalance)(nil), // 1: shared.payment.v1.PaymentBalance
+	(*PaymentActions)(nil), // 2: shared.payment.v1.PaymentActions
+	(*PaymentList)(nil),    // 3: shared.payment.v1.PaymentList
+	(*Payment)(nil),        // 4: shared.payment.v1.Payment
+	(*PaymentAction)(nil),  // 5: shared.payment.v1.PaymentAction
+}
+var file_shared_payment_v1_payment_details_proto_depIdxs = []int32{
+	4, // 0: shared.payment.v1.PaymentDetails.payment:type_name -> shared.payment.v1.Payment
+	1, // 1: shared.payment.v1.PaymentDetails.balance:type_name -> shared.payment.v1.PaymentBalance
+	5, // 2: shared.payment.v1.PaymentDetails.payment_actions:type_name -> shared.payment.v1.PaymentAction
+	5, // 3: shared.payment.v1.PaymentActions.payment_actions:type_name -> shared.payment.v1.PaymentAction
+	4, // 4: shared.payment.v1.PaymentList.payments:type_name -> shared.payment.v1.Payment
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_shared_payment_v1_payment_details_proto_init() }
+func file_shared_payment_v1_payment_details_proto_init() {
+	if File_shared_payment_v1_payment_details_proto != nil {
+		return
+	}
+	file_shared_payment_v1_payment_proto_init()
+	file_shared_payment_v1_payment_action_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_shared_payment_v1_payment_details_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shared_payment_v1_payment_details_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentBalance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shared_payment_v1_payment_details_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentActions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_shared_payment_v1_payment_details_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_shared_payment_v1_payment_details_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_shared_payment_v1_payment_details_proto_goTypes,
+		DependencyIndexes: file_shared_payment_v1_payment_details_proto_depIdxs,
+		MessageInfos:      file_shared_payment_v1_payment_details_proto_msgTypes,
+	}.Build()
+	File_shared_payment_v1_payment_details_proto = out.File
+	file_shared_payment_v1_payment_details_proto_rawDesc = nil
+	file_shared_payment_v1_payment_details_proto_goTypes = nil
+	file_shared_payment_v1_payment_details_proto_depIdxs = nil
+}
@@ -0,0 +1,170 @@
+package currency
+
+// currencies are the active ISO 4217 currencies, excluding precious metals, testing and other codes without an
+// exponent.
+var currencies = map[string]Currency{
+	"AED": {Code: "AED", Number: "784", Exponent: 2},
+	"AFN": {Code: "AFN", Number: "971", Exponent: 2},
+	"ALL": {Code: "ALL", Number: "008", Exponent: 2},
+	"AMD": {Code: "AMD", Number: "051", Exponent: 2},
+	"AOA": {Code: "AOA", Number: "973", Exponent: 2},
+	"ARS": {Code: "ARS", Number: "032", Exponent: 2},
+	"AUD": {Code: "AUD", Number: "036", Exponent: 2},
+	"AWG": {Code: "AWG", Number: "533", Exponent: 2},
+	"AZN": {Code: "AZN", Number: "944", Exponent: 2},
+	"BAM": {Code: "BAM", Number: "977", Exponent: 2},
+	"BBD": {Code: "BBD", Number: "052", Exponent: 2},
+	"BDT": {Code: "BDT", Number: "050", Exponent: 2},
+	"BHD": {Code: "BHD", Number: "048", Exponent: 3},
+	"BIF": {Code: "BIF", Number: "108", Exponent: 0},
+	"BMD": {Code: "BMD", Number: "060", Exponent: 2},
+	"BND": {Code: "BND", Number: "096", Exponent: 2},
+	"BOB": {Code: "BOB", Number: "068", Exponent: 2},
+	"BOV": {Code: "BOV", Number: "984", Exponent: 2},
+	"BRL": {Code: "BRL", Number: "986", Exponent: 2},
+	"BSD": {Code: "BSD", Number: "044", Exponent: 2},
+	"BTN": {Code: "BTN", Number: "064", Exponent: 2},
+	"BWP": {Code: "BWP", Number: "072", Exponent: 2},
+	"BYN": {Code: "BYN", Number: "933", Exponent: 2},
+	"BZD": {Code: "BZD", Number: "084", Exponent: 2},
+	"CAD": {Code: "CAD", Number: "124", Exponent: 2},
+	"CDF": {Code: "CDF", Number: "976", Exponent: 2},
+	"CHE": {Code: "CHE", Number: "947", Exponent: 2},
+	"CHF": {Code: "CHF", Number: "756", Exponent: 2},
+	"CHW": {Code: "CHW", Number: "948", Exponent: 2},
+	"CLF": {Code: "CLF", Number: "990", Exponent: 4},
+	"CLP": {Code: "CLP", Number: "152", Exponent: 0},
+	"CNY": {Code: "CNY", Number: "156", Exponent: 2},
+	"COP": {Code: "COP", Number: "170", Exponent: 2},
+	"COU": {Code: "COU", Number: "970", Exponent: 2},
+	"CRC": {Code: "CRC", Number: "188", Exponent: 2},
+	"CUP": {Code: "CUP", Number: "192", Exponent: 2},
+	"CVE": {Code: "CVE", Number: "132", Exponent: 2},
+	"CZK": {Code: "CZK", Number: "203", Exponent: 2},
+	"DJF": {Code: "DJF", Number: "262", Exponent: 0},
+	"DKK": {Code: "DKK", Number: "208", Exponent: 2},
+	"DOP": {Code: "DOP", Number: "214", Exponent: 2},
+	"DZD": {Code: "DZD", Number: "012", Exponent: 2},
+	"EGP": {Code: "EGP", Number: "818", Exponent: 2},
+	"ERN": {Code: "ERN", Number: "232", Exponent: 2},
+	"ETB": {Code: "ETB", Number: "230", Exponent: 2},
+	"EUR": {Code: "EUR", Number: "978", Exponent: 2},
+	"FJD": {Code: "FJD", Number: "242", Exponent: 2},
+	"FKP": {Code: "FKP", Number: "238", Exponent: 2},
+	"GBP": {Code: "GBP", Number: "826", Exponent: 2},
+	"GEL": {Code: "GEL", Number: "981", Exponent: 2},
+	"GHS": {Code: "GHS", Number: "936", Exponent: 2},
+	"GIP": {Code: "GIP", Number: "292", Exponent: 2},
+	"GMD": {Code: "GMD", Number: "270", Exponent: 2},
+	"GNF": {Code: "GNF", Number: "324", Exponent: 0},
+	"GTQ": {Code: "GTQ", Number: "320", Exponent: 2},
+	"GYD": {Code: "GYD", Number: "328", Exponent: 2},
+	"HKD": {Code: "HKD", Number: "344", Exponent: 2},
+	"HNL": {Code: "HNL", Number: "340", Exponent: 2},
+	"HTG": {Code: "HTG", Number: "332", Exponent: 2},
+	"HUF": {Code: "HUF", Number: "348", Exponent: 2},
+	"IDR": {Code: "IDR", Number: "360", Exponent: 2},
+	"ILS": {Code: "ILS", Number: "376", Exponent: 2},
+	"INR": {Code: "INR", Number: "356", Exponent: 2},
+	"IQD": {Code: "IQD", Number: "368", Exponent: 3},
+	"IRR": {Code: "IRR", Number: "364", Exponent: 2},
+	"ISK": {Code: "ISK", Number: "352", Exponent: 0},
+	"JMD": {Code: "JMD", Number: "388", Exponent: 2},
+	"JOD": {Code: "JOD", Number: "400", Exponent: 3},
+	"JPY": {Code: "JPY", Number: "392", Exponent: 0},
+	"KES": {Code: "KES", Number: "404", Exponent: 2},
+	"KGS": {Code: "KGS", Number: "417", Exponent: 2},
+	"KHR": {Code: "KHR", Number: "116", Exponent: 2},
+	"KMF": {Code: "KMF", Number: "174", Exponent: 0},
+	"KPW": {Code: "KPW", Number: "408", Exponent: 2},
+	"KRW": {Code: "KRW", Number: "410", Exponent: 0},
+	"KWD": {Code: "KWD", Number: "414", Exponent: 3},
+	"KYD": {Code: "KYD", Number: "136", Exponent: 2},
+	"KZT": {Code: "KZT", Number: "398", Exponent: 2},
+	"LAK": {Code: "LAK", Number: "418", Exponent: 2},
+	"LBP": {Code: "LBP", Number: "422", Exponent: 2},
+	"LKR": {Code: "LKR", Number: "144", Exponent: 2},
+	"LRD": {Code: "LRD", Number: "430", Exponent: 2},
+	"LSL": {Code: "LSL", Number: "426", Exponent: 2},
+	"LYD": {Code: "LYD", Number: "434", Exponent: 3},
+	"MAD": {Code: "MAD", Number: "504", Exponent: 2},
+	"MDL": {Code: "MDL", Number: "498", Exponent: 2},
+	"MGA": {Code: "MGA", Number: "969", Exponent: 2},
+	"MKD": {Code: "MKD", Number: "807", Exponent: 2},
+	"MMK": {Code: "MMK", Number: "104", Exponent: 2},
+	"MNT": {Code: "MNT", Number: "496", Exponent: 2},
+	"MOP": {Code: "MOP", Number: "446", Exponent: 2},
+	"MRU": {Code: "MRU", Number: "929", Exponent: 2},
+	"MUR": {Code: "MUR", Number: "480", Exponent: 2},
+	"MVR": {Code: "MVR", Number: "462", Exponent: 2},
+	"MWK": {Code: "MWK", Number: "454", Exponent: 2},
+	"MXN": {Code: "MXN", Number: "484", Exponent: 2},
+	"MXV": {Code: "MXV", Number: "979", Exponent: 2},
+	"MYR": {Code: "MYR", Number: "458", Exponent: 2},
+	"MZN": {Code: "MZN", Number: "943", Exponent: 2},
+	"NAD": {Code: "NAD", Number: "516", Exponent: 2},
+	"NGN": {Code: "NGN", Number: "566", Exponent: 2},
+	"NIO": {Code: "NIO", Number: "558", Exponent: 2},
+	"NOK": {Code: "NOK", Number: "578", Exponent: 2},
+	"NPR": {Code: "NPR", Number: "524", Exponent: 2},
+	"NZD": {Code: "NZD", Number: "554", Exponent: 2},
+	"OMR": {Code: "OMR", Number: "512", Exponent: 3},
+	"PAB": {Code: "PAB", Number: "590", Exponent: 2},
+	"PEN": {Code: "PEN", Number: "604", Exponent: 2},
+	"PGK": {Code: "PGK", Number: "598", Exponent: 2},
+	"PHP": {Code: "PHP", Number: "608", Exponent: 2},
+	"PKR": {Code: "PKR", Number: "586", Exponent: 2},
+	"PLN": {Code: "PLN", Number: "985", Exponent: 2},
+	"PYG": {Code: "PYG", Number: "600", Exponent: 0},
+	"QAR": {Code: "QAR", Number: "634", Exponent: 2},
+	"RON": {Code: "RON", Number: "946", Exponent: 2},
+	"RSD": {Code: "RSD", Number: "941", Exponent: 2},
+	"RUB": {Code: "RUB", Number: "643", Exponent: 2},
+	"RWF": {Code: "RWF", Number: "646", Exponent: 0},
+	"SAR": {Code: "SAR", Number: "682", Exponent: 2},
+	"SBD": {Code: "SBD", Number: "090", Exponent: 2},
+	"SCR": {Code: "SCR", Number: "690", Exponent: 2},
+	"SDG": {Code: "SDG", Number: "938", Exponent: 2},
+	"SEK": {Code: "SEK", Number: "752", Exponent: 2},
+	"SGD": {Code: "SGD", Number: "702", Exponent: 2},
+	"SHP": {Code: "SHP", Number: "654", Exponent: 2},
+	"SLE": {Code: "SLE", Number: "925", Exponent: 2},
+	"SOS": {Code: "SOS", Number: "706", Exponent: 2},
+	"SRD": {Code: "SRD", Number: "968", Exponent: 2},
+	"SSP": {Code: "SSP", Number: "728", Exponent: 2},
+	"STN": {Code: "STN", Number: "930", Exponent: 2},
+	"SVC": {Code: "SVC", Number: "222", Exponent: 2},
+	"SYP": {Code: "SYP", Number: "760", Exponent: 2},
+	"SZL": {Code: "SZL", Number: "748", Exponent: 2},
+	"THB": {Code: "THB", Number: "764", Exponent: 2},
+	"TJS": {Code: "TJS", Number: "972", Exponent: 2},
+	"TMT": {Code: "TMT", Number: "934", Exponent: 2},
+	"TND": {Code: "TND", Number: "788", Exponent: 3},
+	"TOP": {Code: "TOP", Number: "776", Exponent: 2},
+	"TRY": {Code: "TRY", Number: "949", Exponent: 2},
+	"TTD": {Code: "TTD", Number: "780", Exponent: 2},
+	"TWD": {Code: "TWD", Number: "901", Exponent: 2},
+	"TZS": {Code: "TZS", Number: "834", Exponent: 2},
+	"UAH": {Code: "UAH", Number: "980", Exponent: 2},
+	"UGX": {Code: "UGX", Number: "800", Exponent: 0},
+	"USD": {Code: "USD", Number: "840", Exponent: 2},
+	"USN": {Code: "USN", Number: "997", Exponent: 2},
+	"UYI": {Code: "UYI", Number: "940", Exponent: 0},
+	"UYU": {Code: "UYU", Number: "858", Exponent: 2},
+	"UYW": {Code: "UYW", Number: "927", Exponent: 4},
+	"UZS": {Code: "UZS", Number: "860", Exponent: 2},
+	"VED": {Code: "VED", Number: "926", Exponent: 2},
+	"VES": {Code: "VES", Number: "928", Exponent: 2},
+	"VND": {Code: "VND", Number: "704", Exponent: 0},
+	"VUV": {Code: "VUV", Number: "548", Exponent: 0},
+	"WST": {Code: "WST", Number: "882", Exponent: 2},
+	"XAF": {Code: "XAF", Number: "950", Exponent: 0},
+	"XCD": {Code: "XCD", Number: "951", Exponent: 2},
+	"XCG": {Code: "XCG", Number: "532", Exponent: 2},
+	"XOF": {Code: "XOF", Number: "952", Exponent: 0},
+	"XPF": {Code: "XPF", Number: "953", Exponent: 0},
+	"YER": {Code: "YER", Number: "886", Exponent: 2},
+	"ZAR": {Code: "ZAR", Number: "710", Exponent: 2},
+	"ZMW": {Code: "ZMW", Number: "967", Exponent: 2},
+	"ZWG": {Code: "ZWG", Number: "924", Exponent: 2},
+}
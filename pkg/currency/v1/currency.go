@@ -0,0 +1,77 @@
+// Package currency holds the ISO 4217 currencies with their minor unit exponents, validating currency codes and
+// converting amounts held in minor units to major unit strings.
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+)
+
+var ErrUnknownCurrency = errors.New("unknown ISO 4217 currency code")
+
+// Currency is an active ISO 4217 currency.
+type Currency struct {
+	// Code is the alphabetic code i.e. GBP.
+	Code string
+	// Number is the numeric code i.e. 826.
+	Number string
+	// Exponent is how many digits of the amount are after the decimal point i.e. GBP 2, JPY 0 and KWD 3.
+	Exponent int
+}
+
+// Lookup returns the currency by its upper case alphabetic code.
+func Lookup(code string) (Currency, error) {
+	c, ok := currencies[code]
+	if !ok {
+		return Currency{}, ErrUnknownCurrency
+	}
+	return c, nil
+}
+
+// Valid returns whether the code is an active ISO 4217 currency.
+func Valid(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// Format returns the amount in major units i.e. 2030 is "20.30" in GBP, "2030" in JPY and "2.030" in KWD.
+func (c Currency) Format(minorUnits uint64) string {
+	digits := strconv.FormatUint(minorUnits, 10)
+	if c.Exponent == 0 {
+		return digits
+	}
+	if len(digits) <= c.Exponent {
+		digits = strings.Repeat("0", c.Exponent-len(digits)+1) + digits
+	}
+	return digits[:len(digits)-c.Exponent] + "." + digits[len(digits)-c.Exponent:]
+}
+
+// FormatMoney returns the money's amount in major units.
+func FormatMoney(money *amountV1.Money) (string, error) {
+	c, err := Lookup(money.GetCurrency())
+	if err != nil {
+		return "", err
+	}
+	return c.Format(money.GetMinorUnits()), nil
+}
+
+// NewMoney returns the money with its major units set, they are left empty given an unknown currency.
+func NewMoney(minorUnits uint64, code string) *amountV1.Money {
+	money := &amountV1.Money{MinorUnits: minorUnits, Currency: code}
+	money.MajorUnits, _ = FormatMoney(money)
+	return money
+}
+
+// String returns the money in major units followed by its currency i.e. "20.30 GBP" for logging. Money in an unknown
+// currency is returned in minor units.
+func String(money *amountV1.Money) string {
+	major, err := FormatMoney(money)
+	if err != nil {
+		return fmt.Sprintf("%d minor units %s", money.GetMinorUnits(), money.GetCurrency())
+	}
+	return major + " " + money.GetCurrency()
+}
@@ -0,0 +1,104 @@
+package currency_test
+
+import (
+	"testing"
+
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		code        string
+		expExponent int
+		expErr      error
+	}{
+		{code: "GBP", expExponent: 2},
+		{code: "JPY", expExponent: 0},
+		{code: "KWD", expExponent: 3},
+		{code: "CLF", expExponent: 4},
+		{code: "XYZ", expErr: currencyV1.ErrUnknownCurrency},
+		{code: "gbp", expErr: currencyV1.ErrUnknownCurrency},
+		{code: "XAU", expErr: currencyV1.ErrUnknownCurrency},
+	} {
+		tc := tc
+		t.Run(tc.code, func(t *testing.T) {
+			t.Parallel()
+			c, err := currencyV1.Lookup(tc.code)
+			require.Equal(t, tc.expErr, err)
+			assert.Equal(t, tc.expErr == nil, currencyV1.Valid(tc.code))
+			if tc.expErr == nil {
+				assert.Equal(t, tc.code, c.Code)
+				assert.Equal(t, tc.expExponent, c.Exponent)
+			}
+		})
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		money       *amountV1.Money
+		exp         string
+		expString   string
+		expErr      error
+	}{
+		{
+			description: "should format an exponent of 2",
+			money:       &amountV1.Money{MinorUnits: 2030, Currency: "GBP"},
+			exp:         "20.30",
+			expString:   "20.30 GBP",
+		},
+		{
+			description: "should pad amounts below one major unit",
+			money:       &amountV1.Money{MinorUnits: 5, Currency: "GBP"},
+			exp:         "0.05",
+			expString:   "0.05 GBP",
+		},
+		{
+			description: "should format an exponent of 0",
+			money:       &amountV1.Money{MinorUnits: 2030, Currency: "JPY"},
+			exp:         "2030",
+			expString:   "2030 JPY",
+		},
+		{
+			description: "should format an exponent of 3",
+			money:       &amountV1.Money{MinorUnits: 2030, Currency: "KWD"},
+			exp:         "2.030",
+			expString:   "2.030 KWD",
+		},
+		{
+			description: "should format zero",
+			money:       &amountV1.Money{Currency: "BHD"},
+			exp:         "0.000",
+			expString:   "0.000 BHD",
+		},
+		{
+			description: "should return error given an unknown currency",
+			money:       &amountV1.Money{MinorUnits: 2030, Currency: "XYZ"},
+			expErr:      currencyV1.ErrUnknownCurrency,
+			expString:   "2030 minor units XYZ",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			major, err := currencyV1.FormatMoney(tc.money)
+			require.Equal(t, tc.expErr, err)
+			assert.Equal(t, tc.exp, major)
+			assert.Equal(t, tc.expString, currencyV1.String(tc.money))
+		})
+	}
+}
+
+func TestNewMoney(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "20.30", currencyV1.NewMoney(2030, "GBP").MajorUnits)
+	assert.Empty(t, currencyV1.NewMoney(2030, "XYZ").MajorUnits)
+}
@@ -0,0 +1,51 @@
+// Command ledgercheck proves the ledger reconciles with the payment actions it records, exiting non-zero should any
+// payment's balances not.
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/jacktantram/payments-api/pkg/driver/v1/config"
+	"github.com/jacktantram/payments-api/pkg/driver/v1/postgres"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/ledger"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/store"
+	log "github.com/sirupsen/logrus"
+)
+
+// Cfg represents the command's config
+type Cfg struct {
+	DatabaseURI string `envconfig:"DATABASE_URI"`
+}
+
+func main() {
+	cfg := &Cfg{}
+
+	if err := config.LoadConfig(cfg); err != nil {
+		log.WithError(err).Fatalf("unable to load config")
+	}
+
+	client, err := postgres.NewClient(cfg.DatabaseURI, "postgres")
+	if err != nil {
+		log.WithError(err).Fatal("failed to setup postgres client")
+	}
+
+	report, err := ledger.NewChecker(store.NewStore(client)).Check(context.Background())
+	client.DB.Close()
+	if err != nil {
+		log.WithError(err).Fatal("unable to check ledger")
+	}
+	for _, discrepancy := range report.Discrepancies {
+		log.WithField("payment.id", discrepancy.PaymentID).
+			WithField("expected", discrepancy.Expected).
+			WithField("actual", discrepancy.Actual).
+			WithError(discrepancy.Err).
+			Error("ledger does not reconcile")
+	}
+	log.WithField("checked", report.Checked).
+		WithField("discrepancies", len(report.Discrepancies)).
+		Info("ledger checked")
+	if len(report.Discrepancies) != 0 {
+		os.Exit(1)
+	}
+}
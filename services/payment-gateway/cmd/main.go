@@ -2,17 +2,28 @@ package main
 
 import (
 	"context"
-	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"expvar"
+	gatewayV1 "github.com/jacktantram/payments-api/build/go/services/paymentgateway/v1"
 	"github.com/jacktantram/payments-api/pkg/driver/v1/config"
 	"github.com/jacktantram/payments-api/pkg/driver/v1/postgres"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/fx"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/gateway"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/merchant"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/outbox"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/resilience"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/routing"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/simulator"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/store"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transportgrpc"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
-	"github.com/pkg/errors"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/vault"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/webhook"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"net"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -21,6 +32,47 @@ type Cfg struct {
 	config.HTTPConfig
 	DatabaseURI   string `envconfig:"DATABASE_URI"`
 	MigrationPath string `envconfig:"MIGRATION_PATH" default:"/migrations"`
+	// AsyncAuthorization returns authorizations as pending with the issuer being called in the background.
+	AsyncAuthorization   bool `yaml:"async_authorization,omitempty" envconfig:"ASYNC_AUTHORIZATION" default:"false"`
+	AuthorizationWorkers int  `yaml:"authorization_workers,omitempty" envconfig:"AUTHORIZATION_WORKERS" default:"4"`
+	// RecoveryThreshold is how long in seconds a payment action is left without an outcome before it is recovered.
+	RecoveryThreshold int `yaml:"recovery_threshold,omitempty" envconfig:"RECOVERY_THRESHOLD" default:"300"`
+	// RecoveryInterval is in seconds
+	RecoveryInterval int `yaml:"recovery_interval,omitempty" envconfig:"RECOVERY_INTERVAL" default:"60"`
+	// AuthorizationExpiry is how long authorizations are held for per card brand, 7 days if unset.
+	AuthorizationExpiry domain.AuthorizationExpiry `yaml:"authorization_expiry" ignored:"true"`
+	// ExpiryInterval is in seconds
+	ExpiryInterval int `yaml:"expiry_interval,omitempty" envconfig:"EXPIRY_INTERVAL" default:"300"`
+	// OutboxFilePath is where the relay publishes payment events to.
+	OutboxFilePath string `envconfig:"OUTBOX_FILE_PATH" default:"payment-events.jsonl"`
+	// OutboxRelayInterval is in milliseconds
+	OutboxRelayInterval int `yaml:"outbox_relay_interval,omitempty" envconfig:"OUTBOX_RELAY_INTERVAL" default:"1000"`
+	// WebhookDispatchInterval is in milliseconds
+	WebhookDispatchInterval int `yaml:"webhook_dispatch_interval,omitempty" envconfig:"WEBHOOK_DISPATCH_INTERVAL" default:"1000"`
+	// WebhookMaxAttempts is how many times a webhook delivery is attempted before it is marked as failed.
+	WebhookMaxAttempts int `yaml:"webhook_max_attempts,omitempty" envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	// GRPCAddr is the address the gRPC server listens on alongside HTTP.
+	GRPCAddr string `yaml:"grpc_addr,omitempty" envconfig:"GRPC_ADDR" default:":9090"`
+	// AdminAPIKey authenticates the admin endpoints managing merchants, they are disabled if unset.
+	AdminAPIKey string `envconfig:"ADMIN_API_KEY"`
+	// VaultKeyFile holds the vault's hex encoded master key, it is generated if missing.
+	VaultKeyFile string `envconfig:"VAULT_KEY_FILE" default:"vault.key"`
+	// SimulatorScenarioFile is the YAML scenario the simulated acquirers respond by, the default scenario is used if unset.
+	SimulatorScenarioFile string `yaml:"simulator_scenario_file,omitempty" envconfig:"SIMULATOR_SCENARIO_FILE"`
+	// FXRatesFile is the YAML file of FX rates payments are converted to their settlement currency by, payments can only
+	// settle in their own currency if unset.
+	FXRatesFile string `yaml:"fx_rates_file,omitempty" envconfig:"FX_RATES_FILE"`
+	// Routing configures the acquirers issuer requests are routed between, a single simulated acquirer is used if unset.
+	Routing routing.Config `yaml:"routing" ignored:"true"`
+	// IssuerCreateTimeout and IssuerStatusTimeout are in milliseconds
+	IssuerCreateTimeout int `yaml:"issuer_create_timeout,omitempty" envconfig:"ISSUER_CREATE_TIMEOUT" default:"10000"`
+	IssuerStatusTimeout int `yaml:"issuer_status_timeout,omitempty" envconfig:"ISSUER_STATUS_TIMEOUT" default:"5000"`
+	// IssuerStatusAttempts is how many times a status inquiry is made before giving up.
+	IssuerStatusAttempts int `yaml:"issuer_status_attempts,omitempty" envconfig:"ISSUER_STATUS_ATTEMPTS" default:"3"`
+	// BreakerFailureThreshold is how many consecutive failures open an acquirer's circuit breaker.
+	BreakerFailureThreshold int `yaml:"breaker_failure_threshold,omitempty" envconfig:"BREAKER_FAILURE_THRESHOLD" default:"5"`
+	// BreakerOpenDuration is in seconds
+	BreakerOpenDuration int `yaml:"breaker_open_duration,omitempty" envconfig:"BREAKER_OPEN_DURATION" default:"30"`
 }
 
 func main() {
@@ -40,13 +92,111 @@ func main() {
 		log.WithError(err).Fatalf("unable to migrate")
 	}
 
-	h, err := transporthttp.NewHandler(gateway.NewService(store.NewStore(client), FakeGateway{}))
+	paymentStore := store.NewStore(client)
+
+	publisher, err := outbox.NewFilePublisher(cfg.OutboxFilePath)
+	if err != nil {
+		log.WithError(err).Fatal("unable to setup outbox publisher")
+	}
+	defer publisher.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// webhook deliveries are queued within the relay's transaction so that each event is queued exactly once
+	relay := outbox.NewRelay(paymentStore, outbox.MultiPublisher{webhook.NewPublisher(paymentStore), publisher},
+		outbox.DefaultBatchSize, time.Duration(cfg.OutboxRelayInterval)*time.Millisecond)
+	go relay.Run(ctx)
+	dispatcher := webhook.NewDispatcher(paymentStore, time.Duration(cfg.WebhookDispatchInterval)*time.Millisecond,
+		webhook.WithRetries(cfg.WebhookMaxAttempts, webhook.DefaultBackoff))
+	go dispatcher.Run(ctx)
+
+	keys, err := vault.NewFileKeyManager(cfg.VaultKeyFile)
+	if err != nil {
+		log.WithError(err).Fatal("unable to setup vault keys")
+	}
+	cardVault := vault.NewVault(paymentStore, keys)
+
+	scenario := simulator.DefaultScenario()
+	if cfg.SimulatorScenarioFile != "" {
+		if scenario, err = simulator.LoadScenario(cfg.SimulatorScenarioFile); err != nil {
+			log.WithError(err).Fatal("unable to load simulator scenario")
+		}
+	}
+	if len(cfg.Routing.Acquirers) == 0 {
+		cfg.Routing = routing.Config{Acquirers: []string{"simulator"}, DefaultAcquirer: "simulator"}
+	}
+	acquirers := make(map[string]routing.IssuerGateway, len(cfg.Routing.Acquirers))
+	for _, name := range cfg.Routing.Acquirers {
+		issuer, err := simulator.New(scenario)
+		if err != nil {
+			log.WithError(err).Fatal("unable to setup simulator")
+		}
+		acquirers[name] = resilience.NewGateway(name, issuer,
+			resilience.WithTimeouts(time.Duration(cfg.IssuerCreateTimeout)*time.Millisecond, time.Duration(cfg.IssuerStatusTimeout)*time.Millisecond),
+			resilience.WithStatusRetries(cfg.IssuerStatusAttempts, resilience.DefaultRetryBackoff),
+			resilience.WithBreaker(cfg.BreakerFailureThreshold, time.Duration(cfg.BreakerOpenDuration)*time.Second))
+	}
+	router, err := routing.NewRouter(acquirers, cfg.Routing.DefaultAcquirer, cfg.Routing.Rules...)
+	if err != nil {
+		log.WithError(err).Fatal("unable to setup acquirer routing")
+	}
+
+	opts := []gateway.Option{gateway.WithAuthorizationExpiry(cfg.AuthorizationExpiry)}
+	if cfg.AsyncAuthorization {
+		opts = append(opts, gateway.WithAsyncAuthorization())
+	}
+	if cfg.FXRatesFile != "" {
+		rates, err := fx.LoadFileProvider(cfg.FXRatesFile)
+		if err != nil {
+			log.WithError(err).Fatal("unable to load fx rates")
+		}
+		opts = append(opts, gateway.WithRateProvider(rates))
+	}
+	service := gateway.NewService(paymentStore, router, cardVault, opts...)
+	if cfg.AsyncAuthorization {
+		go worker.NewAuthorizationPool(service, cfg.AuthorizationWorkers, worker.DefaultInterval).Run(ctx)
+	}
+	sweeper := worker.NewRecoverySweeper(service, time.Duration(cfg.RecoveryThreshold)*time.Second, time.Duration(cfg.RecoveryInterval)*time.Second)
+	go sweeper.Run(ctx)
+	go worker.NewExpirySweeper(service, time.Duration(cfg.ExpiryInterval)*time.Second).Run(ctx)
+
+	h, err := transporthttp.NewHandler(service)
 	if err != nil {
 		log.WithError(err).Fatalf("unable to setup transporthttp")
 	}
+	merchants := merchant.NewService(paymentStore)
+	merchantHandler, err := transporthttp.NewMerchantHandler(merchants, cfg.AdminAPIKey)
+	if err != nil {
+		log.WithError(err).Fatalf("unable to setup transporthttp")
+	}
+	webhookHandler, err := transporthttp.NewWebhookHandler(webhook.NewService(paymentStore))
+	if err != nil {
+		log.WithError(err).Fatalf("unable to setup transporthttp")
+	}
+
+	grpcServer, err := transportgrpc.NewServer(service)
+	if err != nil {
+		log.WithError(err).Fatalf("unable to setup transportgrpc")
+	}
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.WithError(err).Fatalf("unable to listen on %s", cfg.GRPCAddr)
+	}
+	grpcSrv := grpc.NewServer(grpc.UnaryInterceptor(transportgrpc.AuthInterceptor(merchants)))
+	gatewayV1.RegisterPaymentGatewayServiceServer(grpcSrv, grpcServer)
+	go func() {
+		if err := grpcSrv.Serve(lis); err != nil {
+			log.WithError(err).Fatal("unable to serve grpc")
+		}
+	}()
+	defer grpcSrv.GracefulStop()
+
+	routes := transporthttp.HandleRoutes(h, merchantHandler, webhookHandler, paymentStore)
+	// exposes the acquirers' circuit breakers amongst other metrics, only to the admin as it includes the command line
+	routes.Handle("/debug/vars", merchantHandler.AdminMiddleware(expvar.Handler())).Methods(http.MethodGet)
 
 	srv := &http.Server{
-		Handler:      transporthttp.HandleRoutes(h),
+		Handler:      routes,
 		Addr:         ":8080",
 		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
 		ReadTimeout:  time.Duration(cfg.ReadTimeout) * time.Second,
@@ -57,25 +207,3 @@ func main() {
 	}
 
 }
-
-type FakeGateway struct {
-}
-
-var bannedCards = map[string]paymentsV1.PaymentType{
-	"4000000000000119": paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
-	"4000000000000259": paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
-	"4000000000003238": paymentsV1.PaymentType_PAYMENT_TYPE_REFUND,
-}
-
-func (f FakeGateway) CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
-	if issuerRequest.PaymentMethod.Card == nil {
-		return domain.IssuerResponse{}, errors.New("unsupported method")
-	}
-	cardNumber := strings.ReplaceAll(issuerRequest.PaymentMethod.Card.CardNumber, " ", "")
-	if methodType, ok := bannedCards[cardNumber]; ok {
-		if methodType == issuerRequest.OperationType {
-			return domain.IssuerResponse{AuthCode: "12"}, nil
-		}
-	}
-	return domain.IssuerResponse{AuthCode: "00"}, nil
-}
@@ -1,17 +1,26 @@
 package domain
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 	"unicode"
 )
 
+const (
+	CardNumberMinLen = 13
+	CardNumberMaxLen = 19
+)
+
+// ErrCardBrandNotAccepted is returned when paying with a card of a brand the merchant does not accept.
+var ErrCardBrandNotAccepted = errors.New("card brand not accepted")
+
 // ValidCardNumber Determines whether or not the card number is valid
-// as per luhn algorithm
+// as per luhn algorithm, card numbers are between 13 and 19 digits long
 // https://en.wikipedia.org/wiki/Luhn_algorithm
 func ValidCardNumber(number string) bool {
 	number = strings.ReplaceAll(number, " ", "")
-	if len(number) > 16 {
+	if len(number) < CardNumberMinLen || len(number) > CardNumberMaxLen {
 		return false
 	}
 	digits := make([]int, len(number))
@@ -39,3 +48,128 @@ func ValidCardNumber(number string) bool {
 	}
 	return sum%10 == 0
 }
+
+// CardBrand is the card scheme a card belongs to.
+type CardBrand string
+
+const (
+	CardBrandUnknown    CardBrand = "unknown"
+	CardBrandVisa       CardBrand = "visa"
+	CardBrandMastercard CardBrand = "mastercard"
+	CardBrandAmex       CardBrand = "amex"
+	CardBrandDiscover   CardBrand = "discover"
+	CardBrandJCB        CardBrand = "jcb"
+	CardBrandUnionPay   CardBrand = "unionpay"
+	CardBrandMaestro    CardBrand = "maestro"
+	CardBrandDiners     CardBrand = "diners"
+)
+
+// cardBrandSpec is how the cards of a brand are numbered.
+type cardBrandSpec struct {
+	// minLen and maxLen bound the length of the card number.
+	minLen, maxLen int
+	cvvLen         int
+}
+
+// cardBrandSpecs are the brands cards are detected as, unknown brands may be any length a card number can be.
+var cardBrandSpecs = map[CardBrand]cardBrandSpec{
+	CardBrandUnknown:    {minLen: CardNumberMinLen, maxLen: CardNumberMaxLen, cvvLen: 3},
+	CardBrandVisa:       {minLen: 13, maxLen: 19, cvvLen: 3},
+	CardBrandMastercard: {minLen: 16, maxLen: 16, cvvLen: 3},
+	CardBrandAmex:       {minLen: 15, maxLen: 15, cvvLen: 4},
+	CardBrandDiscover:   {minLen: 16, maxLen: 19, cvvLen: 3},
+	CardBrandJCB:        {minLen: 16, maxLen: 19, cvvLen: 3},
+	CardBrandUnionPay:   {minLen: 16, maxLen: 19, cvvLen: 3},
+	CardBrandMaestro:    {minLen: CardNumberMinLen, maxLen: 19, cvvLen: 3},
+	CardBrandDiners:     {minLen: 14, maxLen: 19, cvvLen: 3},
+}
+
+// binRange is an inclusive range of the leading digits of card numbers issued under a brand, low and high are of
+// the same length.
+type binRange struct {
+	low, high string
+	brand     CardBrand
+}
+
+// binRanges is the BIN/IIN range table cards are detected by. Ranges may overlap, the longest range matching a card
+// number wins so that co-branded ranges such as Discover's within UnionPay's are detected.
+var binRanges = []binRange{
+	{low: "4", high: "4", brand: CardBrandVisa},
+	{low: "51", high: "55", brand: CardBrandMastercard},
+	{low: "2221", high: "2720", brand: CardBrandMastercard},
+	{low: "34", high: "34", brand: CardBrandAmex},
+	{low: "37", high: "37", brand: CardBrandAmex},
+	{low: "6011", high: "6011", brand: CardBrandDiscover},
+	{low: "644", high: "649", brand: CardBrandDiscover},
+	{low: "65", high: "65", brand: CardBrandDiscover},
+	{low: "622126", high: "622925", brand: CardBrandDiscover},
+	{low: "3528", high: "3589", brand: CardBrandJCB},
+	{low: "62", high: "62", brand: CardBrandUnionPay},
+	{low: "5018", high: "5018", brand: CardBrandMaestro},
+	{low: "5020", high: "5020", brand: CardBrandMaestro},
+	{low: "5038", high: "5038", brand: CardBrandMaestro},
+	{low: "56", high: "58", brand: CardBrandMaestro},
+	{low: "6304", high: "6304", brand: CardBrandMaestro},
+	{low: "6759", high: "6759", brand: CardBrandMaestro},
+	{low: "6761", high: "6763", brand: CardBrandMaestro},
+	{low: "300", high: "305", brand: CardBrandDiners},
+	{low: "3095", high: "3095", brand: CardBrandDiners},
+	{low: "36", high: "36", brand: CardBrandDiners},
+	{low: "38", high: "39", brand: CardBrandDiners},
+}
+
+// CardBrands are the brands cards are detected as.
+var CardBrands = []CardBrand{
+	CardBrandVisa, CardBrandMastercard, CardBrandAmex, CardBrandDiscover,
+	CardBrandJCB, CardBrandUnionPay, CardBrandMaestro, CardBrandDiners,
+}
+
+// Known reports whether cards are detected as the brand.
+func (b CardBrand) Known() bool {
+	_, ok := cardBrandSpecs[b]
+	return ok && b != CardBrandUnknown
+}
+
+// ValidLen reports whether card numbers of the brand can be of the length.
+func (b CardBrand) ValidLen(length int) bool {
+	spec, ok := cardBrandSpecs[b]
+	if !ok {
+		spec = cardBrandSpecs[CardBrandUnknown]
+	}
+	return length >= spec.minLen && length <= spec.maxLen
+}
+
+// CVVLen returns the length of the security code printed on cards of the brand.
+func (b CardBrand) CVVLen() int {
+	spec, ok := cardBrandSpecs[b]
+	if !ok {
+		spec = cardBrandSpecs[CardBrandUnknown]
+	}
+	return spec.cvvLen
+}
+
+// CardBrandFromBIN returns the brand of the card from the leading digits of its card number.
+func CardBrandFromBIN(bin string) CardBrand {
+	bin = strings.ReplaceAll(bin, " ", "")
+	brand, matched := CardBrandUnknown, 0
+	for _, r := range binRanges {
+		n := len(r.low)
+		if n <= matched || len(bin) < n {
+			continue
+		}
+		if prefix := bin[:n]; prefix >= r.low && prefix <= r.high && IsDigits(prefix) {
+			brand, matched = r.brand, n
+		}
+	}
+	return brand
+}
+
+// IsDigits reports whether the string is made up only of the digits 0-9, an empty string is.
+func IsDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
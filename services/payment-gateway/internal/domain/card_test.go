@@ -17,6 +17,10 @@ func TestValidateCardNumber(t *testing.T) {
 		{cardNumber: "4603111093880019", bool: true},
 		{cardNumber: "5555555555554444", bool: true},
 		{cardNumber: "4000 0000 0000 0119", bool: true},
+		{cardNumber: "4000000000000000006", bool: true},
+		{cardNumber: "30569309025904", bool: true},
+		{cardNumber: "40000000000000000006", bool: false},
+		{cardNumber: "422222222222", bool: false},
 		{cardNumber: "1111111111111111", bool: false},
 		{cardNumber: "1841835786578528", bool: false},
 		{cardNumber: "1841 8357 8657 8528", bool: false},
@@ -28,3 +32,74 @@ func TestValidateCardNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestCardBrandFromBIN(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		bin   string
+		brand domain.CardBrand
+	}{
+		{bin: "460311", brand: domain.CardBrandVisa},
+		{bin: "555555", brand: domain.CardBrandMastercard},
+		{bin: "222100", brand: domain.CardBrandMastercard},
+		{bin: "272099", brand: domain.CardBrandMastercard},
+		{bin: "272100", brand: domain.CardBrandUnknown},
+		{bin: "378282", brand: domain.CardBrandAmex},
+		{bin: "601111", brand: domain.CardBrandDiscover},
+		{bin: "644000", brand: domain.CardBrandDiscover},
+		{bin: "622126", brand: domain.CardBrandDiscover},
+		{bin: "622925", brand: domain.CardBrandDiscover},
+		{bin: "622926", brand: domain.CardBrandUnionPay},
+		{bin: "620000", brand: domain.CardBrandUnionPay},
+		{bin: "353011", brand: domain.CardBrandJCB},
+		{bin: "358999", brand: domain.CardBrandJCB},
+		{bin: "675964", brand: domain.CardBrandMaestro},
+		{bin: "501800", brand: domain.CardBrandMaestro},
+		{bin: "305693", brand: domain.CardBrandDiners},
+		{bin: "362272", brand: domain.CardBrandDiners},
+		{bin: "4000 0000 0000 0119", brand: domain.CardBrandVisa},
+		{bin: "111111", brand: domain.CardBrandUnknown},
+		{bin: "", brand: domain.CardBrandUnknown},
+	} {
+		tc := tc
+		t.Run(tc.bin, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.brand, domain.CardBrandFromBIN(tc.bin))
+		})
+	}
+}
+
+func TestCardBrand_ValidLen(t *testing.T) {
+	t.Parallel()
+	assert.True(t, domain.CardBrandVisa.ValidLen(13))
+	assert.True(t, domain.CardBrandVisa.ValidLen(19))
+	assert.True(t, domain.CardBrandAmex.ValidLen(15))
+	assert.False(t, domain.CardBrandAmex.ValidLen(16))
+	assert.False(t, domain.CardBrandMastercard.ValidLen(19))
+	assert.True(t, domain.CardBrandUnknown.ValidLen(19))
+	assert.False(t, domain.CardBrand("other").ValidLen(20))
+}
+
+func TestCardBrand_CVVLen(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 4, domain.CardBrandAmex.CVVLen())
+	assert.Equal(t, 3, domain.CardBrandVisa.CVVLen())
+	assert.Equal(t, 3, domain.CardBrandUnknown.CVVLen())
+}
+
+func TestMerchant_Accepts(t *testing.T) {
+	t.Parallel()
+	assert.True(t, domain.Merchant{}.Accepts(domain.CardBrandAmex))
+	merchant := domain.Merchant{AcceptedCardBrands: []domain.CardBrand{domain.CardBrandVisa}}
+	assert.True(t, merchant.Accepts(domain.CardBrandVisa))
+	assert.False(t, merchant.Accepts(domain.CardBrandAmex))
+}
+
+func TestIsDigits(t *testing.T) {
+	t.Parallel()
+	assert.True(t, domain.IsDigits("0119"))
+	assert.True(t, domain.IsDigits(""))
+	assert.False(t, domain.IsDigits("12a4"))
+	assert.False(t, domain.IsDigits("-1"))
+	assert.False(t, domain.IsDigits("١٢٣"))
+}
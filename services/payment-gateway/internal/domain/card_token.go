@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+var ErrNoCardToken = errors.New("no card token found")
+
+// CardToken is a card held in the vault. The card number is encrypted with a data key unique to the card, which is
+// itself encrypted by the vault's master key. The CVV is never stored.
+type CardToken struct {
+	Token            string    `db:"token"`
+	EncryptedPAN     []byte    `db:"encrypted_pan"`
+	EncryptedDataKey []byte    `db:"encrypted_data_key"`
+	KeyID            string    `db:"key_id"`
+	Bin              string    `db:"bin"`
+	LastFour         string    `db:"last_four"`
+	ExpiryMonth      int       `db:"expiry_month"`
+	ExpiryYear       int       `db:"expiry_year"`
+	CreatedAt        time.Time `db:"created_at"`
+	// MerchantID is the merchant the card was tokenized for, the token cannot be used by any other.
+	MerchantID uuid.NullUUID `db:"merchant_id"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"database/sql"
+	uuid "github.com/kevinburke/go.uuid"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+// OutboxEvent is a payment lifecycle event written to the outbox within the same
+// transaction as the state change it describes, to later be relayed to a publisher.
+type OutboxEvent struct {
+	ID          uuid.UUID    `db:"id"`
+	PaymentID   uuid.UUID    `db:"payment_id"`
+	EventType   string       `db:"event_type"`
+	Payload     []byte       `db:"payload"`
+	CreatedAt   time.Time    `db:"created_at"`
+	PublishedAt sql.NullTime `db:"published_at"`
+	// Attempts counts the failed attempts to publish the event, the next being made no sooner than NextAttemptAt.
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	// FailedAt is set once the event runs out of attempts, it is then no longer published.
+	FailedAt sql.NullTime `db:"failed_at"`
+}
+
+// NewOutboxEvent serializes the event for the payment. The event type is the full name of the proto message.
+func NewOutboxEvent(paymentID string, event proto.Message) (*OutboxEvent, error) {
+	// marshal a clone as marshalling caches sizes within messages that may be shared with the caller
+	payload, err := proto.Marshal(proto.Clone(event))
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		PaymentID: uuid.FromStringOrNil(paymentID),
+		EventType: string(event.ProtoReflect().Descriptor().FullName()),
+		Payload:   payload,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// DefaultAuthorizationExpiry is how long an authorization is held for when its card brand has no expiry configured.
+const DefaultAuthorizationExpiry = 7 * 24 * time.Hour
+
+// AuthorizationExpiry configures how long authorizations are held for before they expire, schemes differ in how long
+// they hold funds for so it is configured per card brand.
+type AuthorizationExpiry struct {
+	// Default applies to brands without an expiry of their own, DefaultAuthorizationExpiry is used if unset.
+	Default time.Duration               `yaml:"default"`
+	Brands  map[CardBrand]time.Duration `yaml:"brands"`
+}
+
+// ExpiresAt returns when an authorization made at the time against a card of the brand expires.
+func (e AuthorizationExpiry) ExpiresAt(brand CardBrand, authorizedAt time.Time) time.Time {
+	expiry, ok := e.Brands[brand]
+	if !ok {
+		expiry = e.Default
+	}
+	if expiry <= 0 {
+		expiry = DefaultAuthorizationExpiry
+	}
+	return authorizedAt.Add(expiry)
+}
@@ -0,0 +1,24 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationExpiry_ExpiresAt(t *testing.T) {
+	t.Parallel()
+
+	authorizedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiry := domain.AuthorizationExpiry{
+		Default: 24 * time.Hour,
+		Brands:  map[domain.CardBrand]time.Duration{domain.CardBrandMastercard: 30 * 24 * time.Hour},
+	}
+
+	assert.Equal(t, authorizedAt.Add(30*24*time.Hour), expiry.ExpiresAt(domain.CardBrandMastercard, authorizedAt))
+	assert.Equal(t, authorizedAt.Add(24*time.Hour), expiry.ExpiresAt(domain.CardBrandVisa, authorizedAt))
+	assert.Equal(t, authorizedAt.Add(domain.DefaultAuthorizationExpiry),
+		domain.AuthorizationExpiry{}.ExpiresAt(domain.CardBrandVisa, authorizedAt))
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
+)
+
+var (
+	ErrNoFXRate = errors.New("no fx rate")
+
+	ErrInvalidFXRate = errors.New("invalid fx rate")
+)
+
+// FXRate converts amounts in the Base currency to the Quote currency.
+type FXRate struct {
+	Base  string
+	Quote string
+	// Rate is the decimal amount of the quote currency bought by one unit of the base currency, i.e. "0.8563".
+	Rate string
+}
+
+// Convert converts the amount in the base currency's minor units to the quote currency's minor units, accounting
+// for the currencies' exponents and rounding half up.
+func (r FXRate) Convert(minorUnits uint64) (uint64, error) {
+	rate, ok := new(big.Rat).SetString(r.Rate)
+	if !ok || rate.Sign() <= 0 {
+		return 0, ErrInvalidFXRate
+	}
+	base, err := currencyV1.Lookup(r.Base)
+	if err != nil {
+		return 0, err
+	}
+	quote, err := currencyV1.Lookup(r.Quote)
+	if err != nil {
+		return 0, err
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetUint64(minorUnits), rate)
+	exponent := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(quote.Exponent-base.Exponent))), nil)
+	if quote.Exponent > base.Exponent {
+		converted.Mul(converted, new(big.Rat).SetInt(exponent))
+	} else {
+		converted.Quo(converted, new(big.Rat).SetInt(exponent))
+	}
+
+	// rounds half up by flooring (2n + d) / 2d
+	num := new(big.Int).Add(new(big.Int).Mul(converted.Num(), big.NewInt(2)), converted.Denom())
+	rounded := num.Quo(num, new(big.Int).Mul(converted.Denom(), big.NewInt(2)))
+	if !rounded.IsUint64() {
+		return 0, fmt.Errorf("converted amount %s overflows", rounded)
+	}
+	return rounded.Uint64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,79 @@
+package domain_test
+
+import (
+	"testing"
+
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFXRate_Convert(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		rate        domain.FXRate
+		minorUnits  uint64
+		exp         uint64
+		expErr      error
+	}{
+		{
+			description: "should convert between currencies with the same exponent",
+			rate:        domain.FXRate{Base: "EUR", Quote: "GBP", Rate: "0.8563"},
+			minorUnits:  10000,
+			exp:         8563,
+		},
+		{
+			description: "should round half up",
+			rate:        domain.FXRate{Base: "EUR", Quote: "GBP", Rate: "0.5"},
+			minorUnits:  1,
+			exp:         1,
+		},
+		{
+			description: "should round down below half",
+			rate:        domain.FXRate{Base: "EUR", Quote: "GBP", Rate: "0.49"},
+			minorUnits:  1,
+			exp:         0,
+		},
+		{
+			description: "should convert to a currency with a smaller exponent",
+			rate:        domain.FXRate{Base: "GBP", Quote: "JPY", Rate: "191.25"},
+			minorUnits:  1050,
+			exp:         2008,
+		},
+		{
+			description: "should convert to a currency with a larger exponent",
+			rate:        domain.FXRate{Base: "GBP", Quote: "KWD", Rate: "0.3891"},
+			minorUnits:  1000,
+			exp:         3891,
+		},
+		{
+			description: "should return error given an invalid rate",
+			rate:        domain.FXRate{Base: "EUR", Quote: "GBP", Rate: "abc"},
+			minorUnits:  1000,
+			expErr:      domain.ErrInvalidFXRate,
+		},
+		{
+			description: "should return error given a negative rate",
+			rate:        domain.FXRate{Base: "EUR", Quote: "GBP", Rate: "-1"},
+			minorUnits:  1000,
+			expErr:      domain.ErrInvalidFXRate,
+		},
+		{
+			description: "should return error given an unknown currency",
+			rate:        domain.FXRate{Base: "EUR", Quote: "XYZ", Rate: "1"},
+			minorUnits:  1000,
+			expErr:      currencyV1.ErrUnknownCurrency,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			converted, err := tc.rate.Convert(tc.minorUnits)
+			require.Equal(t, tc.expErr, err)
+			assert.Equal(t, tc.exp, converted)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+var (
+	ErrIdempotencyKeyExists   = errors.New("idempotency key already exists")
+	ErrNoIdempotencyKey       = errors.New("no idempotency key found")
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key used with a different request")
+)
+
+// IdempotencyKey represents a client supplied Idempotency-Key along with
+// the fingerprint of the request that first used it and the response returned.
+// A key with no CompletedAt is still being processed.
+type IdempotencyKey struct {
+	Key            string        `db:"key"`
+	RequestPath    string        `db:"request_path"`
+	Fingerprint    string        `db:"fingerprint"`
+	ResponseStatus sql.NullInt32 `db:"response_status"`
+	ResponseBody   []byte        `db:"response_body"`
+	CreatedAt      time.Time     `db:"created_at"`
+	CompletedAt    sql.NullTime  `db:"completed_at"`
+	// MerchantID is the merchant that sent the key, keys are unique per merchant.
+	MerchantID uuid.NullUUID `db:"merchant_id"`
+}
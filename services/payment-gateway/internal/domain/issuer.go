@@ -1,16 +1,38 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+
 	v1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
 	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
 )
 
+// IssuerResponseCodeNoRecord is recorded against a payment action the issuer has no record of, the request never
+// reached the issuer so is treated as failed.
+const IssuerResponseCodeNoRecord = "25"
+
+var (
+	ErrIssuerRequestNotFound = errors.New("issuer has no record of the request")
+	// ErrIssuerUnavailable is returned when the issuer could not be reached in time or its circuit breaker is open.
+	ErrIssuerUnavailable = errors.New("issuer unavailable")
+	// ErrCircuitOpen is returned when a request is rejected without being sent as the issuer's circuit breaker is
+	// open, it is an ErrIssuerUnavailable.
+	ErrCircuitOpen = fmt.Errorf("%w: circuit breaker open", ErrIssuerUnavailable)
+)
+
 type IssuerRequest struct {
+	// Reference identifies the request to the issuer, it is the id of the payment action being made.
+	Reference     string
 	Amount        *v1.Money
 	OperationType paymentsV1.PaymentType
 	PaymentMethod PaymentMethod
+	// Acquirer pins the request to an acquirer, when empty the request is routed to one.
+	Acquirer string
 }
 
 type IssuerResponse struct {
 	AuthCode string
+	// Acquirer is the acquirer that handled the request.
+	Acquirer string
 }
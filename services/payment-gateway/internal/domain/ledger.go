@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+// LedgerAccount is one of the accounts every payment has in the double-entry ledger.
+type LedgerAccount string
+
+const (
+	// LedgerAccountCustomer is the customer's side of the hold on their card, credited when funds are authorized and
+	// debited as the hold is released by captures, voids, reversals and expiry.
+	LedgerAccountCustomer LedgerAccount = "CUSTOMER"
+	// LedgerAccountAuthorized is the amount held on the customer's card that is yet to be captured or released.
+	LedgerAccountAuthorized LedgerAccount = "AUTHORIZED"
+	// LedgerAccountCaptured is the total amount captured from the customer.
+	LedgerAccountCaptured LedgerAccount = "CAPTURED"
+	// LedgerAccountRefunded is the total amount refunded to the customer.
+	LedgerAccountRefunded LedgerAccount = "REFUNDED"
+	// LedgerAccountMerchantPayable is the amount owed to the merchant, that captured less that refunded.
+	LedgerAccountMerchantPayable LedgerAccount = "MERCHANT_PAYABLE"
+)
+
+// LedgerEntry moves an amount between two of a payment's accounts, debiting one and crediting the other so that the
+// ledger always balances. Entries are written in the same transaction as the payment action they record.
+type LedgerEntry struct {
+	ID              uuid.UUID     `db:"id"`
+	PaymentID       uuid.UUID     `db:"payment_id"`
+	PaymentActionID uuid.NullUUID `db:"payment_action_id"`
+	DebitAccount    LedgerAccount `db:"debit_account"`
+	CreditAccount   LedgerAccount `db:"credit_account"`
+	Amount          uint64        `db:"amount"`
+	Currency        string        `db:"currency"`
+	CreatedAt       time.Time     `db:"created_at"`
+}
+
+// NewLedgerEntries returns the entries recording a successful payment action of the amount, none are returned for a
+// payment type that moves no funds or a zero amount.
+//
+// Authorizations hold the customer's funds and voids and reversals release them. A capture releases the captured
+// amount from the hold and makes it payable to the merchant, a refund returns it from the merchant to the customer.
+func NewLedgerEntries(paymentID, paymentActionID string, paymentType PaymentType, amount uint64, currency string) []*LedgerEntry {
+	entry := func(debit, credit LedgerAccount) *LedgerEntry {
+		return &LedgerEntry{
+			PaymentID:       uuid.FromStringOrNil(paymentID),
+			PaymentActionID: nullUUID(paymentActionID),
+			DebitAccount:    debit,
+			CreditAccount:   credit,
+			Amount:          amount,
+			Currency:        currency,
+		}
+	}
+	if amount == 0 {
+		return nil
+	}
+	switch paymentType {
+	case PaymentTypeAuthorization, PaymentTypeIncrementalAuthorization:
+		return []*LedgerEntry{entry(LedgerAccountAuthorized, LedgerAccountCustomer)}
+	case PaymentTypeVoid, PaymentTypeReversal:
+		return []*LedgerEntry{entry(LedgerAccountCustomer, LedgerAccountAuthorized)}
+	case PaymentTypeCapture:
+		return []*LedgerEntry{
+			entry(LedgerAccountCustomer, LedgerAccountAuthorized),
+			entry(LedgerAccountCaptured, LedgerAccountMerchantPayable),
+		}
+	case PaymentTypeRefund:
+		return []*LedgerEntry{entry(LedgerAccountMerchantPayable, LedgerAccountRefunded)}
+	default:
+		return nil
+	}
+}
+
+// NewLedgerRelease returns the entries releasing the amount held on the customer's card without a payment action, as
+// happens when an authorization expires.
+func NewLedgerRelease(paymentID string, amount uint64, currency string) []*LedgerEntry {
+	return NewLedgerEntries(paymentID, "", PaymentTypeVoid, amount, currency)
+}
+
+// HoldReleasedStatuses are the statuses of payments that hold nothing further, whatever their actions left held having
+// been released by the scheme once their authorization expired rather than by an action of their own. An expired
+// authorization has all of its hold released, an expired partial capture is captured with its remainder released.
+// The ledger checker and the backfill of payments made before the ledger (migrations/14_ledger.up.sql) both release
+// the holds of these statuses.
+var HoldReleasedStatuses = []PaymentStatus{PaymentStatusExpired, PaymentStatusCaptured}
+
+// HoldReleased reports whether a payment in the status has had whatever its actions left held released.
+func HoldReleased(status PaymentStatus) bool {
+	for _, released := range HoldReleasedStatuses {
+		if status == released {
+			return true
+		}
+	}
+	return false
+}
+
+func nullUUID(id string) uuid.NullUUID {
+	if id == "" {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: uuid.FromStringOrNil(id), Valid: true}
+}
+
+// LedgerBalances are the balances of a payment's accounts. The customer, refunded and merchant payable accounts are
+// credited as funds are owed through them so their balances are credits less debits, the others debits less credits.
+type LedgerBalances struct {
+	Customer        int64
+	Authorized      int64
+	Captured        int64
+	Refunded        int64
+	MerchantPayable int64
+}
+
+// Post adds the entries to the balances.
+func (b *LedgerBalances) Post(entries ...*LedgerEntry) {
+	for _, entry := range entries {
+		b.Add(entry.DebitAccount, int64(entry.Amount))
+		b.Add(entry.CreditAccount, -int64(entry.Amount))
+	}
+}
+
+// Add adds the net debit, debits less credits, to the account's balance.
+func (b *LedgerBalances) Add(account LedgerAccount, netDebit int64) {
+	switch account {
+	case LedgerAccountCustomer:
+		b.Customer -= netDebit
+	case LedgerAccountAuthorized:
+		b.Authorized += netDebit
+	case LedgerAccountCaptured:
+		b.Captured += netDebit
+	case LedgerAccountRefunded:
+		b.Refunded -= netDebit
+	case LedgerAccountMerchantPayable:
+		b.MerchantPayable -= netDebit
+	}
+}
+
+// Held returns the amount held on the customer's card, zero should the balance have gone negative.
+func (b LedgerBalances) Held() uint64 {
+	return nonNegative(b.Authorized)
+}
+
+// Payable returns the amount owed to the merchant, zero should the balance have gone negative.
+func (b LedgerBalances) Payable() uint64 {
+	return nonNegative(b.MerchantPayable)
+}
+
+// Validate checks the balances are consistent with one another. As every entry debits and credits the same amount the
+// accounts always net to zero, the merchant is owed what was captured less what was refunded and no account is
+// overdrawn.
+func (b LedgerBalances) Validate() error {
+	if b.Authorized+b.Captured-b.Customer-b.Refunded-b.MerchantPayable != 0 {
+		return fmt.Errorf("accounts do not balance: %+v", b)
+	}
+	if b.MerchantPayable != b.Captured-b.Refunded {
+		return fmt.Errorf("merchant payable %d is not captured %d less refunded %d", b.MerchantPayable, b.Captured, b.Refunded)
+	}
+	for _, account := range []struct {
+		name    LedgerAccount
+		balance int64
+	}{
+		{LedgerAccountCustomer, b.Customer},
+		{LedgerAccountAuthorized, b.Authorized},
+		{LedgerAccountCaptured, b.Captured},
+		{LedgerAccountRefunded, b.Refunded},
+		{LedgerAccountMerchantPayable, b.MerchantPayable},
+	} {
+		if account.balance < 0 {
+			return fmt.Errorf("%s account is overdrawn by %d", account.name, -account.balance)
+		}
+	}
+	return nil
+}
+
+func nonNegative(balance int64) uint64 {
+	if balance < 0 {
+		return 0
+	}
+	return uint64(balance)
+}
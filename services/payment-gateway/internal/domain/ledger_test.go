@@ -0,0 +1,84 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerBalances_Post(t *testing.T) {
+	t.Parallel()
+	const (
+		paymentID = "6f1c1bd5-7f1a-4cbd-a1c1-5d8c0b2ee2a4"
+		actionID  = "0c4c8e42-7a0d-4d62-8bb4-2b5cc1f2a0f1"
+	)
+	var balances domain.LedgerBalances
+	for _, action := range []struct {
+		paymentType domain.PaymentType
+		amount      uint64
+	}{
+		{domain.PaymentTypeAuthorization, 1000},
+		{domain.PaymentTypeIncrementalAuthorization, 250},
+		{domain.PaymentTypeCapture, 600},
+		{domain.PaymentTypeReversal, 150},
+		{domain.PaymentTypeRefund, 200},
+	} {
+		entries := domain.NewLedgerEntries(paymentID, actionID, action.paymentType, action.amount, "GBP")
+		for _, entry := range entries {
+			assert.Equal(t, action.amount, entry.Amount)
+			assert.Equal(t, paymentID, entry.PaymentID.String())
+			assert.Equal(t, actionID, entry.PaymentActionID.UUID.String())
+		}
+		balances.Post(entries...)
+	}
+
+	assert.Equal(t, domain.LedgerBalances{
+		Customer:        500,
+		Authorized:      500,
+		Captured:        600,
+		Refunded:        200,
+		MerchantPayable: 400,
+	}, balances)
+	assert.NoError(t, balances.Validate())
+
+	balances.Post(domain.NewLedgerRelease(paymentID, 500, "GBP")...)
+	assert.Equal(t, uint64(0), balances.Held())
+	assert.Equal(t, uint64(400), balances.Payable())
+	assert.NoError(t, balances.Validate())
+	assert.Empty(t, domain.NewLedgerRelease(paymentID, 0, "GBP"))
+}
+
+func TestLedgerBalances_Validate(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		balances    domain.LedgerBalances
+		expErr      string
+	}{
+		{
+			description: "should return error given the accounts do not net to zero",
+			balances:    domain.LedgerBalances{Authorized: 100},
+			expErr:      "accounts do not balance",
+		},
+		{
+			description: "should return error given the merchant is owed more than was captured less refunded",
+			balances:    domain.LedgerBalances{Authorized: 100, Captured: 100, MerchantPayable: 200},
+			expErr:      "merchant payable 200 is not captured 100 less refunded 0",
+		},
+		{
+			description: "should return error given an account is overdrawn",
+			balances:    domain.LedgerBalances{Customer: -100, Authorized: -100},
+			expErr:      "CUSTOMER account is overdrawn by 100",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			err := tc.balances.Validate()
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expErr)
+			}
+		})
+	}
+}
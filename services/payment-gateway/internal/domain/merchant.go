@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+var (
+	ErrNoMerchant = errors.New("no merchant found")
+	ErrNoAPIKey   = errors.New("no api key found")
+)
+
+type Merchant struct {
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	// AcceptedCardBrands are the brands of card the merchant takes payments with, every brand when empty.
+	AcceptedCardBrands []CardBrand `db:"-"`
+}
+
+// Accepts reports whether the merchant accepts cards of the brand, merchants without accepted brands configured
+// accept every brand.
+func (m Merchant) Accepts(brand CardBrand) bool {
+	if len(m.AcceptedCardBrands) == 0 {
+		return true
+	}
+	for _, accepted := range m.AcceptedCardBrands {
+		if accepted == brand {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey authenticates a merchant. Only a hash of the key is stored, the key itself is returned once when created.
+type APIKey struct {
+	ID         uuid.UUID `db:"id"`
+	MerchantID uuid.UUID `db:"merchant_id"`
+	// Prefix is the start of the key, kept to help merchants tell their keys apart.
+	Prefix    string       `db:"prefix"`
+	KeyHash   string       `db:"key_hash"`
+	CreatedAt time.Time    `db:"created_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+}
+
+type merchantKey struct{}
+
+// ContextWithMerchant scopes everything done with the context to the merchant, the store only reads and writes the
+// merchant's own payments.
+func ContextWithMerchant(ctx context.Context, merchantID string) context.Context {
+	return context.WithValue(ctx, merchantKey{}, merchantID)
+}
+
+// MerchantFromContext returns the merchant the context is scoped to. Contexts without a merchant belong to the
+// gateway itself, such as its background workers, and are not scoped.
+func MerchantFromContext(ctx context.Context) (string, bool) {
+	merchantID, ok := ctx.Value(merchantKey{}).(string)
+	return merchantID, ok && merchantID != ""
+}
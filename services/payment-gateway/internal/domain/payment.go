@@ -15,8 +15,15 @@ var (
 
 	ErrUpdatePaymentOutcome = errors.New("unable to update payment outcome")
 
-	ErrNoPayment    = errors.New("no payment found")
-	ErrNotPermitted = errors.New("not permitted")
+	ErrNoPayment       = errors.New("no payment found")
+	ErrNoPaymentAction = errors.New("no payment action found")
+	ErrNotPermitted    = errors.New("not permitted")
+
+	ErrAuthorizationExpired = errors.New("authorization expired")
+
+	// ErrPaymentActionClaimed is returned for a payment action that has already been claimed, such as by another
+	// authorization worker, so is not to be processed again.
+	ErrPaymentActionClaimed = errors.New("payment action already claimed")
 )
 
 type PaymentAction struct {
@@ -27,10 +34,29 @@ type PaymentAction struct {
 	PaymentID    uuid.UUID      `db:"payment_id"`
 	CreatedAt    time.Time      `db:"created_at"`
 	ProcessedAt  sql.NullTime   `db:"processed_at"`
+	// ResponseCategory is the category of the response code at the time it was received.
+	ResponseCategory sql.NullString `db:"response_category"`
+	// Acquirer is the acquirer the action was sent to.
+	Acquirer sql.NullString `db:"acquirer"`
+	// SettlementAmount is the amount converted to the payment's settlement currency, if it has one.
+	SettlementAmount sql.NullInt64 `db:"settlement_amount"`
+	// ClaimedAt is when an authorization worker claimed the action to send it to the issuer, it is only set for
+	// authorizations made asynchronously.
+	ClaimedAt sql.NullTime `db:"claimed_at"`
 }
 
+// ListPaymentActionFilters filters the payment actions returned when listing. Empty fields are not filtered on.
 type ListPaymentActionFilters struct {
-	PaymentIDs []string
+	PaymentIDs   []string
+	PaymentTypes []PaymentType
+	// Unprocessed only returns actions that have no outcome from the issuer.
+	Unprocessed bool
+	// Unclaimed only returns actions that no authorization worker has claimed.
+	Unclaimed bool
+	// CreatedBefore only returns actions sent to the issuer before the time, which is when they were created unless
+	// an authorization worker claimed them later.
+	CreatedBefore time.Time
+	Limit         uint64
 }
 
 type UpdatePaymentActionField int
@@ -40,19 +66,35 @@ const (
 )
 
 type Payment struct {
-	ID         uuid.UUID     `db:"id"`
-	Amount     int64         `db:"amount"`
-	Currency   string        `db:"currency"`
-	Status     PaymentStatus `db:"status"`
-	CardNumber string        `db:"card_number"`
-	CreatedAt  time.Time     `db:"created_at"`
-	UpdatedAt  sql.NullTime  `db:"updated_at"`
+	ID       uuid.UUID     `db:"id"`
+	Amount   int64         `db:"amount"`
+	Currency string        `db:"currency"`
+	Status   PaymentStatus `db:"status"`
+	// CardToken is the vault token of the card, only the card's BIN and last four are held on the payment.
+	CardToken    sql.NullString `db:"card_token"`
+	CardBin      string         `db:"card_bin"`
+	CardLastFour string         `db:"card_last_four"`
+	CardBrand    string         `db:"card_brand"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    sql.NullTime   `db:"updated_at"`
+	// MerchantID is the merchant that owns the payment, payments made before merchants existed have none.
+	MerchantID uuid.NullUUID `db:"merchant_id"`
+	// SettlementCurrency, SettlementAmount and FXRate are only set when the payment settles in a different currency,
+	// the rate is locked when the payment is created.
+	SettlementCurrency sql.NullString `db:"settlement_currency"`
+	SettlementAmount   sql.NullInt64  `db:"settlement_amount"`
+	FXRate             sql.NullString `db:"fx_rate"`
+	// ExpiresAt is when the authorization expires, it is set once the payment is authorized.
+	ExpiresAt sql.NullTime `db:"expires_at"`
+	// Seq numbers payments in the order they were inserted, payments are listed by it.
+	Seq int64 `db:"seq"`
 }
 
 type UpdatePaymentField int
 
 const (
-	UpdatePaymentFieldStatus UpdatePaymentField = 0
+	UpdatePaymentFieldStatus    UpdatePaymentField = 0
+	UpdatePaymentFieldExpiresAt UpdatePaymentField = 1
 )
 
 type PaymentMethod struct {
@@ -70,6 +112,7 @@ const (
 	PaymentStatusPartiallyRefunded PaymentStatus = "PARTIALLY_REFUNDED"
 	PaymentStatusVoided            PaymentStatus = "VOIDED"
 	PaymentStatusDeclined          PaymentStatus = "DECLINED"
+	PaymentStatusExpired           PaymentStatus = "EXPIRED"
 )
 
 func (p *PaymentStatus) FromProto(paymentStatus paymentsV1.PaymentStatus) error {
@@ -90,6 +133,8 @@ func (p *PaymentStatus) FromProto(paymentStatus paymentsV1.PaymentStatus) error
 		*p = PaymentStatusVoided
 	case paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED:
 		*p = PaymentStatusDeclined
+	case paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED:
+		*p = PaymentStatusExpired
 	default:
 		return errors.New("unknown")
 	}
@@ -114,6 +159,8 @@ func (p PaymentStatus) ToProto() paymentsV1.PaymentStatus {
 		return paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED
 	case PaymentStatusDeclined:
 		return paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED
+	case PaymentStatusExpired:
+		return paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED
 	}
 	return paymentsV1.PaymentStatus_PAYMENT_STATUS_UNSPECIFIED
 }
@@ -125,6 +172,10 @@ const (
 	PaymentTypeCapture       PaymentType = "CAPTURE"
 	PaymentTypeRefund        PaymentType = "REFUND"
 	PaymentTypeVoid          PaymentType = "VOID"
+	// PaymentTypeIncrementalAuthorization increases the amount held by an authorization.
+	PaymentTypeIncrementalAuthorization PaymentType = "INCREMENTAL_AUTHORIZATION"
+	// PaymentTypeReversal releases part of the authorized amount that is yet to be captured.
+	PaymentTypeReversal PaymentType = "REVERSAL"
 )
 
 func (p *PaymentType) FromProto(paymentType paymentsV1.PaymentType) error {
@@ -137,6 +188,10 @@ func (p *PaymentType) FromProto(paymentType paymentsV1.PaymentType) error {
 		*p = PaymentTypeRefund
 	case paymentsV1.PaymentType_PAYMENT_TYPE_VOID:
 		*p = PaymentTypeVoid
+	case paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION:
+		*p = PaymentTypeIncrementalAuthorization
+	case paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL:
+		*p = PaymentTypeReversal
 	default:
 		return errors.New("unknown")
 	}
@@ -153,6 +208,10 @@ func (p PaymentType) ToProto() paymentsV1.PaymentType {
 		return paymentsV1.PaymentType_PAYMENT_TYPE_REFUND
 	case PaymentTypeVoid:
 		return paymentsV1.PaymentType_PAYMENT_TYPE_VOID
+	case PaymentTypeIncrementalAuthorization:
+		return paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION
+	case PaymentTypeReversal:
+		return paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL
 	default:
 		return paymentsV1.PaymentType_PAYMENT_TYPE_UNSPECIFIED
 	}
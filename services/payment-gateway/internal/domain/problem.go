@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCode is the stable, machine readable code an error is reported to clients by. The same codes are used by
+// every transport, unlike messages they are never changed once published.
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidRequest is a request that could not be read, such as a missing or malformed body.
+	ErrorCodeInvalidRequest          ErrorCode = "invalid_request"
+	ErrorCodeValidationFailed        ErrorCode = "validation_failed"
+	ErrorCodeUnauthorized            ErrorCode = "unauthorized"
+	ErrorCodePaymentNotFound         ErrorCode = "payment_not_found"
+	ErrorCodeMerchantNotFound        ErrorCode = "merchant_not_found"
+	ErrorCodeWebhookEndpointNotFound ErrorCode = "webhook_endpoint_not_found"
+	ErrorCodeWebhookDeliveryNotFound ErrorCode = "webhook_delivery_not_found"
+	ErrorCodeUnknownCardToken        ErrorCode = "unknown_card_token"
+	ErrorCodeCardBrandNotAccepted    ErrorCode = "card_brand_not_accepted"
+	ErrorCodeFXRateUnavailable       ErrorCode = "fx_rate_unavailable"
+	ErrorCodeOperationNotPermitted   ErrorCode = "operation_not_permitted"
+	ErrorCodeAuthorizationExpired    ErrorCode = "authorization_expired"
+	ErrorCodePaymentDeclined         ErrorCode = "payment_declined"
+	ErrorCodeIssuerUnavailable       ErrorCode = "issuer_unavailable"
+	ErrorCodeIdempotencyKeyInUse     ErrorCode = "idempotency_key_in_use"
+	ErrorCodeIdempotencyKeyMismatch  ErrorCode = "idempotency_key_mismatch"
+	// ErrorCodeInternal is any error without a code of its own, its details are never reported to clients.
+	ErrorCodeInternal ErrorCode = "internal_error"
+)
+
+// ValidationError is returned when a field of a request fails validation.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors are the errors of every field of a request failing validation.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// InvalidField returns the validation error for a field holding an invalid value.
+func InvalidField(field, reason string, args ...interface{}) ValidationError {
+	return ValidationError{Field: field, Message: fmt.Sprintf("invalid %s: %s", field, fmt.Sprintf(reason, args...))}
+}
+
+// MissingField returns the validation error for a field that is required but was not supplied.
+func MissingField(field, reason string) ValidationError {
+	return ValidationError{Field: field, Message: fmt.Sprintf("missing %s: %s", field, reason)}
+}
+
+// DeclinedError is returned when an action is rejected as the payment was declined, carrying the reason the issuer
+// gave. It matches the error rejecting the action.
+type DeclinedError struct {
+	Err    error
+	Reason string
+}
+
+func (e DeclinedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e DeclinedError) Unwrap() error {
+	return e.Err
+}
+
+// Problem describes an error in terms a client can act upon.
+type Problem struct {
+	Code    ErrorCode
+	Message string
+	// Field is the request field the problem lies with, if any.
+	Field         string
+	DeclineReason string
+	// Errors are every field failing validation.
+	Errors []ValidationError
+	// SideEffectFree is set when the request was rejected before anything was sent to the issuer, such as whilst its
+	// circuit breaker is open, so retrying it can do no harm. It is never reported to clients.
+	SideEffectFree bool
+}
+
+// ProblemInternal is reported for errors without a code of their own.
+var ProblemInternal = Problem{Code: ErrorCodeInternal, Message: "Oops something went wrong"}
+
+// ProblemOf maps the error to the problem reported to clients, operation naming what was requested in messages such
+// as "capture not allowed". Errors without a code of their own are internal.
+func ProblemOf(err error, operation string) Problem {
+	var (
+		validationErrs ValidationErrors
+		validationErr  ValidationError
+		declinedErr    DeclinedError
+	)
+	switch {
+	case errors.As(err, &validationErrs) && len(validationErrs) != 0:
+		problem := Problem{Code: ErrorCodeValidationFailed, Message: validationErrs.Error(), Errors: validationErrs}
+		if len(validationErrs) == 1 {
+			problem.Field = validationErrs[0].Field
+		}
+		return problem
+	case errors.As(err, &validationErr):
+		return Problem{Code: ErrorCodeValidationFailed, Message: validationErr.Message, Field: validationErr.Field}
+	case errors.Is(err, ErrNoAPIKey):
+		return Problem{Code: ErrorCodeUnauthorized, Message: "invalid api key"}
+	case errors.Is(err, ErrNoPayment):
+		return Problem{Code: ErrorCodePaymentNotFound, Message: "payment not found"}
+	case errors.Is(err, ErrNoMerchant):
+		return Problem{Code: ErrorCodeMerchantNotFound, Message: "merchant not found"}
+	case errors.Is(err, ErrNoWebhookEndpoint):
+		return Problem{Code: ErrorCodeWebhookEndpointNotFound, Message: "webhook endpoint not found"}
+	case errors.Is(err, ErrNoWebhookDelivery):
+		return Problem{Code: ErrorCodeWebhookDeliveryNotFound, Message: "webhook delivery not found"}
+	case errors.Is(err, ErrNoCardToken):
+		return Problem{Code: ErrorCodeUnknownCardToken, Message: "invalid payment_method.card.token: unknown token", Field: "payment_method.card.token"}
+	case errors.Is(err, ErrCardBrandNotAccepted):
+		return Problem{Code: ErrorCodeCardBrandNotAccepted, Message: "invalid payment_method.card.card_number: card brand not accepted", Field: "payment_method.card.card_number"}
+	case errors.Is(err, ErrNoFXRate):
+		return Problem{Code: ErrorCodeFXRateUnavailable, Message: "invalid settlement_currency: no fx rate from amount.currency", Field: "settlement_currency"}
+	case errors.As(err, &declinedErr):
+		return Problem{Code: ErrorCodePaymentDeclined, Message: operation + " not allowed: payment declined", DeclineReason: declinedErr.Reason}
+	case errors.Is(err, ErrAuthorizationExpired):
+		return Problem{Code: ErrorCodeAuthorizationExpired, Message: operation + " not allowed: authorization expired"}
+	case errors.Is(err, ErrNotPermitted):
+		return Problem{Code: ErrorCodeOperationNotPermitted, Message: operation + " not allowed"}
+	case errors.Is(err, ErrCircuitOpen):
+		return Problem{Code: ErrorCodeIssuerUnavailable, Message: "issuer unavailable, try again later", SideEffectFree: true}
+	case errors.Is(err, ErrIssuerUnavailable):
+		return Problem{Code: ErrorCodeIssuerUnavailable, Message: "issuer unavailable, try again later"}
+	case errors.Is(err, ErrIdempotencyKeyExists), errors.Is(err, ErrNoIdempotencyKey):
+		return Problem{Code: ErrorCodeIdempotencyKeyInUse, Message: "a request with this Idempotency-Key is in progress"}
+	case errors.Is(err, ErrIdempotencyKeyMismatch):
+		return Problem{Code: ErrorCodeIdempotencyKeyMismatch, Message: ErrIdempotencyKeyMismatch.Error()}
+	default:
+		return ProblemInternal
+	}
+}
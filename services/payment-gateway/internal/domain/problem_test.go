@@ -0,0 +1,115 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemOf(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		err         error
+		exp         domain.Problem
+	}{
+		{
+			description: "should report the field failing validation",
+			err:         domain.InvalidField("amount", "cannot exceed %d", 10),
+			exp: domain.Problem{
+				Code:    domain.ErrorCodeValidationFailed,
+				Message: "invalid amount: cannot exceed 10",
+				Field:   "amount",
+			},
+		},
+		{
+			description: "should report a missing field",
+			err:         domain.MissingField("payment_method", "cannot be empty"),
+			exp: domain.Problem{
+				Code:    domain.ErrorCodeValidationFailed,
+				Message: "missing payment_method: cannot be empty",
+				Field:   "payment_method",
+			},
+		},
+		{
+			description: "should report every field failing validation",
+			err: domain.ValidationErrors{
+				domain.InvalidField("payment_id", "must be a UUID"),
+				domain.InvalidField("amount", "cannot be zero"),
+			},
+			exp: domain.Problem{
+				Code:    domain.ErrorCodeValidationFailed,
+				Message: "invalid payment_id: must be a UUID; invalid amount: cannot be zero",
+				Errors: []domain.ValidationError{
+					{Field: "payment_id", Message: "invalid payment_id: must be a UUID"},
+					{Field: "amount", Message: "invalid amount: cannot be zero"},
+				},
+			},
+		},
+		{
+			description: "should map wrapped errors",
+			err:         fmt.Errorf("failed to get payment: %w", domain.ErrNoPayment),
+			exp:         domain.Problem{Code: domain.ErrorCodePaymentNotFound, Message: "payment not found"},
+		},
+		{
+			description: "should name the operation not permitted",
+			err:         domain.ErrNotPermitted,
+			exp:         domain.Problem{Code: domain.ErrorCodeOperationNotPermitted, Message: "capture not allowed"},
+		},
+		{
+			description: "should report why the payment was declined",
+			err:         domain.DeclinedError{Err: domain.ErrNotPermitted, Reason: "insufficient funds"},
+			exp: domain.Problem{
+				Code:          domain.ErrorCodePaymentDeclined,
+				Message:       "capture not allowed: payment declined",
+				DeclineReason: "insufficient funds",
+			},
+		},
+		{
+			description: "should report an expired authorization",
+			err:         domain.ErrAuthorizationExpired,
+			exp:         domain.Problem{Code: domain.ErrorCodeAuthorizationExpired, Message: "capture not allowed: authorization expired"},
+		},
+		{
+			description: "should report a card brand the merchant does not accept",
+			err:         domain.ErrCardBrandNotAccepted,
+			exp: domain.Problem{
+				Code:    domain.ErrorCodeCardBrandNotAccepted,
+				Message: "invalid payment_method.card.card_number: card brand not accepted",
+				Field:   "payment_method.card.card_number",
+			},
+		},
+		{
+			description: "should report the issuer's circuit breaker being open as free of side effects",
+			err:         domain.ErrCircuitOpen,
+			exp: domain.Problem{
+				Code:           domain.ErrorCodeIssuerUnavailable,
+				Message:        "issuer unavailable, try again later",
+				SideEffectFree: true,
+			},
+		},
+		{
+			description: "should hide the details of any other error",
+			err:         errors.New("connection refused"),
+			exp:         domain.ProblemInternal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.exp, domain.ProblemOf(tc.err, "capture"))
+		})
+	}
+}
+
+func TestDeclinedError(t *testing.T) {
+	t.Parallel()
+
+	err := error(domain.DeclinedError{Err: domain.ErrNotPermitted, Reason: "insufficient funds"})
+	assert.True(t, errors.Is(err, domain.ErrNotPermitted))
+	assert.Equal(t, domain.ErrNotPermitted.Error(), err.Error())
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+)
+
+// ResponseCategory classifies an ISO 8583 response code by what it means for the payment.
+type ResponseCategory string
+
+const (
+	ResponseCategoryApproved ResponseCategory = "APPROVED"
+	// ResponseCategorySoftDecline is a decline that may succeed if retried later.
+	ResponseCategorySoftDecline ResponseCategory = "SOFT_DECLINE"
+	// ResponseCategoryHardDecline is a decline that will never succeed if retried.
+	ResponseCategoryHardDecline ResponseCategory = "HARD_DECLINE"
+	// ResponseCategoryReferral is a decline requiring the cardholder to contact their issuer.
+	ResponseCategoryReferral ResponseCategory = "REFERRAL"
+	// ResponseCategoryFraud is a decline as the card is lost, stolen or suspected of fraud.
+	ResponseCategoryFraud ResponseCategory = "FRAUD"
+)
+
+func (c *ResponseCategory) FromProto(category paymentsV1.ResponseCategory) error {
+	switch category {
+	case paymentsV1.ResponseCategory_RESPONSE_CATEGORY_APPROVED:
+		*c = ResponseCategoryApproved
+	case paymentsV1.ResponseCategory_RESPONSE_CATEGORY_SOFT_DECLINE:
+		*c = ResponseCategorySoftDecline
+	case paymentsV1.ResponseCategory_RESPONSE_CATEGORY_HARD_DECLINE:
+		*c = ResponseCategoryHardDecline
+	case paymentsV1.ResponseCategory_RESPONSE_CATEGORY_REFERRAL:
+		*c = ResponseCategoryReferral
+	case paymentsV1.ResponseCategory_RESPONSE_CATEGORY_FRAUD:
+		*c = ResponseCategoryFraud
+	default:
+		return errors.New("unknown")
+	}
+	return nil
+}
+
+func (c ResponseCategory) ToProto() paymentsV1.ResponseCategory {
+	switch c {
+	case ResponseCategoryApproved:
+		return paymentsV1.ResponseCategory_RESPONSE_CATEGORY_APPROVED
+	case ResponseCategorySoftDecline:
+		return paymentsV1.ResponseCategory_RESPONSE_CATEGORY_SOFT_DECLINE
+	case ResponseCategoryHardDecline:
+		return paymentsV1.ResponseCategory_RESPONSE_CATEGORY_HARD_DECLINE
+	case ResponseCategoryReferral:
+		return paymentsV1.ResponseCategory_RESPONSE_CATEGORY_REFERRAL
+	case ResponseCategoryFraud:
+		return paymentsV1.ResponseCategory_RESPONSE_CATEGORY_FRAUD
+	default:
+		return paymentsV1.ResponseCategory_RESPONSE_CATEGORY_UNSPECIFIED
+	}
+}
+
+// ResponseCode is an ISO 8583 response code returned by the issuer.
+type ResponseCode struct {
+	Code     string
+	Category ResponseCategory
+	Reason   string
+}
+
+// Approved reports whether the issuer approved the request.
+func (r ResponseCode) Approved() bool {
+	return r.Category == ResponseCategoryApproved
+}
+
+// Retryable reports whether a declined request may succeed if retried later.
+func (r ResponseCode) Retryable() bool {
+	return r.Category == ResponseCategorySoftDecline
+}
+
+// ResponseCodes is the catalogue of ISO 8583 response codes understood by the gateway.
+var ResponseCodes = map[string]ResponseCode{
+	"00": {"00", ResponseCategoryApproved, "approved"},
+	"01": {"01", ResponseCategoryReferral, "refer to card issuer"},
+	"02": {"02", ResponseCategoryReferral, "refer to card issuer, special condition"},
+	"03": {"03", ResponseCategoryHardDecline, "invalid merchant"},
+	"04": {"04", ResponseCategoryFraud, "pick up card"},
+	"05": {"05", ResponseCategorySoftDecline, "do not honour"},
+	"06": {"06", ResponseCategorySoftDecline, "error"},
+	"07": {"07", ResponseCategoryFraud, "pick up card, special condition"},
+	"08": {"08", ResponseCategoryApproved, "honour with identification"},
+	"11": {"11", ResponseCategoryApproved, "approved (VIP)"},
+	"12": {"12", ResponseCategoryHardDecline, "invalid transaction"},
+	"13": {"13", ResponseCategoryHardDecline, "invalid amount"},
+	"14": {"14", ResponseCategoryHardDecline, "invalid card number"},
+	"15": {"15", ResponseCategoryHardDecline, "no such issuer"},
+	"19": {"19", ResponseCategorySoftDecline, "re-enter transaction"},
+	"25": {IssuerResponseCodeNoRecord, ResponseCategoryHardDecline, "unable to locate record"},
+	"30": {"30", ResponseCategoryHardDecline, "format error"},
+	"41": {"41", ResponseCategoryFraud, "lost card"},
+	"43": {"43", ResponseCategoryFraud, "stolen card"},
+	"51": {"51", ResponseCategorySoftDecline, "insufficient funds"},
+	"54": {"54", ResponseCategoryHardDecline, "expired card"},
+	"55": {"55", ResponseCategorySoftDecline, "incorrect PIN"},
+	"57": {"57", ResponseCategoryHardDecline, "transaction not permitted to cardholder"},
+	"58": {"58", ResponseCategoryHardDecline, "transaction not permitted to terminal"},
+	"59": {"59", ResponseCategoryFraud, "suspected fraud"},
+	"61": {"61", ResponseCategorySoftDecline, "exceeds withdrawal amount limit"},
+	"62": {"62", ResponseCategoryHardDecline, "restricted card"},
+	"63": {"63", ResponseCategoryFraud, "security violation"},
+	"65": {"65", ResponseCategorySoftDecline, "exceeds withdrawal frequency limit"},
+	"75": {"75", ResponseCategoryHardDecline, "allowable number of PIN tries exceeded"},
+	"91": {"91", ResponseCategorySoftDecline, "issuer or switch is inoperative"},
+	"92": {"92", ResponseCategorySoftDecline, "unable to route transaction"},
+	"94": {"94", ResponseCategoryHardDecline, "duplicate transmission"},
+	"96": {"96", ResponseCategorySoftDecline, "system malfunction"},
+}
+
+// LookupResponseCode returns the response code from the catalogue. Codes missing from the catalogue are treated
+// as a hard decline so they are never mistaken for an approval.
+func LookupResponseCode(code string) ResponseCode {
+	if responseCode, ok := ResponseCodes[code]; ok {
+		return responseCode
+	}
+	return ResponseCode{Code: code, Category: ResponseCategoryHardDecline, Reason: "unknown response code"}
+}
@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupResponseCode(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		code         string
+		expCategory  domain.ResponseCategory
+		expApproved  bool
+		expRetryable bool
+	}{
+		{code: "00", expCategory: domain.ResponseCategoryApproved, expApproved: true},
+		{code: "08", expCategory: domain.ResponseCategoryApproved, expApproved: true},
+		{code: "05", expCategory: domain.ResponseCategorySoftDecline, expRetryable: true},
+		{code: "51", expCategory: domain.ResponseCategorySoftDecline, expRetryable: true},
+		{code: "91", expCategory: domain.ResponseCategorySoftDecline, expRetryable: true},
+		{code: "12", expCategory: domain.ResponseCategoryHardDecline},
+		{code: "54", expCategory: domain.ResponseCategoryHardDecline},
+		{code: domain.IssuerResponseCodeNoRecord, expCategory: domain.ResponseCategoryHardDecline},
+		{code: "01", expCategory: domain.ResponseCategoryReferral},
+		{code: "41", expCategory: domain.ResponseCategoryFraud},
+		{code: "59", expCategory: domain.ResponseCategoryFraud},
+		{code: "", expCategory: domain.ResponseCategoryHardDecline},
+		{code: "ZZ", expCategory: domain.ResponseCategoryHardDecline},
+	} {
+		responseCode := domain.LookupResponseCode(tc.code)
+		assert.Equal(t, tc.code, responseCode.Code)
+		assert.Equal(t, tc.expCategory, responseCode.Category, tc.code)
+		assert.Equal(t, tc.expApproved, responseCode.Approved(), tc.code)
+		assert.Equal(t, tc.expRetryable, responseCode.Retryable(), tc.code)
+		assert.NotEmpty(t, responseCode.Reason)
+	}
+}
+
+func TestResponseCodes(t *testing.T) {
+	t.Parallel()
+
+	for code, responseCode := range domain.ResponseCodes {
+		assert.Equal(t, code, responseCode.Code)
+		require.Len(t, code, 2)
+
+		var category domain.ResponseCategory
+		require.NoError(t, category.FromProto(responseCode.Category.ToProto()), code)
+		assert.Equal(t, responseCode.Category, category)
+	}
+}
+
+func TestResponseCodes_Approved(t *testing.T) {
+	t.Parallel()
+
+	// migrations/14_ledger.up.sql backfills actions without a category by these codes
+	var approved []string
+	for code, responseCode := range domain.ResponseCodes {
+		if responseCode.Approved() {
+			approved = append(approved, code)
+		}
+	}
+	assert.ElementsMatch(t, []string{"00", "08", "11"}, approved)
+}
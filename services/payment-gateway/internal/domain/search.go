@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	DefaultListPaymentsLimit = 20
+	MaxListPaymentsLimit     = 100
+)
+
+// ListPaymentFilters filters the payments returned when listing. Empty fields are not filtered on.
+// Payments are ordered newest first and paginated from the Cursor.
+type ListPaymentFilters struct {
+	Statuses      []PaymentStatus
+	Currencies    []string
+	MinAmount     uint64
+	MaxAmount     uint64
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	CardLastFour  string
+	// ExpiresBefore only returns payments whose authorization expires before the time.
+	ExpiresBefore time.Time
+
+	Cursor *PaymentCursor
+	Limit  uint64
+}
+
+// PaymentCursor points at the last payment of a page. Payments are paged in the order they were inserted, newest
+// first, so that paging through them returns every payment committed before the first page was read exactly once
+// however many are inserted meanwhile. A payment committed whilst paging may be missed, it is returned when listing
+// from the first page again.
+type PaymentCursor struct {
+	ID string
+}
+
+// Encode returns the opaque representation of the cursor returned to clients.
+func (c PaymentCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.ID))
+}
+
+// DecodePaymentCursor parses a cursor previously returned by Encode. Cursors returned before payments were paged in
+// the order they were inserted, which also held when the payment was created, are still accepted.
+func DecodePaymentCursor(cursor string) (*PaymentCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id := string(b)
+	if i := strings.LastIndex(id, "|"); i != -1 {
+		id = id[i+1:]
+	}
+	if _, err = uuid.FromString(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &PaymentCursor{ID: id}, nil
+}
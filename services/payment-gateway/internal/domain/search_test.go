@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"encoding/base64"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPaymentCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode an encoded cursor", func(t *testing.T) {
+		t.Parallel()
+		cursor := domain.PaymentCursor{ID: uuid.NewV4().String()}
+		decoded, err := domain.DecodePaymentCursor(cursor.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, cursor, *decoded)
+	})
+
+	t.Run("should decode a cursor holding when the payment was created", func(t *testing.T) {
+		t.Parallel()
+		id := uuid.NewV4().String()
+		decoded, err := domain.DecodePaymentCursor(base64.RawURLEncoding.EncodeToString(
+			[]byte(time.Date(2021, 11, 2, 10, 30, 15, 123456000, time.UTC).Format(time.RFC3339Nano) + "|" + id)))
+		require.NoError(t, err)
+		assert.Equal(t, id, decoded.ID)
+	})
+
+	for _, cursor := range []string{"not-base64!", "bm8tc2VwYXJhdG9y", "MjAyMS0xMS0wMnw="} {
+		cursor := cursor
+		t.Run("should return error given invalid cursor "+cursor, func(t *testing.T) {
+			t.Parallel()
+			_, err := domain.DecodePaymentCursor(cursor)
+			assert.Equal(t, domain.ErrInvalidCursor, err)
+		})
+	}
+}
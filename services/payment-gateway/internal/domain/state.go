@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Transition is a legal move of a payment between statuses through a payment action.
+type Transition struct {
+	PaymentType PaymentType
+	From        PaymentStatus
+	To          PaymentStatus
+}
+
+// TransitionError is returned when a payment action is not permitted from the payment's status.
+// To is empty when the action was rejected before its outcome was known. It matches ErrNotPermitted.
+type TransitionError struct {
+	PaymentType PaymentType
+	From        PaymentStatus
+	To          PaymentStatus
+}
+
+func (e TransitionError) Error() string {
+	if e.To == "" {
+		return fmt.Sprintf("%s: %s not permitted from %s", ErrNotPermitted, e.PaymentType, statusName(e.From))
+	}
+	return fmt.Sprintf("%s: %s not permitted from %s to %s", ErrNotPermitted, e.PaymentType, statusName(e.From), statusName(e.To))
+}
+
+func (e TransitionError) Is(target error) bool {
+	return target == ErrNotPermitted
+}
+
+func statusName(status PaymentStatus) string {
+	if status == "" {
+		return "UNSPECIFIED"
+	}
+	return string(status)
+}
+
+// PaymentTransitions declares every legal transition of a payment.
+var PaymentTransitions = []Transition{
+	{PaymentTypeAuthorization, PaymentStatusPending, PaymentStatusAuthorized},
+	{PaymentTypeAuthorization, PaymentStatusPending, PaymentStatusDeclined},
+	{PaymentTypeIncrementalAuthorization, PaymentStatusAuthorized, PaymentStatusAuthorized},
+
+	{PaymentTypeCapture, PaymentStatusAuthorized, PaymentStatusPartiallyCaptured},
+	{PaymentTypeCapture, PaymentStatusAuthorized, PaymentStatusCaptured},
+	{PaymentTypeCapture, PaymentStatusPartiallyCaptured, PaymentStatusPartiallyCaptured},
+	{PaymentTypeCapture, PaymentStatusPartiallyCaptured, PaymentStatusCaptured},
+
+	{PaymentTypeRefund, PaymentStatusPartiallyCaptured, PaymentStatusPartiallyRefunded},
+	{PaymentTypeRefund, PaymentStatusPartiallyCaptured, PaymentStatusRefunded},
+	{PaymentTypeRefund, PaymentStatusCaptured, PaymentStatusPartiallyRefunded},
+	{PaymentTypeRefund, PaymentStatusCaptured, PaymentStatusRefunded},
+	{PaymentTypeRefund, PaymentStatusPartiallyRefunded, PaymentStatusPartiallyRefunded},
+	{PaymentTypeRefund, PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+
+	// a reversal releasing all that is left uncaptured completes the payment
+	{PaymentTypeReversal, PaymentStatusAuthorized, PaymentStatusAuthorized},
+	{PaymentTypeReversal, PaymentStatusAuthorized, PaymentStatusVoided},
+	{PaymentTypeReversal, PaymentStatusPartiallyCaptured, PaymentStatusPartiallyCaptured},
+	{PaymentTypeReversal, PaymentStatusPartiallyCaptured, PaymentStatusCaptured},
+	// a payment refunded before it was fully captured still holds what was left uncaptured until it is reversed
+	{PaymentTypeReversal, PaymentStatusPartiallyRefunded, PaymentStatusPartiallyRefunded},
+	{PaymentTypeReversal, PaymentStatusRefunded, PaymentStatusRefunded},
+
+	{PaymentTypeVoid, PaymentStatusAuthorized, PaymentStatusVoided},
+	// an expired authorization is released by the scheme rather than voided with the issuer
+	{PaymentTypeVoid, PaymentStatusAuthorized, PaymentStatusExpired},
+}
+
+// PaymentStateMachine is the state machine all payments move through.
+var PaymentStateMachine = NewStateMachine(PaymentTransitions...)
+
+// StateMachine holds the legal transitions between payment statuses per payment type.
+type StateMachine struct {
+	transitions []Transition
+	allowed     map[PaymentType]map[PaymentStatus]map[PaymentStatus]bool
+}
+
+func NewStateMachine(transitions ...Transition) StateMachine {
+	m := StateMachine{
+		transitions: transitions,
+		allowed:     make(map[PaymentType]map[PaymentStatus]map[PaymentStatus]bool),
+	}
+	for _, t := range transitions {
+		if m.allowed[t.PaymentType] == nil {
+			m.allowed[t.PaymentType] = make(map[PaymentStatus]map[PaymentStatus]bool)
+		}
+		if m.allowed[t.PaymentType][t.From] == nil {
+			m.allowed[t.PaymentType][t.From] = make(map[PaymentStatus]bool)
+		}
+		m.allowed[t.PaymentType][t.From][t.To] = true
+	}
+	return m
+}
+
+// CanApply returns a TransitionError if the payment type cannot be made from the status.
+func (m StateMachine) CanApply(paymentType PaymentType, from PaymentStatus) error {
+	if len(m.allowed[paymentType][from]) == 0 {
+		return TransitionError{PaymentType: paymentType, From: from}
+	}
+	return nil
+}
+
+// Transition returns a TransitionError if the payment type cannot move a payment between the statuses.
+func (m StateMachine) Transition(paymentType PaymentType, from, to PaymentStatus) error {
+	if !m.allowed[paymentType][from][to] {
+		return TransitionError{PaymentType: paymentType, From: from, To: to}
+	}
+	return nil
+}
+
+// Transitions returns every legal transition in the order they were declared.
+func (m StateMachine) Transitions() []Transition {
+	transitions := make([]Transition, len(m.transitions))
+	copy(transitions, m.transitions)
+	return transitions
+}
+
+// Table renders the transitions as a markdown table.
+func (m StateMachine) Table() string {
+	var b strings.Builder
+	b.WriteString("| Payment Type | From | To |\n")
+	b.WriteString("|---|---|---|\n")
+	for _, t := range m.transitions {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", t.PaymentType, t.From, t.To)
+	}
+	return b.String()
+}
+
+// DOT renders the transitions as a graphviz digraph, edges between the same statuses are merged.
+func (m StateMachine) DOT() string {
+	edges := make(map[[2]PaymentStatus][]string)
+	var order [][2]PaymentStatus
+	for _, t := range m.transitions {
+		edge := [2]PaymentStatus{t.From, t.To}
+		if _, ok := edges[edge]; !ok {
+			order = append(order, edge)
+		}
+		edges[edge] = append(edges[edge], string(t.PaymentType))
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph payment {\n")
+	for _, edge := range order {
+		labels := edges[edge]
+		sort.Strings(labels)
+		fmt.Fprintf(&b, "\t%s -> %s [label=%q];\n", edge[0], edge[1], strings.Join(labels, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
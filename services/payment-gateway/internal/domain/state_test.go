@@ -0,0 +1,122 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var allStatuses = []domain.PaymentStatus{
+	domain.PaymentStatusPending,
+	domain.PaymentStatusAuthorized,
+	domain.PaymentStatusCaptured,
+	domain.PaymentStatusPartiallyCaptured,
+	domain.PaymentStatusRefunded,
+	domain.PaymentStatusPartiallyRefunded,
+	domain.PaymentStatusVoided,
+	domain.PaymentStatusDeclined,
+	domain.PaymentStatusExpired,
+}
+
+func TestStateMachine_Transition(t *testing.T) {
+	t.Parallel()
+
+	allowed := make(map[domain.Transition]bool)
+	for _, transition := range domain.PaymentStateMachine.Transitions() {
+		allowed[transition] = true
+	}
+
+	for _, paymentType := range []domain.PaymentType{domain.PaymentTypeAuthorization, domain.PaymentTypeIncrementalAuthorization, domain.PaymentTypeCapture, domain.PaymentTypeRefund, domain.PaymentTypeReversal, domain.PaymentTypeVoid} {
+		for _, from := range allStatuses {
+			for _, to := range allStatuses {
+				transition := domain.Transition{PaymentType: paymentType, From: from, To: to}
+				err := domain.PaymentStateMachine.Transition(paymentType, from, to)
+				if allowed[transition] {
+					assert.NoError(t, err, transition)
+					continue
+				}
+				require.Error(t, err, transition)
+				assert.True(t, errors.Is(err, domain.ErrNotPermitted))
+				assert.Equal(t, domain.TransitionError{PaymentType: paymentType, From: from, To: to}, err)
+			}
+		}
+	}
+}
+
+func TestStateMachine_CanApply(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		paymentType domain.PaymentType
+		from        domain.PaymentStatus
+		allowed     bool
+	}{
+		{domain.PaymentTypeAuthorization, domain.PaymentStatusPending, true},
+		{domain.PaymentTypeAuthorization, domain.PaymentStatusAuthorized, false},
+		{domain.PaymentTypeCapture, domain.PaymentStatusAuthorized, true},
+		{domain.PaymentTypeCapture, domain.PaymentStatusPartiallyCaptured, true},
+		{domain.PaymentTypeCapture, domain.PaymentStatusCaptured, false},
+		{domain.PaymentTypeRefund, domain.PaymentStatusCaptured, true},
+		{domain.PaymentTypeRefund, domain.PaymentStatusPartiallyRefunded, true},
+		{domain.PaymentTypeRefund, domain.PaymentStatusRefunded, false},
+		{domain.PaymentTypeVoid, domain.PaymentStatusAuthorized, true},
+		{domain.PaymentTypeVoid, domain.PaymentStatusPartiallyCaptured, false},
+		{domain.PaymentTypeCapture, domain.PaymentStatusExpired, false},
+		{domain.PaymentTypeIncrementalAuthorization, domain.PaymentStatusAuthorized, true},
+		{domain.PaymentTypeIncrementalAuthorization, domain.PaymentStatusPartiallyCaptured, false},
+		{domain.PaymentTypeReversal, domain.PaymentStatusPartiallyCaptured, true},
+		{domain.PaymentTypeReversal, domain.PaymentStatusCaptured, false},
+		{domain.PaymentTypeReversal, domain.PaymentStatusPartiallyRefunded, true},
+		{domain.PaymentTypeReversal, domain.PaymentStatusRefunded, true},
+		{domain.PaymentTypeReversal, domain.PaymentStatusVoided, false},
+		{domain.PaymentTypeVoid, "", false},
+	} {
+		err := domain.PaymentStateMachine.CanApply(tc.paymentType, tc.from)
+		if tc.allowed {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.Equal(t, domain.TransitionError{PaymentType: tc.paymentType, From: tc.from}, err)
+	}
+}
+
+func TestTransitionError_Error(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "not permitted: VOID not permitted from CAPTURED",
+		domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusCaptured}.Error())
+	assert.Equal(t, "not permitted: CAPTURE not permitted from UNSPECIFIED to CAPTURED",
+		domain.TransitionError{PaymentType: domain.PaymentTypeCapture, To: domain.PaymentStatusCaptured}.Error())
+}
+
+func TestStateMachine_Export(t *testing.T) {
+	t.Parallel()
+
+	machine := domain.NewStateMachine(
+		domain.Transition{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusAuthorized, To: domain.PaymentStatusCaptured},
+		domain.Transition{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusAuthorized, To: domain.PaymentStatusVoided},
+		domain.Transition{PaymentType: domain.PaymentTypeRefund, From: domain.PaymentStatusCaptured, To: domain.PaymentStatusRefunded},
+	)
+
+	assert.Equal(t, strings.Join([]string{
+		"| Payment Type | From | To |",
+		"|---|---|---|",
+		"| CAPTURE | AUTHORIZED | CAPTURED |",
+		"| VOID | AUTHORIZED | VOIDED |",
+		"| REFUND | CAPTURED | REFUNDED |",
+		"",
+	}, "\n"), machine.Table())
+
+	assert.Equal(t, strings.Join([]string{
+		"digraph payment {",
+		"\tAUTHORIZED -> CAPTURED [label=\"CAPTURE\"];",
+		"\tAUTHORIZED -> VOIDED [label=\"VOID\"];",
+		"\tCAPTURED -> REFUNDED [label=\"REFUND\"];",
+		"}",
+		"",
+	}, "\n"), machine.DOT())
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+var (
+	ErrNoWebhookEndpoint = errors.New("no webhook endpoint found")
+	ErrNoWebhookDelivery = errors.New("no webhook delivery found")
+	// ErrNonPublicAddress is returned when connecting to a webhook endpoint resolving to an address that is not public.
+	ErrNonPublicAddress = errors.New("not a public address")
+)
+
+var (
+	// nonPublicNetworks are the ranges, besides loopback, link-local, multicast and unspecified addresses, that are not
+	// routable on the public internet.
+	nonPublicNetworks = parseCIDRs(
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"240.0.0.0/4",
+		"fc00::/7",
+	)
+	// internalHosts are names that resolve to the gateway itself or to cloud metadata services.
+	internalHosts = map[string]bool{
+		"localhost":                true,
+		"metadata":                 true,
+		"metadata.google.internal": true,
+		"instance-data":            true,
+	}
+)
+
+// WebhookEndpoint is a URL a merchant is notified at of changes to their payments.
+type WebhookEndpoint struct {
+	ID         uuid.UUID `db:"id"`
+	MerchantID uuid.UUID `db:"merchant_id"`
+	URL        string    `db:"url"`
+	// Secret signs the notifications sent to the endpoint, it is shared with the merchant when registered.
+	Secret     string       `db:"secret"`
+	CreatedAt  time.Time    `db:"created_at"`
+	DisabledAt sql.NullTime `db:"disabled_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	// WebhookDeliveryStatusFailed is a delivery that exhausted its attempts, it is only retried if replayed.
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery is the notification of an outbox event to an endpoint along with the outcome of delivering it.
+type WebhookDelivery struct {
+	ID         uuid.UUID `db:"id"`
+	EndpointID uuid.UUID `db:"endpoint_id"`
+	EventID    uuid.UUID `db:"event_id"`
+	PaymentID  uuid.UUID `db:"payment_id"`
+	EventType  string    `db:"event_type"`
+	// Payload is the JSON body sent to the endpoint.
+	Payload       []byte                `db:"payload"`
+	Status        WebhookDeliveryStatus `db:"status"`
+	Attempts      int                   `db:"attempts"`
+	NextAttemptAt time.Time             `db:"next_attempt_at"`
+	// LastResponseStatus is the HTTP status the endpoint last responded with, it is null if it never responded.
+	LastResponseStatus sql.NullInt32  `db:"last_response_status"`
+	LastError          sql.NullString `db:"last_error"`
+	CreatedAt          time.Time      `db:"created_at"`
+	DeliveredAt        sql.NullTime   `db:"delivered_at"`
+}
+
+// ListWebhookDeliveryFilters filters the deliveries returned when listing. Empty fields are not filtered on.
+type ListWebhookDeliveryFilters struct {
+	EndpointID string
+	PaymentID  string
+	Limit      uint64
+}
+
+// PublicWebhookHost reports whether the host of a webhook URL may be notified. Hosts that are the gateway itself, on
+// a private network or a cloud metadata service are not so that endpoints cannot be used to reach internal services.
+// A name can still resolve to such an address so the address connected to must also be checked with PublicIP.
+func PublicWebhookHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || internalHosts[host] || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return PublicIP(ip)
+	}
+	return true
+}
+
+// PublicIP reports whether the address is routable on the public internet.
+func PublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicWebhookHost(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		host string
+		bool
+	}{
+		{host: "merchant.example", bool: true},
+		{host: "93.184.216.34", bool: true},
+		{host: "2606:2800:220:1:248:1893:25c8:1946", bool: true},
+		{host: "localhost", bool: false},
+		{host: "LOCALHOST.", bool: false},
+		{host: "api.localhost", bool: false},
+		{host: "metadata.google.internal", bool: false},
+		{host: "127.0.0.1", bool: false},
+		{host: "::1", bool: false},
+		{host: "0.0.0.0", bool: false},
+		{host: "10.1.2.3", bool: false},
+		{host: "172.16.0.1", bool: false},
+		{host: "192.168.1.1", bool: false},
+		{host: "100.64.0.1", bool: false},
+		{host: "169.254.169.254", bool: false},
+		{host: "fe80::1", bool: false},
+		{host: "fd00:ec2::254", bool: false},
+		{host: "::ffff:127.0.0.1", bool: false},
+		{host: "", bool: false},
+	} {
+		tc := tc
+		t.Run(tc.host, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.bool, domain.PublicWebhookHost(tc.host))
+		})
+	}
+}
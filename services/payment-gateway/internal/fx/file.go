@@ -0,0 +1,88 @@
+// Package fx provides the FX rates payments are converted to their settlement currency by.
+package fx
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// inversePrecision is how many decimal places a rate derived from the inverse pair is held to.
+const inversePrecision = 10
+
+// Rate is a configured rate, see domain.FXRate.
+type Rate struct {
+	Base  string `yaml:"base"`
+	Quote string `yaml:"quote"`
+	Rate  string `yaml:"rate"`
+}
+
+// FileProvider is a stand in for a live rate feed, it provides the fixed rates loaded from a YAML file.
+type FileProvider struct {
+	rates map[string]string
+}
+
+// LoadFileProvider loads the rates from the YAML file at path, see fx-rates.yaml.
+func LoadFileProvider(path string) (FileProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return FileProvider{}, err
+	}
+	defer file.Close()
+
+	var config struct {
+		Rates []Rate `yaml:"rates"`
+	}
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&config); err != nil {
+		return FileProvider{}, errors.Wrapf(err, "unable to decode fx rates %s", path)
+	}
+	return NewFileProvider(config.Rates...)
+}
+
+// NewFileProvider returns a provider of the rates. A pair's inverse is derived from it unless also configured.
+func NewFileProvider(rates ...Rate) (FileProvider, error) {
+	p := FileProvider{rates: make(map[string]string, len(rates)*2)}
+	for _, rate := range rates {
+		if !currencyV1.Valid(rate.Base) || !currencyV1.Valid(rate.Quote) {
+			return FileProvider{}, errors.Errorf("invalid fx rate %s/%s: unknown currency", rate.Base, rate.Quote)
+		}
+		r, ok := new(big.Rat).SetString(rate.Rate)
+		if !ok || r.Sign() <= 0 {
+			return FileProvider{}, errors.Errorf("invalid fx rate %s/%s: rate must be a positive decimal", rate.Base, rate.Quote)
+		}
+		p.rates[pair(rate.Base, rate.Quote)] = rate.Rate
+	}
+	for _, rate := range rates {
+		if _, ok := p.rates[pair(rate.Quote, rate.Base)]; ok {
+			continue
+		}
+		r, _ := new(big.Rat).SetString(rate.Rate)
+		inverse := strings.TrimRight(new(big.Rat).Inv(r).FloatString(inversePrecision), "0")
+		p.rates[pair(rate.Quote, rate.Base)] = strings.TrimSuffix(inverse, ".")
+	}
+	return p, nil
+}
+
+// Rate returns the rate converting base to quote, domain.ErrNoFXRate is returned if there is none.
+func (p FileProvider) Rate(_ context.Context, base, quote string) (domain.FXRate, error) {
+	if base == quote {
+		return domain.FXRate{Base: base, Quote: quote, Rate: "1"}, nil
+	}
+	rate, ok := p.rates[pair(base, quote)]
+	if !ok {
+		return domain.FXRate{}, domain.ErrNoFXRate
+	}
+	return domain.FXRate{Base: base, Quote: quote, Rate: rate}, nil
+}
+
+func pair(base, quote string) string {
+	return base + "/" + quote
+}
@@ -0,0 +1,52 @@
+package fx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/fx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFileProvider(t *testing.T) {
+	t.Parallel()
+
+	provider, err := fx.LoadFileProvider("testdata/rates.yaml")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		description string
+		base, quote string
+		exp         string
+		expErr      error
+	}{
+		{description: "should return the configured rate", base: "EUR", quote: "GBP", exp: "0.8563"},
+		{description: "should derive the rate from the inverse pair", base: "GBP", quote: "EUR", exp: "1.1678150181"},
+		{description: "should prefer a configured rate over the inverse", base: "GBP", quote: "USD", exp: "1.2641"},
+		{description: "should return one given the same currency", base: "JPY", quote: "JPY", exp: "1"},
+		{description: "should return error given no rate", base: "EUR", quote: "USD", expErr: domain.ErrNoFXRate},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			rate, err := provider.Rate(context.Background(), tc.base, tc.quote)
+			require.Equal(t, tc.expErr, err)
+			if tc.expErr == nil {
+				assert.Equal(t, domain.FXRate{Base: tc.base, Quote: tc.quote, Rate: tc.exp}, rate)
+			}
+		})
+	}
+}
+
+func TestNewFileProvider(t *testing.T) {
+	t.Parallel()
+
+	_, err := fx.NewFileProvider(fx.Rate{Base: "EUR", Quote: "XYZ", Rate: "1"})
+	require.Error(t, err)
+	_, err = fx.NewFileProvider(fx.Rate{Base: "EUR", Quote: "GBP", Rate: "0"})
+	require.Error(t, err)
+	_, err = fx.LoadFileProvider("testdata/missing.yaml")
+	require.Error(t, err)
+}
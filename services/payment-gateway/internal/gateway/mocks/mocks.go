@@ -36,6 +36,53 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// ClaimPaymentAction mocks base method.
+func (m *MockStore) ClaimPaymentAction(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPaymentAction", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimPaymentAction indicates an expected call of ClaimPaymentAction.
+func (mr *MockStoreMockRecorder) ClaimPaymentAction(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPaymentAction", reflect.TypeOf((*MockStore)(nil).ClaimPaymentAction), ctx, id)
+}
+
+// CreateLedgerEntries mocks base method.
+func (m *MockStore) CreateLedgerEntries(ctx context.Context, entries ...*domain.LedgerEntry) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range entries {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLedgerEntries", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLedgerEntries indicates an expected call of CreateLedgerEntries.
+func (mr *MockStoreMockRecorder) CreateLedgerEntries(ctx interface{}, entries ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, entries...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerEntries", reflect.TypeOf((*MockStore)(nil).CreateLedgerEntries), varargs...)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), ctx, event)
+}
+
 // CreatePayment mocks base method.
 func (m *MockStore) CreatePayment(ctx context.Context, payment *v1.Payment) error {
 	m.ctrl.T.Helper()
@@ -78,6 +125,36 @@ func (mr *MockStoreMockRecorder) ExecInTransaction(ctx, fn interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecInTransaction", reflect.TypeOf((*MockStore)(nil).ExecInTransaction), ctx, fn)
 }
 
+// GetLedgerBalances mocks base method.
+func (m *MockStore) GetLedgerBalances(ctx context.Context, paymentID string) (domain.LedgerBalances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerBalances", ctx, paymentID)
+	ret0, _ := ret[0].(domain.LedgerBalances)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerBalances indicates an expected call of GetLedgerBalances.
+func (mr *MockStoreMockRecorder) GetLedgerBalances(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerBalances", reflect.TypeOf((*MockStore)(nil).GetLedgerBalances), ctx, paymentID)
+}
+
+// GetMerchant mocks base method.
+func (m *MockStore) GetMerchant(ctx context.Context, id string) (*domain.Merchant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMerchant", ctx, id)
+	ret0, _ := ret[0].(*domain.Merchant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMerchant indicates an expected call of GetMerchant.
+func (mr *MockStoreMockRecorder) GetMerchant(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMerchant", reflect.TypeOf((*MockStore)(nil).GetMerchant), ctx, id)
+}
+
 // GetPayment mocks base method.
 func (m *MockStore) GetPayment(ctx context.Context, id string) (*v1.Payment, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +170,36 @@ func (mr *MockStoreMockRecorder) GetPayment(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockStore)(nil).GetPayment), ctx, id)
 }
 
+// GetPaymentActionForUpdate mocks base method.
+func (m *MockStore) GetPaymentActionForUpdate(ctx context.Context, id string) (*v1.PaymentAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentActionForUpdate", ctx, id)
+	ret0, _ := ret[0].(*v1.PaymentAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentActionForUpdate indicates an expected call of GetPaymentActionForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentActionForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentActionForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentActionForUpdate), ctx, id)
+}
+
+// GetPaymentForUpdate mocks base method.
+func (m *MockStore) GetPaymentForUpdate(ctx context.Context, id string) (*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentForUpdate", ctx, id)
+	ret0, _ := ret[0].(*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentForUpdate indicates an expected call of GetPaymentForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentForUpdate), ctx, id)
+}
+
 // ListPaymentActions mocks base method.
 func (m *MockStore) ListPaymentActions(ctx context.Context, filters *domain.ListPaymentActionFilters) ([]*v1.PaymentAction, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +215,21 @@ func (mr *MockStoreMockRecorder) ListPaymentActions(ctx, filters interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentActions", reflect.TypeOf((*MockStore)(nil).ListPaymentActions), ctx, filters)
 }
 
+// ListPayments mocks base method.
+func (m *MockStore) ListPayments(ctx context.Context, filters *domain.ListPaymentFilters) ([]*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayments", ctx, filters)
+	ret0, _ := ret[0].([]*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayments indicates an expected call of ListPayments.
+func (mr *MockStoreMockRecorder) ListPayments(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayments", reflect.TypeOf((*MockStore)(nil).ListPayments), ctx, filters)
+}
+
 // UpdatePayment mocks base method.
 func (m *MockStore) UpdatePayment(ctx context.Context, payment *v1.Payment, fields ...domain.UpdatePaymentField) error {
 	m.ctrl.T.Helper()
@@ -183,3 +305,109 @@ func (mr *MockIssuerGatewayMockRecorder) CreateIssuerRequest(ctx, issuerRequest
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssuerRequest", reflect.TypeOf((*MockIssuerGateway)(nil).CreateIssuerRequest), ctx, issuerRequest)
 }
+
+// GetIssuerRequestStatus mocks base method.
+func (m *MockIssuerGateway) GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssuerRequestStatus", ctx, issuerRequest)
+	ret0, _ := ret[0].(domain.IssuerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuerRequestStatus indicates an expected call of GetIssuerRequestStatus.
+func (mr *MockIssuerGatewayMockRecorder) GetIssuerRequestStatus(ctx, issuerRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuerRequestStatus", reflect.TypeOf((*MockIssuerGateway)(nil).GetIssuerRequestStatus), ctx, issuerRequest)
+}
+
+// MockVault is a mock of Vault interface.
+type MockVault struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultMockRecorder
+}
+
+// MockVaultMockRecorder is the mock recorder for MockVault.
+type MockVaultMockRecorder struct {
+	mock *MockVault
+}
+
+// NewMockVault creates a new mock instance.
+func NewMockVault(ctrl *gomock.Controller) *MockVault {
+	mock := &MockVault{ctrl: ctrl}
+	mock.recorder = &MockVaultMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVault) EXPECT() *MockVaultMockRecorder {
+	return m.recorder
+}
+
+// Detokenize mocks base method.
+func (m *MockVault) Detokenize(ctx context.Context, token string) (*v1.PaymentMethodCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detokenize", ctx, token)
+	ret0, _ := ret[0].(*v1.PaymentMethodCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detokenize indicates an expected call of Detokenize.
+func (mr *MockVaultMockRecorder) Detokenize(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detokenize", reflect.TypeOf((*MockVault)(nil).Detokenize), ctx, token)
+}
+
+// Tokenize mocks base method.
+func (m *MockVault) Tokenize(ctx context.Context, card *v1.PaymentMethodCard) (*v1.PaymentMethodCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tokenize", ctx, card)
+	ret0, _ := ret[0].(*v1.PaymentMethodCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tokenize indicates an expected call of Tokenize.
+func (mr *MockVaultMockRecorder) Tokenize(ctx, card interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tokenize", reflect.TypeOf((*MockVault)(nil).Tokenize), ctx, card)
+}
+
+// MockRateProvider is a mock of RateProvider interface.
+type MockRateProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateProviderMockRecorder
+}
+
+// MockRateProviderMockRecorder is the mock recorder for MockRateProvider.
+type MockRateProviderMockRecorder struct {
+	mock *MockRateProvider
+}
+
+// NewMockRateProvider creates a new mock instance.
+func NewMockRateProvider(ctrl *gomock.Controller) *MockRateProvider {
+	mock := &MockRateProvider{ctrl: ctrl}
+	mock.recorder = &MockRateProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateProvider) EXPECT() *MockRateProviderMockRecorder {
+	return m.recorder
+}
+
+// Rate mocks base method.
+func (m *MockRateProvider) Rate(ctx context.Context, base, quote string) (domain.FXRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", ctx, base, quote)
+	ret0, _ := ret[0].(domain.FXRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *MockRateProviderMockRecorder) Rate(ctx, base, quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockRateProvider)(nil).Rate), ctx, base, quote)
+}
@@ -6,342 +6,1087 @@ import (
 	"context"
 	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
 	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
 )
 
 type Store interface {
 	ExecInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 
 	GetPayment(ctx context.Context, id string) (*paymentsV1.Payment, error)
+	GetPaymentForUpdate(ctx context.Context, id string) (*paymentsV1.Payment, error)
+	ListPayments(ctx context.Context, filters *domain.ListPaymentFilters) ([]*paymentsV1.Payment, error)
 	ListPaymentActions(ctx context.Context, filters *domain.ListPaymentActionFilters) ([]*paymentsV1.PaymentAction, error)
+	GetPaymentActionForUpdate(ctx context.Context, id string) (*paymentsV1.PaymentAction, error)
+	ClaimPaymentAction(ctx context.Context, id string) error
 
 	CreatePayment(ctx context.Context, payment *paymentsV1.Payment) error
 	CreatePaymentAction(ctx context.Context, action *paymentsV1.PaymentAction) error
 
 	UpdatePayment(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error
 	UpdatePaymentAction(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error
+
+	CreateOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error
+
+	CreateLedgerEntries(ctx context.Context, entries ...*domain.LedgerEntry) error
+	GetLedgerBalances(ctx context.Context, paymentID string) (domain.LedgerBalances, error)
+
+	GetMerchant(ctx context.Context, id string) (*domain.Merchant, error)
 }
 
 type IssuerGateway interface {
 	CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error)
+	// GetIssuerRequestStatus returns the outcome of a request previously made with the same reference,
+	// domain.ErrIssuerRequestNotFound is returned if the issuer never received it.
+	GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error)
+}
+
+// Vault tokenizes cards so that card numbers are never stored against payments.
+type Vault interface {
+	// Tokenize stores the card returning it with its token in place of the card number and CVV.
+	Tokenize(ctx context.Context, card *paymentsV1.PaymentMethodCard) (*paymentsV1.PaymentMethodCard, error)
+	// Detokenize returns the card with its card number, domain.ErrNoCardToken is returned for an unknown token.
+	Detokenize(ctx context.Context, token string) (*paymentsV1.PaymentMethodCard, error)
+}
+
+// RateProvider provides the FX rates payments are converted to their settlement currency by.
+type RateProvider interface {
+	// Rate returns the rate converting base to quote, domain.ErrNoFXRate is returned if there is none.
+	Rate(ctx context.Context, base, quote string) (domain.FXRate, error)
 }
 
 type Service struct {
-	store         Store
-	issuerGateway IssuerGateway
+	store              Store
+	issuerGateway      IssuerGateway
+	vault              Vault
+	rates              RateProvider
+	expiry             *domain.AuthorizationExpiry
+	asyncAuthorization bool
+}
+
+// Option configures optional behaviour of the Service.
+type Option func(s *Service)
+
+// WithAsyncAuthorization makes CreatePayment return the pending payment without waiting on the issuer.
+// Pending authorizations are then sent to the issuer in the background by ProcessAuthorization.
+func WithAsyncAuthorization() Option {
+	return func(s *Service) {
+		s.asyncAuthorization = true
+	}
+}
+
+// WithRateProvider allows payments to settle in a different currency, converted by the provider's rates.
+func WithRateProvider(rates RateProvider) Option {
+	return func(s *Service) {
+		s.rates = rates
+	}
+}
+
+// WithAuthorizationExpiry sets when authorizations expire, after which they can no longer be captured. Authorizations
+// never expire without it.
+func WithAuthorizationExpiry(expiry domain.AuthorizationExpiry) Option {
+	return func(s *Service) {
+		s.expiry = &expiry
+	}
 }
 
-func NewService(store Store, gateway IssuerGateway) Service {
-	return Service{store: store, issuerGateway: gateway}
+func NewService(store Store, gateway IssuerGateway, vault Vault, opts ...Option) Service {
+	s := Service{store: store, issuerGateway: gateway, vault: vault}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
 }
 
-func (s Service) CreatePayment(ctx context.Context, amount *amountV1.Money, method domain.PaymentMethod) (*paymentsV1.Payment, error) {
-	paymentType := paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION
+// CreatePayment creates the payment and authorizes it with the issuer. The card is either a raw card, which is
+// tokenized, or the token of a card already in the vault. Only the card's token, BIN and last four are stored.
+// Given a settlement currency other than the amount's the amount is converted to it, locking the current FX rate
+// against the payment.
+func (s Service) CreatePayment(ctx context.Context, amount *amountV1.Money, settlementCurrency string, method domain.PaymentMethod) (*paymentsV1.Payment, error) {
 	var (
 		payment       *paymentsV1.Payment
 		paymentAction *paymentsV1.PaymentAction
+		settlement    *amountV1.Money
+		fxRate        string
 	)
+	if settlementCurrency != "" && settlementCurrency != amount.Currency {
+		if s.rates == nil {
+			return nil, domain.ErrNoFXRate
+		}
+		rate, err := s.rates.Rate(ctx, amount.Currency, settlementCurrency)
+		if err != nil {
+			return nil, err
+		}
+		settled, err := rate.Convert(amount.MinorUnits)
+		if err != nil {
+			return nil, err
+		}
+		settlement, fxRate = currencyV1.NewMoney(settled, settlementCurrency), rate.Rate
+	}
+	if token := method.Card.GetToken(); token != "" {
+		card, err := s.vault.Detokenize(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		method = domain.PaymentMethod{Card: card}
+	}
+	if err := s.acceptCard(ctx, method.Card); err != nil {
+		return nil, err
+	}
 	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		card := maskedCard(method.Card)
+		if card.Token == "" {
+			var err error
+			if card, err = s.vault.Tokenize(ctx, method.Card); err != nil {
+				return err
+			}
+		}
 		payment = &paymentsV1.Payment{
-			Amount:        amount,
-			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: method.Card},
+			Amount:           currencyV1.NewMoney(amount.MinorUnits, amount.Currency),
+			PaymentStatus:    paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			PaymentMethod:    &paymentsV1.Payment_Card{Card: card},
+			SettlementAmount: settlement,
+			FxRate:           fxRate,
 		}
 		if err := s.store.CreatePayment(ctx, payment); err != nil {
 			return err
 		}
 		paymentAction = &paymentsV1.PaymentAction{
 			Amount:      amount.MinorUnits,
-			PaymentType: paymentType,
+			PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
 			PaymentId:   payment.Id,
 		}
+		if err := settle(payment, paymentAction); err != nil {
+			return err
+		}
 		if err := s.store.CreatePaymentAction(ctx, paymentAction); err != nil {
 			return err
 		}
 
-		return nil
+		return s.createEvent(ctx, payment.Id, &paymentsV1.PaymentCreated{
+			Payment:       eventPayment(payment),
+			PaymentAction: paymentAction,
+		})
 	}); err != nil {
 		return nil, err
 	}
 
-	// If more time would have done this part asynchronously from a PaymentCreatedEvent.
-	issuerResponse, err := s.issuerGateway.CreateIssuerRequest(ctx, domain.IssuerRequest{
-		Amount:        amount,
-		OperationType: paymentType,
-		PaymentMethod: method})
-	if err != nil {
+	if s.asyncAuthorization {
+		// the authorization is sent to the issuer by the authorization workers, see ProcessAuthorization.
+		return payment, nil
+	}
+
+	if err := s.authorize(ctx, payment, paymentAction, method); err != nil {
 		return nil, err
 	}
+	return payment, nil
+}
 
-	if err = s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
-		paymentAction.ResponseCode = issuerResponse.AuthCode
-		if err = s.store.UpdatePaymentAction(ctx, paymentAction, domain.UpdatePaymentActionFieldResponseCode); err != nil {
+// ListPendingAuthorizations returns the oldest authorizations that are yet to be sent to the issuer.
+func (s Service) ListPendingAuthorizations(ctx context.Context, limit uint64) ([]*paymentsV1.PaymentAction, error) {
+	return s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{
+		PaymentTypes: []domain.PaymentType{domain.PaymentTypeAuthorization},
+		Unprocessed:  true,
+		Unclaimed:    true,
+		Limit:        limit,
+	})
+}
+
+// ProcessAuthorization sends a pending authorization created in async mode to the issuer and updates the payment
+// with the outcome. The action is claimed in a short transaction so that concurrent workers never send it twice
+// without holding a transaction open whilst waiting on the issuer, domain.ErrPaymentActionClaimed is returned for an
+// action that is locked, already claimed or already processed, which is skipped. Should the outcome fail to be
+// recorded the action is left for the recovery sweeper.
+// As the card's CVV is never stored the card is sent to the issuer without it.
+func (s Service) ProcessAuthorization(ctx context.Context, paymentActionID string) error {
+	var (
+		payment       *paymentsV1.Payment
+		paymentAction *paymentsV1.PaymentAction
+		method        domain.PaymentMethod
+	)
+	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		action, err := s.store.GetPaymentActionForUpdate(ctx, paymentActionID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNoPaymentAction) {
+				// being claimed by another worker
+				return domain.ErrPaymentActionClaimed
+			}
+			return err
+		}
+		if action.ProcessedAt != nil || action.PaymentType != paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION {
+			return domain.ErrPaymentActionClaimed
+		}
+		if err = s.store.ClaimPaymentAction(ctx, paymentActionID); err != nil {
 			return err
 		}
 
-		// This will need more work on mappings
-		if issuerSuccess(issuerResponse.AuthCode) {
-			payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
-		} else {
-			payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED
+		if payment, err = s.store.GetPayment(ctx, action.PaymentId); err != nil {
+			return err
 		}
-		if err := s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus); err != nil {
+		if method, err = s.paymentMethod(ctx, payment); err != nil {
 			return err
 		}
+		paymentAction = action
 		return nil
 	}); err != nil {
-		// will need to alert on this as payment was successful
-		return nil, errors.Wrap(domain.ErrUpdatePaymentOutcome, err.Error())
+		return err
 	}
+	return s.authorize(ctx, payment, paymentAction, method)
+}
 
-	return payment, nil
+// ListUnresolvedPaymentActions returns the oldest payment actions sent to the issuer before the given time that are
+// yet to have an outcome recorded against them. An authorization made asynchronously is sent once a worker claims it
+// rather than when it is created.
+func (s Service) ListUnresolvedPaymentActions(ctx context.Context, createdBefore time.Time, limit uint64) ([]*paymentsV1.PaymentAction, error) {
+	return s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{
+		Unprocessed:   true,
+		CreatedBefore: createdBefore,
+		Limit:         limit,
+	})
 }
 
-// Capture is responsible for capturing funds in a payment.
-// The amount cannot exceed the existing auth amount and cannot capture unless the payment is in an authorized or partially captured state.
-// It can also not exceed the existing successful payment action amounts
-func (s Service) Capture(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error) {
-	paymentType := paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE
-	var (
-		payment       *paymentsV1.Payment
-		paymentAction *paymentsV1.PaymentAction
-		sumAction     uint64
-	)
-	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
-		var err error
-		payment, err = s.store.GetPayment(ctx, paymentID)
+// RecoverPaymentAction resolves a payment action left without an outcome, which happens when the call to the issuer
+// or the update following it fails. The issuer is asked for the outcome of the original request which is recorded
+// against the action, the payment status is then repaired. A request the issuer has no record of never took place
+// and is recorded as failed, unless it is an authorization that is still to be sent by the authorization workers as
+// none of them has claimed it.
+func (s Service) RecoverPaymentAction(ctx context.Context, paymentActionID string) error {
+	return s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		paymentAction, err := s.store.GetPaymentActionForUpdate(ctx, paymentActionID)
 		if err != nil {
+			if errors.Is(err, domain.ErrNoPaymentAction) {
+				return nil
+			}
 			return err
 		}
-
-		if amount > payment.Amount.GetMinorUnits() {
-			return domain.ErrNotPermitted
+		if paymentAction.ProcessedAt != nil {
+			return nil
 		}
 
-		if payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED && payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED {
-			return domain.ErrNotPermitted
-
+		payment, err := s.store.GetPayment(ctx, paymentAction.PaymentId)
+		if err != nil {
+			return err
 		}
-
-		if payment.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED {
-			actions, err := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: []string{paymentID}})
+		method, err := s.paymentMethod(ctx, payment)
+		if err != nil {
+			return err
+		}
+		var acquirer string
+		if paymentAction.PaymentType != paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION {
+			actions, err := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: []string{payment.Id}})
 			if err != nil {
 				return err
 			}
+			acquirer = authorizationAcquirer(actions)
+		}
 
-			for _, action := range actions {
-				if issuerSuccess(action.ResponseCode) {
-					if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE {
-						sumAction += action.Amount
-					}
-				}
+		issuerResponse, err := s.issuerGateway.GetIssuerRequestStatus(ctx, domain.IssuerRequest{
+			Reference: paymentAction.Id,
+			Amount: &amountV1.Money{
+				MinorUnits: paymentAction.Amount,
+				Currency:   payment.Amount.GetCurrency(),
+			},
+			OperationType: paymentAction.PaymentType,
+			PaymentMethod: method,
+			Acquirer:      acquirer})
+		if err != nil {
+			if !errors.Is(err, domain.ErrIssuerRequestNotFound) {
+				return err
 			}
-			if amount > payment.Amount.MinorUnits-sumAction {
-				return domain.ErrNotPermitted
+			if s.asyncAuthorization && paymentAction.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION {
+				// an authorization no worker has claimed is still to be sent, one that was claimed never reached the issuer
+				unclaimed, err := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{
+					PaymentIDs:   []string{payment.Id},
+					PaymentTypes: []domain.PaymentType{domain.PaymentTypeAuthorization},
+					Unclaimed:    true,
+				})
+				if err != nil {
+					return err
+				}
+				if len(unclaimed) != 0 {
+					return nil
+				}
 			}
+			issuerResponse = domain.IssuerResponse{AuthCode: domain.IssuerResponseCodeNoRecord, Acquirer: acquirer}
 		}
 
-		paymentAction = &paymentsV1.PaymentAction{
-			Amount:      amount,
-			PaymentType: paymentType,
-			PaymentId:   paymentID,
-		}
-		if err = s.store.CreatePaymentAction(ctx, paymentAction); err != nil {
-			return err
-		}
-		return nil
-	}); err != nil {
-		return nil, err
-	}
+		_, err = s.recordOutcome(ctx, paymentAction, issuerResponse)
+		return err
+	})
+}
 
+// authorize sends the authorization to the issuer and records the outcome against the payment. An authorization the
+// issuer's circuit breaker rejects is recorded as failed, declining the payment, see issuerRequestFailed.
+func (s Service) authorize(ctx context.Context, payment *paymentsV1.Payment, paymentAction *paymentsV1.PaymentAction, method domain.PaymentMethod) error {
+	if err := canApply(paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, payment); err != nil {
+		return err
+	}
 	issuerResponse, err := s.issuerGateway.CreateIssuerRequest(ctx, domain.IssuerRequest{
-		Amount: &amountV1.Money{
-			MinorUnits: amount,
-			Currency:   payment.Amount.Currency,
-		},
-		OperationType: paymentType,
-		PaymentMethod: domain.PaymentMethod{Card: payment.GetCard()}})
+		Reference:     paymentAction.Id,
+		Amount:        payment.Amount,
+		OperationType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+		PaymentMethod: method})
 	if err != nil {
-		return nil, err
+		return s.issuerRequestFailed(ctx, paymentAction, "", err)
 	}
 
 	if err = s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
-		paymentAction.ResponseCode = issuerResponse.AuthCode
+		setOutcome(paymentAction, issuerResponse)
 		if err = s.store.UpdatePaymentAction(ctx, paymentAction, domain.UpdatePaymentActionFieldResponseCode); err != nil {
 			return err
 		}
+		if err = s.postLedgerEntries(ctx, payment, paymentAction); err != nil {
+			return err
+		}
 
-		// This will need more work on mappings
-		if issuerSuccess(issuerResponse.AuthCode) {
-			if sumAction+amount == payment.Amount.MinorUnits {
-				payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED
-			} else {
-				payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED
-			}
-			if err := s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus); err != nil {
-				return err
-			}
+		payment.DeclineReason = paymentAction.DeclineReason
+		status := paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED
+		if issuerSuccess(paymentAction.ResponseCode) {
+			status = paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
+		}
+		if err = transition(payment, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, status); err != nil {
+			return err
+		}
+		if err := s.updatePayment(ctx, payment); err != nil {
+			return err
 		}
-		return nil
-	}); err != nil {
 
+		if payment.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED {
+			return s.createEvent(ctx, payment.Id, &paymentsV1.PaymentDeclined{
+				Payment:       eventPayment(payment),
+				PaymentAction: paymentAction,
+			})
+		}
+		return s.createEvent(ctx, payment.Id, &paymentsV1.PaymentAuthorized{
+			Payment:       eventPayment(payment),
+			PaymentAction: paymentAction,
+		})
+	}); err != nil {
 		// will need to alert on this as payment was successful
-		return nil, errors.Wrap(domain.ErrUpdatePaymentOutcome, err.Error())
+		return errors.Wrap(domain.ErrUpdatePaymentOutcome, err.Error())
 	}
-	return payment, nil
+	return nil
 }
 
+// Capture is responsible for capturing funds in a payment. The amount cannot exceed the authorized amount, including
+// any incremental authorizations, and cannot capture unless the payment is in an authorized or partially captured
+// state. It can also not exceed the existing successful payment action amounts. The payment is locked whilst checking
+// so that concurrent captures are made one after another, captures still awaiting an outcome from the issuer count
+// towards the captured amount so that together they can never exceed the payment amount. An authorization that has
+// expired can no longer be captured, domain.ErrAuthorizationExpired is returned. A final capture reverses whatever is
+// left uncaptured once it succeeds, should the reversal fail the captured payment is still returned with the remainder
+// held.
+func (s Service) Capture(ctx context.Context, paymentID string, amount uint64, final bool) (*paymentsV1.Payment, error) {
+	payment, paymentAction, err := s.applyOperation(ctx, paymentID, operation{
+		paymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
+		expires:     true,
+		amount: func(ctx context.Context, payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) (uint64, error) {
+			if voidInFlight(actions) {
+				return 0, domain.ErrNotPermitted
+			}
+			balances, err := s.store.GetLedgerBalances(ctx, paymentID)
+			if err != nil {
+				return 0, err
+			}
+			if amount > uncapturedAmount(balances, actions) {
+				return 0, domain.ErrNotPermitted
+			}
+			return amount, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !final || payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED || !issuerSuccess(paymentAction.ResponseCode) {
+		return payment, nil
+	}
+	reversed, err := s.Reverse(ctx, paymentID, 0)
+	if err != nil {
+		log.WithError(err).WithField("payment.id", paymentID).Warn("unable to reverse the remainder of a final capture")
+		return payment, nil
+	}
+	return reversed, nil
+}
+
+// Refund is responsible for refunding captured funds in a payment.
+// The amount cannot exceed the captured amount that is yet to be refunded. The payment is locked whilst checking so
+// that concurrent refunds are made one after another, refunds still awaiting an outcome from the issuer count towards
+// the refunded amount so that together they can never exceed the captured amount.
 func (s Service) Refund(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error) {
-	paymentType := paymentsV1.PaymentType_PAYMENT_TYPE_REFUND
+	payment, _, err := s.applyOperation(ctx, paymentID, operation{
+		paymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND,
+		amount: func(ctx context.Context, payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) (uint64, error) {
+			balances, err := s.store.GetLedgerBalances(ctx, paymentID)
+			if err != nil {
+				return 0, err
+			}
+			// refunds awaiting an outcome from the issuer are yet to be posted so count towards the amount refunded
+			var refunding uint64
+			for _, action := range actions {
+				if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_REFUND && inFlight(action) {
+					refunding += action.Amount
+				}
+			}
+			if refunding+amount > balances.Payable() {
+				return 0, domain.ErrNotPermitted
+			}
+			return amount, nil
+		},
+	})
+	return payment, err
+}
+
+// Void is responsible for cancelling an authorized payment, releasing the authorized amount including any incremental
+// authorizations. The payment is locked whilst checking, a void is not permitted whilst a capture or incremental
+// authorization is awaiting an outcome from the issuer.
+func (s Service) Void(ctx context.Context, paymentID string) (*paymentsV1.Payment, error) {
+	payment, _, err := s.applyOperation(ctx, paymentID, operation{
+		paymentType: paymentsV1.PaymentType_PAYMENT_TYPE_VOID,
+		amount: func(ctx context.Context, payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) (uint64, error) {
+			for _, action := range actions {
+				if action.PaymentType != paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION && inFlight(action) {
+					return 0, domain.ErrNotPermitted
+				}
+			}
+			balances, err := s.store.GetLedgerBalances(ctx, paymentID)
+			if err != nil {
+				return 0, err
+			}
+			return balances.Held(), nil
+		},
+	})
+	return payment, err
+}
+
+// IncrementAuthorization increases the amount held by an authorized payment, for when the final amount is only known
+// later such as a hotel stay. A successful increment adds to the amount that can be captured and, when authorizations
+// expire, reauthorizes the payment renewing when it expires. A declined increment leaves the existing authorization
+// as is. The payment is locked whilst checking, an increment is not permitted whilst a void is awaiting an outcome
+// from the issuer or once the authorization has expired.
+func (s Service) IncrementAuthorization(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error) {
+	payment, _, err := s.applyOperation(ctx, paymentID, operation{
+		paymentType: paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION,
+		expires:     true,
+		amount: func(ctx context.Context, payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) (uint64, error) {
+			if voidInFlight(actions) {
+				return 0, domain.ErrNotPermitted
+			}
+			return amount, nil
+		},
+	})
+	return payment, err
+}
+
+// Reverse releases part of the authorized amount that is yet to be captured, a zero amount releasing all of it. It
+// allows a partially captured payment's remaining hold to be released, moving it to captured, as well as reducing the
+// hold of an authorized payment. Reversing all that is left of an authorized payment voids it. The payment is locked
+// whilst checking, captures and reversals still awaiting an outcome from the issuer count towards the amount that can
+// no longer be reversed.
+func (s Service) Reverse(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error) {
+	payment, _, err := s.applyOperation(ctx, paymentID, operation{
+		paymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL,
+		amount: func(ctx context.Context, payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) (uint64, error) {
+			if voidInFlight(actions) {
+				return 0, domain.ErrNotPermitted
+			}
+			balances, err := s.store.GetLedgerBalances(ctx, paymentID)
+			if err != nil {
+				return 0, err
+			}
+			uncaptured := uncapturedAmount(balances, actions)
+			if amount == 0 {
+				amount = uncaptured
+			}
+			if amount == 0 || amount > uncaptured {
+				return 0, domain.ErrNotPermitted
+			}
+			return amount, nil
+		},
+	})
+	return payment, err
+}
+
+// operation is an action made with the issuer against an existing payment.
+type operation struct {
+	paymentType paymentsV1.PaymentType
+	// expires rejects the operation with domain.ErrAuthorizationExpired once the payment's authorization has expired.
+	expires bool
+	// amount checks the operation is permitted given the payment's existing actions, returning the amount of the
+	// action to be made. It is called with the payment locked.
+	amount func(ctx context.Context, payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) (uint64, error)
+}
+
+// applyOperation makes the operation against the payment with the issuer, returning the payment along with the action
+// once its outcome has been recorded. The payment is locked whilst the operation is checked and its action created so
+// that operations are checked one after another. A payment the operation cannot be made from is declined.
+func (s Service) applyOperation(ctx context.Context, paymentID string, op operation) (*paymentsV1.Payment, *paymentsV1.PaymentAction, error) {
 	var (
 		payment       *paymentsV1.Payment
 		paymentAction *paymentsV1.PaymentAction
-		sumAction     uint64
+		method        domain.PaymentMethod
+		acquirer      string
 	)
 	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
 		var err error
-		payment, err = s.store.GetPayment(ctx, paymentID)
+		payment, err = s.store.GetPaymentForUpdate(ctx, paymentID)
 		if err != nil {
 			return err
 		}
 
-		if amount > payment.Amount.GetMinorUnits() {
-			return domain.ErrNotPermitted
+		if op.expires && authorizationExpired(payment) {
+			return domain.ErrAuthorizationExpired
 		}
-
-		if payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED && payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED && payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED {
-			return domain.ErrNotPermitted
+		if err = canApply(op.paymentType, payment); err != nil {
+			return s.declined(ctx, payment, err)
 		}
 
 		actions, err := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: []string{paymentID}})
 		if err != nil {
 			return err
 		}
-
-		for _, action := range actions {
-			if issuerSuccess(action.ResponseCode) {
-				if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE {
-					sumAction += action.Amount
-				}
-				if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_REFUND {
-					sumAction -= action.Amount
-				}
-			}
+		amount, err := op.amount(ctx, payment, actions)
+		if err != nil {
+			return err
 		}
-		if amount > sumAction && sumAction > 0 {
-			return domain.ErrNotPermitted
+
+		if method, err = s.paymentMethod(ctx, payment); err != nil {
+			return err
 		}
+		acquirer = authorizationAcquirer(actions)
 		paymentAction = &paymentsV1.PaymentAction{
 			Amount:      amount,
-			PaymentType: paymentType,
+			PaymentType: op.paymentType,
 			PaymentId:   paymentID,
 		}
-		if err = s.store.CreatePaymentAction(ctx, paymentAction); err != nil {
+		if err = settle(payment, paymentAction); err != nil {
 			return err
 		}
-		return nil
+		return s.store.CreatePaymentAction(ctx, paymentAction)
 	}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	issuerResponse, err := s.issuerGateway.CreateIssuerRequest(ctx, domain.IssuerRequest{
+		Reference: paymentAction.Id,
 		Amount: &amountV1.Money{
-			MinorUnits: amount,
+			MinorUnits: paymentAction.Amount,
 			Currency:   payment.Amount.Currency,
 		},
-		OperationType: paymentType,
-		PaymentMethod: domain.PaymentMethod{Card: payment.GetCard()}})
+		OperationType: op.paymentType,
+		PaymentMethod: method,
+		Acquirer:      acquirer})
 	if err != nil {
-		return nil, err
+		return nil, nil, s.issuerRequestFailed(ctx, paymentAction, acquirer, err)
 	}
 
 	if err = s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
-		paymentAction.ResponseCode = issuerResponse.AuthCode
-		if err = s.store.UpdatePaymentAction(ctx, paymentAction, domain.UpdatePaymentActionFieldResponseCode); err != nil {
-			return err
-		}
-
-		// This will need more work on mappings
-		if issuerSuccess(issuerResponse.AuthCode) {
-			if sumAction+amount == payment.Amount.MinorUnits || amount == sumAction {
-				payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED
-			} else {
-				payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED
-			}
-			if err := s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus); err != nil {
-				return err
-			}
-		}
-		return nil
+		payment, err = s.recordOutcome(ctx, paymentAction, issuerResponse)
+		return err
 	}); err != nil {
-		// will need to alert on this as payment was successful
-		return nil, errors.Wrap(domain.ErrUpdatePaymentOutcome, err.Error())
+		// will need to alert on this as the issuer has made the operation
+		return nil, nil, errors.Wrap(domain.ErrUpdatePaymentOutcome, err.Error())
 	}
-	return payment, nil
+	return payment, paymentAction, nil
 }
 
-func (s Service) Void(ctx context.Context, paymentID string) (*paymentsV1.Payment, error) {
-	paymentType := paymentsV1.PaymentType_PAYMENT_TYPE_VOID
-	var (
-		payment       *paymentsV1.Payment
-		paymentAction *paymentsV1.PaymentAction
-	)
+// ListExpiredAuthorizations returns authorized and partially captured payments whose authorization expired before the
+// given time, both still holding funds that are yet to be released.
+func (s Service) ListExpiredAuthorizations(ctx context.Context, expiredBefore time.Time, limit uint64) ([]*paymentsV1.Payment, error) {
+	return s.store.ListPayments(ctx, &domain.ListPaymentFilters{
+		Statuses:      []domain.PaymentStatus{domain.PaymentStatusAuthorized, domain.PaymentStatusPartiallyCaptured},
+		ExpiresBefore: expiredBefore,
+		Limit:         limit,
+	})
+}
+
+// ExpireAuthorization marks an authorization that has expired as such without going to the issuer, for when the hold
+// has already been released by the scheme and the issuer will not void or reverse it. The release is posted to the
+// ledger. A partially captured payment keeps what was captured, it is captured rather than expired with the remainder
+// released. The payment is locked whilst checking, it is not permitted whilst an action is awaiting an outcome from the
+// issuer or before the authorization has expired.
+func (s Service) ExpireAuthorization(ctx context.Context, paymentID string) (*paymentsV1.Payment, error) {
+	var payment *paymentsV1.Payment
 	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
 		var err error
-		payment, err = s.store.GetPayment(ctx, paymentID)
+		payment, err = s.store.GetPaymentForUpdate(ctx, paymentID)
 		if err != nil {
 			return err
 		}
-
-		if payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED {
+		if payment.ExpiresAt == nil || payment.ExpiresAt.AsTime().After(time.Now()) {
 			return domain.ErrNotPermitted
 		}
 
-		paymentAction = &paymentsV1.PaymentAction{
-			Amount:      payment.Amount.GetMinorUnits(),
-			PaymentType: paymentType,
-			PaymentId:   paymentID,
+		actions, err := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: []string{paymentID}})
+		if err != nil {
+			return err
+		}
+		for _, action := range actions {
+			if inFlight(action) {
+				return domain.ErrNotPermitted
+			}
+		}
+
+		paymentType, status := paymentsV1.PaymentType_PAYMENT_TYPE_VOID, paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED
+		if payment.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED {
+			paymentType, status = paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL, paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED
 		}
-		if err = s.store.CreatePaymentAction(ctx, paymentAction); err != nil {
+		if err = transition(payment, paymentType, status); err != nil {
 			return err
 		}
-		return nil
+		if err = s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus); err != nil {
+			return err
+		}
+		// the scheme releases whatever is still held without the issuer being asked
+		balances, err := s.store.GetLedgerBalances(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		if release := domain.NewLedgerRelease(paymentID, balances.Held(), payment.Amount.GetCurrency()); len(release) != 0 {
+			if err = s.store.CreateLedgerEntries(ctx, release...); err != nil {
+				return err
+			}
+		}
+		return s.createEvent(ctx, payment.Id, &paymentsV1.PaymentExpired{Payment: eventPayment(payment)})
 	}); err != nil {
 		return nil, err
 	}
+	return payment, nil
+}
 
-	issuerResponse, err := s.issuerGateway.CreateIssuerRequest(ctx, domain.IssuerRequest{
-		Amount: &amountV1.Money{
-			MinorUnits: payment.Amount.GetMinorUnits(),
-			Currency:   payment.Amount.Currency,
-		},
-		OperationType: paymentType,
-		PaymentMethod: domain.PaymentMethod{Card: payment.GetCard()}})
+// issuerRequestFailed handles the issuer request made for the payment action failing, returning the error. A request
+// rejected without being sent, as the issuer's circuit breaker is open, is recorded as failed so that it does not hold
+// up later actions on the payment until the recovery sweeper resolves it. Any other failure may have reached the
+// issuer so is left for the sweeper.
+func (s Service) issuerRequestFailed(ctx context.Context, paymentAction *paymentsV1.PaymentAction, acquirer string, err error) error {
+	if !errors.Is(err, domain.ErrCircuitOpen) {
+		return err
+	}
+	if recordErr := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.recordOutcome(ctx, paymentAction, domain.IssuerResponse{AuthCode: domain.IssuerResponseCodeNoRecord, Acquirer: acquirer})
+		return err
+	}); recordErr != nil {
+		log.WithError(recordErr).WithField("payment_action.id", paymentAction.Id).Error("failed to record payment action rejected by the circuit breaker")
+	}
+	return err
+}
+
+// recordOutcome records the issuer's response against the payment action and moves the payment on from it, writing
+// the event describing the change. A successful outcome is posted to the ledger, the payment is then locked and its
+// balances read so that the outcomes of concurrent actions on the same payment are all accounted for. It must be called
+// within ExecInTransaction.
+func (s Service) recordOutcome(ctx context.Context, paymentAction *paymentsV1.PaymentAction, issuerResponse domain.IssuerResponse) (*paymentsV1.Payment, error) {
+	setOutcome(paymentAction, issuerResponse)
+	if err := s.store.UpdatePaymentAction(ctx, paymentAction, domain.UpdatePaymentActionFieldResponseCode); err != nil {
+		return nil, err
+	}
+
+	payment, err := s.store.GetPaymentForUpdate(ctx, paymentAction.PaymentId)
 	if err != nil {
 		return nil, err
 	}
+	payment.DeclineReason = paymentAction.DeclineReason
+	if err = s.postLedgerEntries(ctx, payment, paymentAction); err != nil {
+		return nil, err
+	}
+	balances, err := s.store.GetLedgerBalances(ctx, payment.Id)
+	if err != nil {
+		return nil, err
+	}
+	event := nextPaymentStatus(payment, paymentAction, balances)
+	if event == nil {
+		return payment, nil
+	}
+	if err = s.updatePayment(ctx, payment); err != nil {
+		return nil, err
+	}
+	return payment, s.createEvent(ctx, payment.Id, event)
+}
 
-	if err = s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
-		paymentAction.ResponseCode = issuerResponse.AuthCode
-		if err = s.store.UpdatePaymentAction(ctx, paymentAction, domain.UpdatePaymentActionFieldResponseCode); err != nil {
-			return err
+// GetPayment returns the payment along with the actions made towards it and the balances derived from them.
+func (s Service) GetPayment(ctx context.Context, paymentID string) (*paymentsV1.PaymentDetails, error) {
+	payment, err := s.store.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: []string{paymentID}})
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.store.GetLedgerBalances(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED {
+		for _, action := range actions {
+			if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION {
+				payment.DeclineReason = action.DeclineReason
+			}
 		}
-		// This will need more work on mappings
-		if issuerSuccess(issuerResponse.AuthCode) {
-			payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED
-			if err = s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus); err != nil {
-				return err
+	}
+	return &paymentsV1.PaymentDetails{
+		Payment:        payment,
+		Balance:        paymentBalance(balances, actions),
+		PaymentActions: actions,
+	}, nil
+}
+
+// ListPaymentActions returns the actions made towards a payment.
+func (s Service) ListPaymentActions(ctx context.Context, paymentID string) ([]*paymentsV1.PaymentAction, error) {
+	if _, err := s.store.GetPayment(ctx, paymentID); err != nil {
+		return nil, err
+	}
+	return s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: []string{paymentID}})
+}
+
+// ListPayments returns a page of payments matching the filters along with the cursor for the next page.
+func (s Service) ListPayments(ctx context.Context, filters domain.ListPaymentFilters) (*paymentsV1.PaymentList, error) {
+	limit := filters.Limit
+	if limit == 0 {
+		limit = domain.DefaultListPaymentsLimit
+	}
+	if limit > domain.MaxListPaymentsLimit {
+		limit = domain.MaxListPaymentsLimit
+	}
+	// fetch one more than requested to know whether there is a next page
+	filters.Limit = limit + 1
+	payments, err := s.store.ListPayments(ctx, &filters)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &paymentsV1.PaymentList{Payments: payments}
+	if uint64(len(payments)) > limit {
+		list.Payments = payments[:limit]
+		last := list.Payments[limit-1]
+		list.NextCursor = domain.PaymentCursor{ID: last.Id}.Encode()
+	}
+	return list, nil
+}
+
+// paymentBalance returns the payment's balances from the ledger, the reversed balance is summed from its successful
+// reversals as the ledger does not tell them apart from other releases of the hold.
+func paymentBalance(balances domain.LedgerBalances, actions []*paymentsV1.PaymentAction) *paymentsV1.PaymentBalance {
+	balance := &paymentsV1.PaymentBalance{
+		Authorized: balances.Held() + nonNegative(balances.Captured),
+		Captured:   nonNegative(balances.Captured),
+		Refunded:   nonNegative(balances.Refunded),
+		Remaining:  balances.Held(),
+	}
+	for _, action := range actions {
+		if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL && issuerSuccess(action.ResponseCode) {
+			balance.Reversed += action.Amount
+		}
+	}
+	return balance
+}
+
+func nonNegative(balance int64) uint64 {
+	if balance < 0 {
+		return 0
+	}
+	return uint64(balance)
+}
+
+// uncapturedAmount returns the amount held on the customer's card that is left to be captured or reversed. Captures
+// and reversals still awaiting an outcome from the issuer are yet to be posted to the ledger, they are counted as
+// though they will succeed.
+func uncapturedAmount(balances domain.LedgerBalances, actions []*paymentsV1.PaymentAction) uint64 {
+	var pending uint64
+	for _, action := range actions {
+		switch action.PaymentType {
+		case paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL:
+			if inFlight(action) {
+				pending += action.Amount
 			}
 		}
+	}
+	if pending > balances.Held() {
+		return 0
+	}
+	return balances.Held() - pending
+}
+
+// nextPaymentStatus moves the payment to the status following the outcome of the action, returning the event
+// describing the change. The balances include the action's outcome. The payment is left as is and no event returned
+// when the action failed or the payment has since moved to a status the action no longer applies to.
+func nextPaymentStatus(payment *paymentsV1.Payment, action *paymentsV1.PaymentAction, balances domain.LedgerBalances) proto.Message {
+	if canApply(action.PaymentType, payment) != nil {
 		return nil
-	}); err != nil {
-		// will need to alert on this as payment was successful
-		return nil, errors.Wrap(domain.ErrUpdatePaymentOutcome, err.Error())
 	}
-	return payment, nil
+	if action.PaymentType != paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION && !issuerSuccess(action.ResponseCode) {
+		return nil
+	}
+
+	var status paymentsV1.PaymentStatus
+	switch action.PaymentType {
+	case paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION:
+		status = paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
+	case paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL:
+		// a refunded payment stays refunded once the rest of its hold is released
+		status = payment.PaymentStatus
+		if balances.Held() == 0 {
+			switch payment.PaymentStatus {
+			case paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED:
+				status = paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED
+			case paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED:
+				status = paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED
+			}
+		}
+	case paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION:
+		status = paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED
+		if issuerSuccess(action.ResponseCode) {
+			status = paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
+		}
+	case paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE:
+		status = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED
+		if balances.Held() == 0 {
+			status = paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED
+		}
+	case paymentsV1.PaymentType_PAYMENT_TYPE_REFUND:
+		status = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED
+		if balances.Payable() == 0 {
+			status = paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED
+		}
+	case paymentsV1.PaymentType_PAYMENT_TYPE_VOID:
+		status = paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED
+	}
+	if transition(payment, action.PaymentType, status) != nil {
+		return nil
+	}
+
+	switch action.PaymentType {
+	case paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION:
+		return &paymentsV1.PaymentAuthorizationIncremented{Payment: eventPayment(payment), PaymentAction: action}
+	case paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL:
+		return &paymentsV1.PaymentAuthorizationReversed{Payment: eventPayment(payment), PaymentAction: action}
+	}
+	switch status {
+	case paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED:
+		return &paymentsV1.PaymentAuthorized{Payment: eventPayment(payment), PaymentAction: action}
+	case paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED:
+		return &paymentsV1.PaymentDeclined{Payment: eventPayment(payment), PaymentAction: action}
+	case paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED, paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED:
+		return &paymentsV1.PaymentCaptured{Payment: eventPayment(payment), PaymentAction: action}
+	case paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED, paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED:
+		return &paymentsV1.PaymentRefunded{Payment: eventPayment(payment), PaymentAction: action}
+	default:
+		return &paymentsV1.PaymentVoided{Payment: eventPayment(payment), PaymentAction: action}
+	}
+}
+
+// updatePayment updates the payment's status, a payment that has just been authorized also has when its authorization
+// expires set if authorizations are configured to expire.
+func (s Service) updatePayment(ctx context.Context, payment *paymentsV1.Payment) error {
+	if s.expiry == nil || payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED {
+		return s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus)
+	}
+	brand := cardBrand(payment.GetCard())
+	payment.ExpiresAt = timestamppb.New(s.expiry.ExpiresAt(brand, time.Now()))
+	return s.store.UpdatePayment(ctx, payment, domain.UpdatePaymentFieldStatus, domain.UpdatePaymentFieldExpiresAt)
+}
+
+// authorizationExpired reports whether the payment's authorization has expired, whether or not it has been marked
+// as expired yet.
+func authorizationExpired(payment *paymentsV1.Payment) bool {
+	if payment.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED {
+		return true
+	}
+	return payment.ExpiresAt != nil && !payment.ExpiresAt.AsTime().After(time.Now())
+}
+
+// inFlight reports whether the action is still awaiting an outcome from the issuer.
+func inFlight(action *paymentsV1.PaymentAction) bool {
+	return action.ProcessedAt == nil && action.ResponseCode == ""
+}
+
+// voidInFlight reports whether a void of the payment is still awaiting an outcome from the issuer.
+func voidInFlight(actions []*paymentsV1.PaymentAction) bool {
+	for _, action := range actions {
+		if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_VOID && inFlight(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// canApply checks the payment type can be made against the payment in its current status.
+func canApply(paymentType paymentsV1.PaymentType, payment *paymentsV1.Payment) error {
+	return domain.PaymentStateMachine.CanApply(toDomainPaymentType(paymentType), toDomainPaymentStatus(payment.PaymentStatus))
+}
+
+// transition moves the payment to the status through the payment type if the state machine permits it.
+func transition(payment *paymentsV1.Payment, paymentType paymentsV1.PaymentType, to paymentsV1.PaymentStatus) error {
+	if err := domain.PaymentStateMachine.Transition(toDomainPaymentType(paymentType), toDomainPaymentStatus(payment.PaymentStatus), toDomainPaymentStatus(to)); err != nil {
+		return err
+	}
+	payment.PaymentStatus = to
+	return nil
+}
+
+// toDomainPaymentType converts the payment type, unknown types are left empty which the state machine never permits.
+func toDomainPaymentType(paymentType paymentsV1.PaymentType) domain.PaymentType {
+	var p domain.PaymentType
+	_ = p.FromProto(paymentType)
+	return p
+}
+
+// toDomainPaymentStatus converts the payment status, unknown statuses are left empty which the state machine never permits.
+func toDomainPaymentStatus(status paymentsV1.PaymentStatus) domain.PaymentStatus {
+	var p domain.PaymentStatus
+	_ = p.FromProto(status)
+	return p
+}
+
+// declined attaches the reason the issuer declined the payment to the error rejecting an action on it, so that clients
+// know why it can no longer be acted upon. The error is returned as is for other payments, or should the reason be
+// unable to be read.
+func (s Service) declined(ctx context.Context, payment *paymentsV1.Payment, err error) error {
+	if payment.PaymentStatus != paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED {
+		return err
+	}
+	actions, listErr := s.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{
+		PaymentIDs:   []string{payment.Id},
+		PaymentTypes: []domain.PaymentType{domain.PaymentTypeAuthorization},
+	})
+	if listErr != nil {
+		return err
+	}
+	for _, action := range actions {
+		if action.DeclineReason != "" {
+			return domain.DeclinedError{Err: err, Reason: action.DeclineReason}
+		}
+	}
+	return err
+}
+
+// postLedgerEntries posts the funds moved by a successful action to the ledger. It should be called within the same
+// transaction as the action's outcome is recorded.
+func (s Service) postLedgerEntries(ctx context.Context, payment *paymentsV1.Payment, action *paymentsV1.PaymentAction) error {
+	if !issuerSuccess(action.ResponseCode) {
+		return nil
+	}
+	entries := domain.NewLedgerEntries(payment.Id, action.Id, toDomainPaymentType(action.PaymentType), action.Amount, payment.Amount.GetCurrency())
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.store.CreateLedgerEntries(ctx, entries...)
+}
+
+// createEvent writes the event to the outbox. It should be called within the same
+// transaction as the state change the event describes.
+func (s Service) createEvent(ctx context.Context, paymentID string, event proto.Message) error {
+	outboxEvent, err := domain.NewOutboxEvent(paymentID, event)
+	if err != nil {
+		return err
+	}
+	return s.store.CreateOutboxEvent(ctx, outboxEvent)
+}
+
+// authorizationAcquirer returns the acquirer that authorized the payment, the actions following the authorization
+// are pinned to it.
+func authorizationAcquirer(actions []*paymentsV1.PaymentAction) string {
+	for _, action := range actions {
+		if action.PaymentType == paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION {
+			return action.Acquirer
+		}
+	}
+	return ""
+}
+
+// paymentMethod returns the payment's card from the vault to send to the issuer. Payments made before the vault
+// only hold the card's BIN and last four which are sent as they are.
+func (s Service) paymentMethod(ctx context.Context, payment *paymentsV1.Payment) (domain.PaymentMethod, error) {
+	token := payment.GetCard().GetToken()
+	if token == "" {
+		return domain.PaymentMethod{Card: payment.GetCard()}, nil
+	}
+	card, err := s.vault.Detokenize(ctx, token)
+	if err != nil {
+		return domain.PaymentMethod{}, err
+	}
+	return domain.PaymentMethod{Card: card}, nil
+}
+
+// maskedCard copies the card without its card number and CVV.
+func maskedCard(card *paymentsV1.PaymentMethodCard) *paymentsV1.PaymentMethodCard {
+	return &paymentsV1.PaymentMethodCard{
+		Token:    card.GetToken(),
+		Bin:      card.GetBin(),
+		LastFour: card.GetLastFour(),
+		Expiry:   card.GetExpiry(),
+		Brand:    string(cardBrand(card)),
+	}
+}
+
+// cardBrand returns the brand of the card, detecting it from the card number or BIN when it is not yet known.
+func cardBrand(card *paymentsV1.PaymentMethodCard) domain.CardBrand {
+	if card.GetBrand() != "" {
+		return domain.CardBrand(card.GetBrand())
+	}
+	if card.GetCardNumber() != "" {
+		return domain.CardBrandFromBIN(card.GetCardNumber())
+	}
+	return domain.CardBrandFromBIN(card.GetBin())
+}
+
+// acceptCard checks the merchant the payment is made for accepts the card's brand, domain.ErrCardBrandNotAccepted
+// is returned otherwise. Payments the gateway makes outside of a merchant accept every brand.
+func (s Service) acceptCard(ctx context.Context, card *paymentsV1.PaymentMethodCard) error {
+	merchantID, ok := domain.MerchantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	merchant, err := s.store.GetMerchant(ctx, merchantID)
+	if err != nil {
+		return err
+	}
+	if !merchant.Accepts(cardBrand(card)) {
+		return domain.ErrCardBrandNotAccepted
+	}
+	return nil
+}
+
+// settle converts the action's amount to the payment's settlement currency by the payment's locked FX rate. Each
+// action is converted on its own so partial amounts may not sum exactly to the payment's settlement amount.
+func settle(payment *paymentsV1.Payment, action *paymentsV1.PaymentAction) error {
+	if payment.SettlementAmount == nil {
+		return nil
+	}
+	rate := domain.FXRate{Base: payment.Amount.GetCurrency(), Quote: payment.SettlementAmount.GetCurrency(), Rate: payment.FxRate}
+	settled, err := rate.Convert(action.Amount)
+	if err != nil {
+		return err
+	}
+	action.SettlementAmount = settled
+	return nil
+}
+
+// eventPayment copies the payment for an event without its payment method.
+func eventPayment(payment *paymentsV1.Payment) *paymentsV1.Payment {
+	p := proto.Clone(payment).(*paymentsV1.Payment)
+	p.PaymentMethod = nil
+	return p
+}
+
+// setOutcome records the issuer's response code against the action along with its category, the reason
+// for any decline and the acquirer that handled it.
+func setOutcome(action *paymentsV1.PaymentAction, issuerResponse domain.IssuerResponse) {
+	code := issuerResponse.AuthCode
+	responseCode := domain.LookupResponseCode(code)
+	action.ResponseCode = code
+	action.Acquirer = issuerResponse.Acquirer
+	action.ResponseCategory = responseCode.Category.ToProto()
+	action.DeclineReason = ""
+	if !responseCode.Approved() {
+		action.DeclineReason = responseCode.Reason
+	}
 }
 
 func issuerSuccess(code string) bool {
-	return code == "00"
+	return domain.LookupResponseCode(code).Approved()
 }
@@ -2,6 +2,7 @@ package gateway_test
 
 import (
 	"context"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
 	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
@@ -12,23 +13,68 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"testing"
+	"time"
 )
 
+// protoEq matches proto messages by their content rather than their internal state.
+func protoEq(msg proto.Message) gomock.Matcher {
+	return protoMatcher{msg: msg}
+}
+
+type protoMatcher struct {
+	msg proto.Message
+}
+
+func (m protoMatcher) Matches(x interface{}) bool {
+	msg, ok := x.(proto.Message)
+	return ok && proto.Equal(m.msg, msg)
+}
+
+func (m protoMatcher) String() string {
+	return fmt.Sprintf("is equal to %v", m.msg)
+}
+
+// ledgerBalances returns the balances of a payment's ledger holding the amount, with that captured and refunded.
+func ledgerBalances(held, captured, refunded int64) domain.LedgerBalances {
+	return domain.LedgerBalances{
+		Customer:        held,
+		Authorized:      held,
+		Captured:        captured,
+		Refunded:        refunded,
+		MerchantPayable: captured - refunded,
+	}
+}
+
 func TestService_CreatePayment_Error(t *testing.T) {
 	t.Parallel()
+	tokenizedCard := &paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "100000", LastFour: "0000"}
 	for _, tc := range []struct {
 		description string
-		fn          func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway)
+		fn          func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault)
 		err         error
 	}{
+		{
+			description: "should return an error if unable to tokenize the card",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
 		{
 			description: "should return an error if unable to create pending payment",
-			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
 					})
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
 				store.
 					EXPECT().
 					CreatePayment(gomock.Any(), gomock.Any()).
@@ -38,12 +84,34 @@ func TestService_CreatePayment_Error(t *testing.T) {
 		},
 		{
 			description: "should return an error if unable to create payment action",
-			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
+				store.
+					EXPECT().
+					CreatePayment(gomock.Any(), gomock.Any()).
+					Return(nil)
+
+				store.
+					EXPECT().
+					CreatePaymentAction(gomock.Any(), gomock.Any()).
+					Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return an error if unable to create payment created event",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
 					})
 
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
 				store.
 					EXPECT().
 					CreatePayment(gomock.Any(), gomock.Any()).
@@ -52,17 +120,21 @@ func TestService_CreatePayment_Error(t *testing.T) {
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
+					Return(nil)
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
 					Return(errors.New("error"))
 			},
 			err: errors.New("error"),
 		},
 		{
 			description: "should return an error if unable to call payment gateway",
-			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
 					})
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
 				store.
 					EXPECT().
 					CreatePayment(gomock.Any(), gomock.Any()).
@@ -72,6 +144,9 @@ func TestService_CreatePayment_Error(t *testing.T) {
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
 					Return(nil)
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					Return(nil)
 
 				gateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).
 					Return(domain.IssuerResponse{}, errors.New("error"))
@@ -81,11 +156,12 @@ func TestService_CreatePayment_Error(t *testing.T) {
 		},
 		{
 			description: "should return an error if unable to update payment action",
-			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
 					})
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
 				store.
 					EXPECT().
 					CreatePayment(gomock.Any(), gomock.Any()).
@@ -95,6 +171,9 @@ func TestService_CreatePayment_Error(t *testing.T) {
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
 					Return(nil)
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					Return(nil)
 
 				gateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).
 					Return(domain.IssuerResponse{AuthCode: "00"}, nil)
@@ -112,11 +191,12 @@ func TestService_CreatePayment_Error(t *testing.T) {
 		},
 		{
 			description: "should return an error if unable to update payment",
-			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 						return fn(ctx)
 					})
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
 				store.
 					EXPECT().
 					CreatePayment(gomock.Any(), gomock.Any()).
@@ -126,6 +206,9 @@ func TestService_CreatePayment_Error(t *testing.T) {
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
 					Return(nil)
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					Return(nil)
 
 				gateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).
 					Return(domain.IssuerResponse{AuthCode: "00"}, nil)
@@ -137,12 +220,33 @@ func TestService_CreatePayment_Error(t *testing.T) {
 				store.EXPECT().
 					UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil)
+				store.EXPECT().
+					CreateLedgerEntries(gomock.Any(), gomock.Any()).
+					Return(nil)
 				store.EXPECT().
 					UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(errors.New("error"))
 
 			},
 			err: errors.Wrap(domain.ErrUpdatePaymentOutcome, "error"),
+		},
+		{
+			description: "should return an error if unable to post the authorization to the ledger",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					}).Times(2)
+				vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(tokenizedCard, nil)
+				store.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil)
+				gateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).
+					Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+				store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			err: errors.Wrap(domain.ErrUpdatePaymentOutcome, "error"),
 		}} {
 		tc := tc
 		t.Run(tc.description, func(t *testing.T) {
@@ -152,15 +256,16 @@ func TestService_CreatePayment_Error(t *testing.T) {
 
 				mockStore         = mocks.NewMockStore(ctrl)
 				mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+				mockVault         = mocks.NewMockVault(ctrl)
 			)
 			if tc.fn != nil {
-				tc.fn(mockStore, mockIssuerGateway)
+				tc.fn(mockStore, mockIssuerGateway, mockVault)
 			}
-			service := gateway.NewService(mockStore, mockIssuerGateway)
+			service := gateway.NewService(mockStore, mockIssuerGateway, mockVault)
 			_, err := service.CreatePayment(context.Background(), &amountV1.Money{
 				MinorUnits: 10000,
 				Currency:   "GBP",
-			}, domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{
+			}, "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{
 				CardNumber: "10000000000000000",
 			},
 			})
@@ -178,20 +283,23 @@ func TestService_CreatePayment_Success(t *testing.T) {
 
 		store         = mocks.NewMockStore(ctrl)
 		issuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		vault         = mocks.NewMockVault(ctrl)
 		amount        = &amountV1.Money{
 			MinorUnits: 10000,
 			Currency:   "GBP",
 		}
 		method = domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{
 			CardNumber: "10000000000000000",
+			Cvv:        "123",
 		},
 		}
+		tokenizedCard = &paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "100000", LastFour: "0000"}
 
 		paymentID = uuid.NewV4().String()
 		payment   = &paymentsV1.Payment{
-			Amount:        amount,
+			Amount:        &amountV1.Money{MinorUnits: 10000, Currency: "GBP", MajorUnits: "100.00"},
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: method.Card},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: tokenizedCard},
 		}
 		paymentAction = &paymentsV1.PaymentAction{
 			Amount:      amount.MinorUnits,
@@ -204,6 +312,7 @@ func TestService_CreatePayment_Success(t *testing.T) {
 		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 			return fn(ctx)
 		})
+	vault.EXPECT().Tokenize(gomock.Any(), method.Card).Return(tokenizedCard, nil)
 	store.
 		EXPECT().
 		CreatePayment(gomock.Any(), payment).DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment) error {
@@ -216,9 +325,16 @@ func TestService_CreatePayment_Success(t *testing.T) {
 		EXPECT().
 		CreatePaymentAction(gomock.Any(), paymentAction).
 		Return(nil)
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+			assert.Equal(t, "shared.payment.v1.PaymentCreated", event.EventType)
+			assert.Equal(t, paymentID, event.PaymentID.String())
+			return nil
+		})
 
 	issuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), domain.IssuerRequest{
-		Amount:        amount,
+		Amount:        payment.Amount,
 		OperationType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
 		PaymentMethod: method}).
 		Return(domain.IssuerResponse{AuthCode: "00"}, nil)
@@ -230,24 +346,192 @@ func TestService_CreatePayment_Success(t *testing.T) {
 
 	store.EXPECT().
 		UpdatePaymentAction(gomock.Any(), &paymentsV1.PaymentAction{
-			Amount:       paymentAction.Amount,
-			PaymentType:  paymentAction.PaymentType,
-			ResponseCode: "00",
-			PaymentId:    paymentAction.PaymentId,
+			Amount:           paymentAction.Amount,
+			PaymentType:      paymentAction.PaymentType,
+			ResponseCode:     "00",
+			ResponseCategory: paymentsV1.ResponseCategory_RESPONSE_CATEGORY_APPROVED,
+			PaymentId:        paymentAction.PaymentId,
 		}, domain.UpdatePaymentActionFieldResponseCode).
 		Return(nil)
+	store.EXPECT().
+		CreateLedgerEntries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entries ...*domain.LedgerEntry) error {
+			require.Len(t, entries, 1)
+			assert.Equal(t, paymentID, entries[0].PaymentID.String())
+			assert.Equal(t, domain.LedgerAccountAuthorized, entries[0].DebitAccount)
+			assert.Equal(t, domain.LedgerAccountCustomer, entries[0].CreditAccount)
+			assert.Equal(t, uint64(10000), entries[0].Amount)
+			assert.Equal(t, "GBP", entries[0].Currency)
+			return nil
+		})
 
 	store.EXPECT().
-		UpdatePayment(gomock.Any(), &paymentsV1.Payment{
+		UpdatePayment(gomock.Any(), protoEq(&paymentsV1.Payment{
 			Id:            paymentID,
 			Amount:        payment.Amount,
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 			PaymentMethod: payment.PaymentMethod,
-		}, domain.UpdatePaymentFieldStatus).Return(nil)
+		}), domain.UpdatePaymentFieldStatus).Return(nil)
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+			var authorized paymentsV1.PaymentAuthorized
+			require.NoError(t, proto.Unmarshal(event.Payload, &authorized))
+			assert.Equal(t, "shared.payment.v1.PaymentAuthorized", event.EventType)
+			assert.Equal(t, paymentID, event.PaymentID.String())
+			assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, authorized.Payment.PaymentStatus)
+			assert.Nil(t, authorized.Payment.PaymentMethod)
+			assert.Equal(t, "00", authorized.PaymentAction.ResponseCode)
+			return nil
+		})
 
-	service := gateway.NewService(store, issuerGateway)
-	_, err := service.CreatePayment(context.Background(), amount, method)
+	service := gateway.NewService(store, issuerGateway, vault)
+	p, err := service.CreatePayment(context.Background(), amount, "", method)
 	require.NoError(t, err)
+	assert.Equal(t, tokenizedCard, p.GetCard())
+	assert.Equal(t, "100.00", p.Amount.MajorUnits)
+}
+
+func TestService_CreatePayment_Token(t *testing.T) {
+	t.Parallel()
+
+	var (
+		amount = &amountV1.Money{
+			MinorUnits: 10000,
+			Currency:   "GBP",
+		}
+		majorAmount = &amountV1.Money{MinorUnits: 10000, Currency: "GBP", MajorUnits: "100.00"}
+		expiry      = &paymentsV1.PaymentMethodCard_ExpiryDate{Month: 12, Year: 2030}
+		card        = &paymentsV1.PaymentMethodCard{
+			CardNumber: "4000000000000119",
+			Token:      "tok_abc",
+			Bin:        "400000",
+			LastFour:   "0119",
+			Expiry:     expiry,
+		}
+	)
+
+	t.Run("should return error given the token is unknown", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		vault := mocks.NewMockVault(ctrl)
+		vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(nil, domain.ErrNoCardToken)
+
+		service := gateway.NewService(mocks.NewMockStore(ctrl), mocks.NewMockIssuerGateway(ctrl), vault)
+		_, err := service.CreatePayment(context.Background(), amount, "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}})
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNoCardToken, err)
+	})
+	t.Run("should authorize the card held against the token", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl          = gomock.NewController(t)
+			store         = mocks.NewMockStore(ctrl)
+			issuerGateway = mocks.NewMockIssuerGateway(ctrl)
+			vault         = mocks.NewMockVault(ctrl)
+		)
+		vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(card, nil)
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).Times(2)
+		store.EXPECT().CreatePayment(gomock.Any(), protoEq(&paymentsV1.Payment{
+			Amount:        majorAmount,
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{
+				Token:    "tok_abc",
+				Bin:      "400000",
+				LastFour: "0119",
+				Expiry:   expiry,
+				Brand:    "visa",
+			}},
+		})).Return(nil)
+		store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		issuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), domain.IssuerRequest{
+			Amount:        majorAmount,
+			OperationType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+			PaymentMethod: domain.PaymentMethod{Card: card}}).
+			Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+		store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		service := gateway.NewService(store, issuerGateway, vault)
+		p, err := service.CreatePayment(context.Background(), amount, "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}})
+		require.NoError(t, err)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, p.PaymentStatus)
+		assert.Empty(t, p.GetCard().GetCardNumber())
+	})
+}
+
+func TestService_CreatePayment_CardBrand(t *testing.T) {
+	t.Parallel()
+
+	var (
+		amount      = &amountV1.Money{MinorUnits: 10000, Currency: "GBP"}
+		merchantID  = uuid.NewV4()
+		merchantCtx = domain.ContextWithMerchant(context.Background(), merchantID.String())
+		card        = &paymentsV1.PaymentMethodCard{
+			CardNumber: "378282246310005",
+			Cvv:        "1234",
+			Expiry:     &paymentsV1.PaymentMethodCard_ExpiryDate{Month: 12, Year: 2030},
+		}
+	)
+
+	t.Run("should decline the card given the merchant does not accept its brand", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		store := mocks.NewMockStore(ctrl)
+		store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(&domain.Merchant{
+			ID:                 merchantID,
+			AcceptedCardBrands: []domain.CardBrand{domain.CardBrandVisa, domain.CardBrandMastercard},
+		}, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.CreatePayment(merchantCtx, amount, "", domain.PaymentMethod{Card: card})
+		assert.Equal(t, domain.ErrCardBrandNotAccepted, err)
+	})
+	t.Run("should return error given the merchant cannot be got", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		store := mocks.NewMockStore(ctrl)
+		store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(nil, errors.New("failed"))
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.CreatePayment(merchantCtx, amount, "", domain.PaymentMethod{Card: card})
+		assert.Error(t, err)
+	})
+	t.Run("should store the brand given the merchant accepts it", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+			vault = mocks.NewMockVault(ctrl)
+		)
+		store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(&domain.Merchant{
+			ID:                 merchantID,
+			AcceptedCardBrands: []domain.CardBrand{domain.CardBrandAmex},
+		}, nil)
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		vault.EXPECT().Tokenize(gomock.Any(), card).
+			Return(&paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "378282", LastFour: "0005", Brand: "amex"}, nil)
+		store.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment) error {
+				assert.Equal(t, "amex", payment.GetCard().GetBrand())
+				return nil
+			})
+		store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), vault, gateway.WithAsyncAuthorization())
+		p, err := service.CreatePayment(merchantCtx, amount, "", domain.PaymentMethod{Card: card})
+		require.NoError(t, err)
+		assert.Equal(t, "amex", p.GetCard().GetBrand())
+	})
 }
 
 func TestService_Capture_Error(t *testing.T) {
@@ -268,13 +552,13 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("error"))
 			},
 			err: errors.New("error"),
 		},
 		{
-			description: "given capture amount exceeds payment amount",
+			description: "given capture amount exceeds authorized amount",
 			amount:      1200,
 			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
@@ -283,12 +567,22 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
+						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return([]*paymentsV1.PaymentAction{
+						{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+						{Amount: 100, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, ResponseCode: "00"},
+						{Amount: 500, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, ResponseCode: "05"},
+					}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), gomock.Any()).Return(ledgerBalances(1100, 0, 0), nil)
 			},
 			err: domain.ErrNotPermitted,
 		},
@@ -302,7 +596,7 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
 						Amount: &amountV1.Money{
@@ -310,7 +604,7 @@ func TestService_Capture_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusCaptured},
 		},
 		{
 			description: "given payment is already refunded",
@@ -322,7 +616,7 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED,
 						Amount: &amountV1.Money{
@@ -330,7 +624,7 @@ func TestService_Capture_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusRefunded},
 		},
 		{
 			description: "given payment is partially refunded",
@@ -342,7 +636,7 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
 						Amount: &amountV1.Money{
@@ -350,7 +644,7 @@ func TestService_Capture_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusPartiallyRefunded},
 		},
 		{
 			description: "given payment is voided",
@@ -362,7 +656,7 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED,
 						Amount: &amountV1.Money{
@@ -370,7 +664,7 @@ func TestService_Capture_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusVoided},
 		},
 		{
 			description: "given payment is pending",
@@ -382,7 +676,7 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
 						Amount: &amountV1.Money{
@@ -390,7 +684,7 @@ func TestService_Capture_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusPending},
 		},
 		{
 			description: "given payment is declined",
@@ -402,15 +696,21 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return([]*paymentsV1.PaymentAction{
+						{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "51", DeclineReason: "insufficient funds"},
+					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeCapture, From: domain.PaymentStatusDeclined},
 		},
 		{
 			description: "given payment is partially captured and new capture exceeds exceeds total payment amount",
@@ -422,7 +722,7 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
 						Amount: &amountV1.Money{
@@ -438,6 +738,7 @@ func TestService_Capture_Error(t *testing.T) {
 						PaymentType:  paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
 						ResponseCode: "00",
 					}}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), gomock.Any()).Return(ledgerBalances(500, 500, 0), nil)
 			},
 			err: domain.ErrNotPermitted,
 		},
@@ -451,13 +752,18 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), gomock.Any()).Return(ledgerBalances(1000, 0, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -475,13 +781,18 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), gomock.Any()).Return(ledgerBalances(1000, 0, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -504,13 +815,18 @@ func TestService_Capture_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), gomock.Any()).Return(ledgerBalances(1000, 0, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -543,8 +859,8 @@ func TestService_Capture_Error(t *testing.T) {
 			if tc.fn != nil {
 				tc.fn(mockStore, mockIssuerGateway)
 			}
-			service := gateway.NewService(mockStore, mockIssuerGateway)
-			_, err := service.Capture(context.Background(), "id", tc.amount)
+			service := gateway.NewService(mockStore, mockIssuerGateway, mocks.NewMockVault(ctrl))
+			_, err := service.Capture(context.Background(), "id", tc.amount, false)
 			require.Error(t, err)
 			assert.Equal(t, tc.err.Error(), err.Error())
 		})
@@ -558,30 +874,44 @@ func TestService_Capture_Success(t *testing.T) {
 		var (
 			ctrl = gomock.NewController(t)
 
-			store       = mocks.NewMockStore(ctrl)
-			mockGateway = mocks.NewMockIssuerGateway(ctrl)
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
 		)
+
 		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 				return fn(ctx)
 			})
 		store.
 			EXPECT().
-			GetPayment(gomock.Any(), gomock.Any()).
+			GetPaymentForUpdate(gomock.Any(), "id").
 			Return(&paymentsV1.Payment{
+				Id:            "id",
 				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 				Amount: &amountV1.Money{
 					MinorUnits: 1000,
 				},
 			}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return([]*paymentsV1.PaymentAction{{Id: "auth-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00", Acquirer: "acquirer-b"}}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
 		store.
 			EXPECT().
 			CreatePaymentAction(gomock.Any(), gomock.Any()).
-			Return(nil)
-		mockGateway.
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+				action.Id = "action-id"
+				return nil
+			})
+		mockIssuerGateway.
 			EXPECT().
 			CreateIssuerRequest(gomock.Any(), gomock.Any()).
-			Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+			DoAndReturn(func(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+				// captures are pinned to the acquirer that authorized the payment
+				assert.Equal(t, "acquirer-b", issuerRequest.Acquirer)
+				return domain.IssuerResponse{AuthCode: "00", Acquirer: issuerRequest.Acquirer}, nil
+			})
 
 		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -590,12 +920,28 @@ func TestService_Capture_Success(t *testing.T) {
 		store.EXPECT().
 			UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.
+			EXPECT().
+			GetPaymentForUpdate(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
+
 		store.EXPECT().
 			UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.EXPECT().
+			CreateOutboxEvent(gomock.Any(), gomock.Any()).
+			Return(nil)
 
-		service := gateway.NewService(store, mockGateway)
-		payment, err := service.Capture(context.Background(), "id", 1000)
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		payment, err := service.Capture(context.Background(), "id", 1000, false)
 		require.NoError(t, err)
 		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED, payment.PaymentStatus, payment)
 	})
@@ -603,27 +949,37 @@ func TestService_Capture_Success(t *testing.T) {
 		var (
 			ctrl = gomock.NewController(t)
 
-			store       = mocks.NewMockStore(ctrl)
-			mockGateway = mocks.NewMockIssuerGateway(ctrl)
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
 		)
+
 		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
 				return fn(ctx)
 			})
 		store.
 			EXPECT().
-			GetPayment(gomock.Any(), gomock.Any()).
+			GetPaymentForUpdate(gomock.Any(), "id").
 			Return(&paymentsV1.Payment{
+				Id:            "id",
 				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 				Amount: &amountV1.Money{
 					MinorUnits: 1000,
 				},
 			}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return(nil, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
 		store.
 			EXPECT().
 			CreatePaymentAction(gomock.Any(), gomock.Any()).
-			Return(nil)
-		mockGateway.
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+				action.Id = "action-id"
+				return nil
+			})
+		mockIssuerGateway.
 			EXPECT().
 			CreateIssuerRequest(gomock.Any(), gomock.Any()).
 			Return(domain.IssuerResponse{AuthCode: "00"}, nil)
@@ -635,16 +991,102 @@ func TestService_Capture_Success(t *testing.T) {
 		store.EXPECT().
 			UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.
+			EXPECT().
+			GetPaymentForUpdate(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(500, 500, 0), nil)
+
 		store.EXPECT().
 			UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.EXPECT().
+			CreateOutboxEvent(gomock.Any(), gomock.Any()).
+			Return(nil)
 
-		service := gateway.NewService(store, mockGateway)
-		payment, err := service.Capture(context.Background(), "id", 500)
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		payment, err := service.Capture(context.Background(), "id", 500, false)
 		require.NoError(t, err)
 		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED, payment.PaymentStatus, payment)
 	})
+	t.Run("should capture the remaining amount of a partially captured payment", func(t *testing.T) {
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		)
+
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		store.
+			EXPECT().
+			GetPaymentForUpdate(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return([]*paymentsV1.PaymentAction{{Amount: 600, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(400, 600, 0), nil)
+		store.
+			EXPECT().
+			CreatePaymentAction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+				action.Id = "action-id"
+				return nil
+			})
+		mockIssuerGateway.
+			EXPECT().
+			CreateIssuerRequest(gomock.Any(), gomock.Any()).
+			Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		store.EXPECT().
+			UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+		store.
+			EXPECT().
+			GetPaymentForUpdate(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
+
+		store.EXPECT().
+			UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+		store.EXPECT().
+			CreateOutboxEvent(gomock.Any(), gomock.Any()).
+			Return(nil)
 
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		payment, err := service.Capture(context.Background(), "id", 400, false)
+		require.NoError(t, err)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED, payment.PaymentStatus, payment)
+	})
 }
 
 func TestService_Refund_Error(t *testing.T) {
@@ -665,13 +1107,13 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("error"))
 			},
 			err: errors.New("error"),
 		},
 		{
-			description: "given refund amount exceeds payment amount",
+			description: "given refund amount exceeds captured amount",
 			amount:      1200,
 			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
 				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
@@ -680,12 +1122,21 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
+						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return([]*paymentsV1.PaymentAction{
+						{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+						{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+					}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
 			},
 			err: domain.ErrNotPermitted,
 		},
@@ -699,7 +1150,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED,
 						Amount: &amountV1.Money{
@@ -707,7 +1158,7 @@ func TestService_Refund_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeRefund, From: domain.PaymentStatusRefunded},
 		},
 		{
 			description: "given payment is voided",
@@ -719,7 +1170,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED,
 						Amount: &amountV1.Money{
@@ -727,7 +1178,7 @@ func TestService_Refund_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeRefund, From: domain.PaymentStatusVoided},
 		},
 		{
 			description: "given payment is pending",
@@ -739,7 +1190,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
 						Amount: &amountV1.Money{
@@ -747,7 +1198,7 @@ func TestService_Refund_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeRefund, From: domain.PaymentStatusPending},
 		},
 		{
 			description: "given payment is declined",
@@ -759,15 +1210,21 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return([]*paymentsV1.PaymentAction{
+						{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "51", DeclineReason: "insufficient funds"},
+					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeRefund, From: domain.PaymentStatusDeclined},
 		},
 		{
 			description: "given payment is authorized",
@@ -779,7 +1236,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
@@ -787,7 +1244,7 @@ func TestService_Refund_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeRefund, From: domain.PaymentStatusAuthorized},
 		},
 		{
 			description: "given payment is partially captured and new capture exceeds exceeds total payment amount",
@@ -799,7 +1256,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
 						Amount: &amountV1.Money{
@@ -815,6 +1272,7 @@ func TestService_Refund_Error(t *testing.T) {
 						PaymentType:  paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
 						ResponseCode: "00",
 					}}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(500, 500, 0), nil)
 			},
 			err: domain.ErrNotPermitted,
 		},
@@ -829,7 +1287,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
 						Amount: &amountV1.Money{
@@ -851,6 +1309,7 @@ func TestService_Refund_Error(t *testing.T) {
 							ResponseCode: "00",
 						},
 					}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 500, 100), nil)
 			},
 			err: domain.ErrNotPermitted,
 		},
@@ -864,7 +1323,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
 						Amount: &amountV1.Money{
@@ -875,7 +1334,8 @@ func TestService_Refund_Error(t *testing.T) {
 				store.
 					EXPECT().
 					ListPaymentActions(gomock.Any(), gomock.Any()).
-					Return([]*paymentsV1.PaymentAction{{}}, nil)
+					Return([]*paymentsV1.PaymentAction{{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -893,7 +1353,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
 						Amount: &amountV1.Money{
@@ -903,7 +1363,8 @@ func TestService_Refund_Error(t *testing.T) {
 				store.
 					EXPECT().
 					ListPaymentActions(gomock.Any(), gomock.Any()).
-					Return([]*paymentsV1.PaymentAction{{}}, nil)
+					Return([]*paymentsV1.PaymentAction{{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -926,7 +1387,7 @@ func TestService_Refund_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
 						Amount: &amountV1.Money{
@@ -936,7 +1397,8 @@ func TestService_Refund_Error(t *testing.T) {
 				store.
 					EXPECT().
 					ListPaymentActions(gomock.Any(), gomock.Any()).
-					Return([]*paymentsV1.PaymentAction{{}}, nil)
+					Return([]*paymentsV1.PaymentAction{{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}}, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -969,7 +1431,7 @@ func TestService_Refund_Error(t *testing.T) {
 			if tc.fn != nil {
 				tc.fn(mockStore, mockIssuerGateway)
 			}
-			service := gateway.NewService(mockStore, mockIssuerGateway)
+			service := gateway.NewService(mockStore, mockIssuerGateway, mocks.NewMockVault(ctrl))
 			_, err := service.Refund(context.Background(), "id", tc.amount)
 			require.Error(t, err)
 			assert.Equal(t, tc.err.Error(), err.Error())
@@ -994,7 +1456,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("error"))
 			},
 			err: errors.New("error"),
@@ -1008,7 +1470,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED,
 						Amount: &amountV1.Money{
@@ -1016,7 +1478,7 @@ func TestService_Void_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusRefunded},
 		},
 		{
 			description: "given payment is partially refunded",
@@ -1027,7 +1489,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
 						Amount: &amountV1.Money{
@@ -1035,7 +1497,7 @@ func TestService_Void_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusPartiallyRefunded},
 		},
 		{
 			description: "given payment is already voided",
@@ -1046,7 +1508,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED,
 						Amount: &amountV1.Money{
@@ -1054,7 +1516,7 @@ func TestService_Void_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusVoided},
 		},
 		{
 			description: "given payment is pending",
@@ -1065,7 +1527,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
 						Amount: &amountV1.Money{
@@ -1073,7 +1535,7 @@ func TestService_Void_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusPending},
 		},
 		{
 			description: "given payment is declined",
@@ -1084,15 +1546,21 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return([]*paymentsV1.PaymentAction{
+						{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "51", DeclineReason: "insufficient funds"},
+					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusDeclined},
 		},
 		{
 			description: "given payment is captured",
@@ -1103,7 +1571,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
 						Amount: &amountV1.Money{
@@ -1111,7 +1579,7 @@ func TestService_Void_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusCaptured},
 		},
 		{
 			description: "given payment is partially captured",
@@ -1122,7 +1590,7 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
 						Amount: &amountV1.Money{
@@ -1130,7 +1598,7 @@ func TestService_Void_Error(t *testing.T) {
 						},
 					}, nil)
 			},
-			err: domain.ErrNotPermitted,
+			err: domain.TransitionError{PaymentType: domain.PaymentTypeVoid, From: domain.PaymentStatusCaptured},
 		},
 		{
 			description: "given that payment action creation fails",
@@ -1141,13 +1609,18 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
 
 				store.
 					EXPECT().
@@ -1165,13 +1638,18 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -1193,13 +1671,18 @@ func TestService_Void_Error(t *testing.T) {
 					})
 				store.
 					EXPECT().
-					GetPayment(gomock.Any(), gomock.Any()).
+					GetPaymentForUpdate(gomock.Any(), gomock.Any()).
 					Return(&paymentsV1.Payment{
 						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 						Amount: &amountV1.Money{
 							MinorUnits: 1000,
 						},
 					}, nil)
+				store.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
 				store.
 					EXPECT().
 					CreatePaymentAction(gomock.Any(), gomock.Any()).
@@ -1232,7 +1715,7 @@ func TestService_Void_Error(t *testing.T) {
 			if tc.fn != nil {
 				tc.fn(mockStore, mockIssuerGateway)
 			}
-			service := gateway.NewService(mockStore, mockIssuerGateway)
+			service := gateway.NewService(mockStore, mockIssuerGateway, mocks.NewMockVault(ctrl))
 			_, err := service.Void(context.Background(), "id")
 			require.Error(t, err)
 			assert.Equal(t, tc.err.Error(), err.Error())
@@ -1256,8 +1739,9 @@ func TestService_Refund_Success(t *testing.T) {
 			})
 		store.
 			EXPECT().
-			GetPayment(gomock.Any(), gomock.Any()).
+			GetPaymentForUpdate(gomock.Any(), "id").
 			Return(&paymentsV1.Payment{
+				Id:            "id",
 				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
 				Amount: &amountV1.Money{
 					MinorUnits: 1000,
@@ -1265,12 +1749,16 @@ func TestService_Refund_Success(t *testing.T) {
 			}, nil)
 		store.
 			EXPECT().
-			ListPaymentActions(gomock.Any(), gomock.Any()).
-			Return([]*paymentsV1.PaymentAction{{}}, nil)
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return([]*paymentsV1.PaymentAction{{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}, {Amount: 500, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND, ResponseCode: "00"}}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 500), nil)
 		store.
 			EXPECT().
 			CreatePaymentAction(gomock.Any(), gomock.Any()).
-			Return(nil)
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+				action.Id = "action-id"
+				return nil
+			})
 		mockIssuerGateway.
 			EXPECT().
 			CreateIssuerRequest(gomock.Any(), gomock.Any()).
@@ -1283,15 +1771,30 @@ func TestService_Refund_Success(t *testing.T) {
 		store.EXPECT().
 			UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.
+			EXPECT().
+			GetPaymentForUpdate(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 1000), nil)
 
 		store.EXPECT().
 			UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.EXPECT().
+			CreateOutboxEvent(gomock.Any(), gomock.Any()).
+			Return(nil)
 
-		service := gateway.NewService(store, mockIssuerGateway)
-		payment, err := service.Refund(context.Background(), "id", 1000)
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		payment, err := service.Refund(context.Background(), "id", 500)
 		require.NoError(t, err)
-		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED, payment.PaymentStatus)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED, payment.PaymentStatus, payment)
 	})
 	t.Run("should be able to partially refund", func(t *testing.T) {
 		var (
@@ -1307,21 +1810,26 @@ func TestService_Refund_Success(t *testing.T) {
 			})
 		store.
 			EXPECT().
-			GetPayment(gomock.Any(), gomock.Any()).
+			GetPaymentForUpdate(gomock.Any(), "id").
 			Return(&paymentsV1.Payment{
-				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
 				Amount: &amountV1.Money{
 					MinorUnits: 1000,
 				},
 			}, nil)
 		store.
 			EXPECT().
-			ListPaymentActions(gomock.Any(), gomock.Any()).
-			Return([]*paymentsV1.PaymentAction{{}}, nil)
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return([]*paymentsV1.PaymentAction{{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
 		store.
 			EXPECT().
 			CreatePaymentAction(gomock.Any(), gomock.Any()).
-			Return(nil)
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+				action.Id = "action-id"
+				return nil
+			})
 		mockIssuerGateway.
 			EXPECT().
 			CreateIssuerRequest(gomock.Any(), gomock.Any()).
@@ -1334,17 +1842,31 @@ func TestService_Refund_Success(t *testing.T) {
 		store.EXPECT().
 			UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.
+			EXPECT().
+			GetPaymentForUpdate(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 500), nil)
 
 		store.EXPECT().
 			UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
+		store.EXPECT().
+			CreateOutboxEvent(gomock.Any(), gomock.Any()).
+			Return(nil)
 
-		service := gateway.NewService(store, mockIssuerGateway)
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
 		payment, err := service.Refund(context.Background(), "id", 500)
 		require.NoError(t, err)
-		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED, payment.PaymentStatus)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED, payment.PaymentStatus, payment)
 	})
-
 }
 
 func TestService_Void_Success(t *testing.T) {
@@ -1363,18 +1885,26 @@ func TestService_Void_Success(t *testing.T) {
 		})
 	store.
 		EXPECT().
-		GetPayment(gomock.Any(), gomock.Any()).
+		GetPaymentForUpdate(gomock.Any(), "id").
 		Return(&paymentsV1.Payment{
+			Id:            "id",
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
 			Amount: &amountV1.Money{
 				MinorUnits: 1000,
 			},
 		}, nil)
-
+	store.
+		EXPECT().
+		ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+		Return([]*paymentsV1.PaymentAction{{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"}}, nil)
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
 	store.
 		EXPECT().
 		CreatePaymentAction(gomock.Any(), gomock.Any()).
-		Return(nil)
+		DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+			action.Id = "action-id"
+			return nil
+		})
 	mockIssuerGateway.
 		EXPECT().
 		CreateIssuerRequest(gomock.Any(), gomock.Any()).
@@ -1387,13 +1917,1813 @@ func TestService_Void_Success(t *testing.T) {
 	store.EXPECT().
 		UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil)
+	store.
+		EXPECT().
+		GetPaymentForUpdate(gomock.Any(), "id").
+		Return(&paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			Amount: &amountV1.Money{
+				MinorUnits: 1000,
+			},
+		}, nil)
+	store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 0, 0), nil)
 
 	store.EXPECT().
 		UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil)
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Return(nil)
 
-	service := gateway.NewService(store, mockIssuerGateway)
+	service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
 	payment, err := service.Void(context.Background(), "id")
 	require.NoError(t, err)
-	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED, payment.PaymentStatus)
+	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED, payment.PaymentStatus, payment)
+}
+
+func TestService_GetPayment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return error given the payment does not exist", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(nil, domain.ErrNoPayment)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		_, err := service.GetPayment(context.Background(), "id")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNoPayment, err)
+	})
+
+	t.Run("should derive balances from the ledger", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+
+			payment = &paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}
+			actions = []*paymentsV1.PaymentAction{
+				{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+				{Amount: 600, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+				{Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "12"},
+				{Amount: 200, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND, ResponseCode: "00"},
+				{Amount: 100, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND},
+			}
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(payment, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return(actions, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 600, 200), nil)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		details, err := service.GetPayment(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, payment, details.Payment)
+		assert.Equal(t, actions, details.PaymentActions)
+		assert.Equal(t, uint64(600), details.Balance.Captured)
+		assert.Equal(t, uint64(200), details.Balance.Refunded)
+		assert.Equal(t, uint64(0), details.Balance.Remaining)
+	})
+
+	t.Run("should return the remaining amount given the payment is partially captured", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{
+				{Amount: 300, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+			}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(700, 300, 0), nil)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		details, err := service.GetPayment(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(300), details.Balance.Captured)
+		assert.Equal(t, uint64(700), details.Balance.Remaining)
+	})
+
+	t.Run("should exclude the reversed amount from the remaining amount", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{
+				{Amount: 300, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+				{Amount: 200, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL, ResponseCode: "00"},
+				{Amount: 100, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL, ResponseCode: "51"},
+			}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(500, 300, 0), nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		details, err := service.GetPayment(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(800), details.Balance.Authorized)
+		assert.Equal(t, uint64(200), details.Balance.Reversed)
+		assert.Equal(t, uint64(500), details.Balance.Remaining)
+	})
+	t.Run("should include incremental authorizations in the authorized amount", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+				},
+			}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{
+				{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+				{Amount: 500, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, ResponseCode: "00"},
+				{Amount: 200, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, ResponseCode: "51"},
+				{Amount: 300, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+			}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1200, 300, 0), nil)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		details, err := service.GetPayment(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1500), details.Balance.Authorized)
+		assert.Equal(t, uint64(1200), details.Balance.Remaining)
+	})
+}
+
+func TestService_ListPaymentActions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return error given the payment does not exist", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(nil, domain.ErrNoPayment)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		_, err := service.ListPaymentActions(context.Background(), "id")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNoPayment, err)
+	})
+
+	t.Run("should return the payments actions", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+
+			actions = []*paymentsV1.PaymentAction{
+				{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+			}
+		)
+		store.
+			EXPECT().
+			GetPayment(gomock.Any(), "id").
+			Return(&paymentsV1.Payment{Id: "id"}, nil)
+		store.
+			EXPECT().
+			ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return(actions, nil)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		a, err := service.ListPaymentActions(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, actions, a)
+	})
+}
+
+func TestService_ListPayments(t *testing.T) {
+	t.Parallel()
+
+	newPayments := func(n int) []*paymentsV1.Payment {
+		payments := make([]*paymentsV1.Payment, n)
+		for i := range payments {
+			payments[i] = &paymentsV1.Payment{Id: uuid.NewV4().String(), CreatedAt: timestamppb.Now()}
+		}
+		return payments
+	}
+
+	t.Run("should return error given unable to list payments", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		store.EXPECT().ListPayments(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.ListPayments(context.Background(), domain.ListPaymentFilters{})
+		require.Error(t, err)
+	})
+
+	t.Run("should return a next cursor given there are more payments", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl     = gomock.NewController(t)
+			store    = mocks.NewMockStore(ctrl)
+			payments = newPayments(3)
+		)
+		store.EXPECT().ListPayments(gomock.Any(), &domain.ListPaymentFilters{
+			Currencies: []string{"GBP"},
+			Limit:      3,
+		}).Return(payments, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		list, err := service.ListPayments(context.Background(), domain.ListPaymentFilters{Currencies: []string{"GBP"}, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, payments[:2], list.Payments)
+
+		cursor, err := domain.DecodePaymentCursor(list.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, payments[1].Id, cursor.ID)
+	})
+
+	t.Run("should not return a next cursor given the last page", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl     = gomock.NewController(t)
+			store    = mocks.NewMockStore(ctrl)
+			payments = newPayments(1)
+		)
+		store.EXPECT().ListPayments(gomock.Any(), &domain.ListPaymentFilters{
+			Limit: domain.DefaultListPaymentsLimit + 1,
+		}).Return(payments, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		list, err := service.ListPayments(context.Background(), domain.ListPaymentFilters{})
+		require.NoError(t, err)
+		assert.Equal(t, payments, list.Payments)
+		assert.Empty(t, list.NextCursor)
+	})
+}
+
+func TestService_CreatePayment_Async(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl = gomock.NewController(t)
+
+		store         = mocks.NewMockStore(ctrl)
+		issuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		vault         = mocks.NewMockVault(ctrl)
+	)
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).
+		Return(&paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "400000", LastFour: "0119"}, nil)
+	store.
+		EXPECT().
+		CreatePayment(gomock.Any(), gomock.Any()).
+		Return(nil)
+	store.
+		EXPECT().
+		CreatePaymentAction(gomock.Any(), gomock.Any()).
+		Return(nil)
+	store.EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	service := gateway.NewService(store, issuerGateway, vault, gateway.WithAsyncAuthorization())
+	payment, err := service.CreatePayment(context.Background(), &amountV1.Money{
+		MinorUnits: 10000,
+		Currency:   "GBP",
+	}, "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}})
+	require.NoError(t, err)
+	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING, payment.PaymentStatus)
+}
+
+func TestService_CreatePayment_Settlement(t *testing.T) {
+	t.Parallel()
+
+	var (
+		amount = &amountV1.Money{MinorUnits: 10000, Currency: "EUR"}
+		method = domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}}
+		rate   = domain.FXRate{Base: "EUR", Quote: "GBP", Rate: "0.8563"}
+	)
+
+	t.Run("should convert the amount locking the rate against the payment", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+			vault = mocks.NewMockVault(ctrl)
+			rates = mocks.NewMockRateProvider(ctrl)
+		)
+		rates.EXPECT().Rate(gomock.Any(), "EUR", "GBP").Return(rate, nil)
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(&paymentsV1.PaymentMethodCard{Token: "tok_abc"}, nil)
+		store.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment) error {
+				assert.Equal(t, uint64(8563), payment.SettlementAmount.GetMinorUnits())
+				assert.Equal(t, "GBP", payment.SettlementAmount.GetCurrency())
+				assert.Equal(t, "0.8563", payment.FxRate)
+				return nil
+			})
+		store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+				assert.Equal(t, uint64(8563), action.SettlementAmount)
+				return nil
+			})
+		store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), vault,
+			gateway.WithAsyncAuthorization(), gateway.WithRateProvider(rates))
+		payment, err := service.CreatePayment(context.Background(), amount, "GBP", method)
+		require.NoError(t, err)
+		assert.Equal(t, "85.63", payment.SettlementAmount.GetMajorUnits())
+	})
+	t.Run("should not convert given the settlement currency is the amount's", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+			vault = mocks.NewMockVault(ctrl)
+		)
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(&paymentsV1.PaymentMethodCard{Token: "tok_abc"}, nil)
+		store.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), vault, gateway.WithAsyncAuthorization())
+		payment, err := service.CreatePayment(context.Background(), amount, "EUR", method)
+		require.NoError(t, err)
+		assert.Nil(t, payment.SettlementAmount)
+		assert.Empty(t, payment.FxRate)
+	})
+	t.Run("should return error given there is no rate provider", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		service := gateway.NewService(mocks.NewMockStore(ctrl), mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.CreatePayment(context.Background(), amount, "GBP", method)
+		assert.Equal(t, domain.ErrNoFXRate, err)
+	})
+	t.Run("should return error given there is no rate", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		rates := mocks.NewMockRateProvider(ctrl)
+		rates.EXPECT().Rate(gomock.Any(), "EUR", "JPY").Return(domain.FXRate{}, domain.ErrNoFXRate)
+		service := gateway.NewService(mocks.NewMockStore(ctrl), mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl),
+			gateway.WithRateProvider(rates))
+		_, err := service.CreatePayment(context.Background(), amount, "JPY", method)
+		assert.Equal(t, domain.ErrNoFXRate, err)
+	})
+}
+
+func TestService_Capture_Settlement(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl  = gomock.NewController(t)
+		store = mocks.NewMockStore(ctrl)
+		stop  = errors.New("stop")
+	)
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(&paymentsV1.Payment{
+		Id:               "id",
+		PaymentStatus:    paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+		Amount:           &amountV1.Money{MinorUnits: 10000, Currency: "EUR"},
+		SettlementAmount: &amountV1.Money{MinorUnits: 8563, Currency: "GBP"},
+		FxRate:           "0.8563",
+	}, nil)
+	store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+		Return([]*paymentsV1.PaymentAction{{Id: "auth-id", Amount: 10000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"}}, nil)
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(10000, 0, 0), nil)
+	store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+			// the partial capture is converted by the rate locked on the payment
+			assert.Equal(t, uint64(2999), action.Amount)
+			assert.Equal(t, uint64(2568), action.SettlementAmount)
+			return stop
+		})
+
+	service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+	_, err := service.Capture(context.Background(), "id", 2999, false)
+	assert.Equal(t, stop, err)
+}
+
+func TestService_ListPendingAuthorizations(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl = gomock.NewController(t)
+
+		store   = mocks.NewMockStore(ctrl)
+		actions = []*paymentsV1.PaymentAction{{Id: "id"}}
+	)
+	store.
+		EXPECT().
+		ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{
+			PaymentTypes: []domain.PaymentType{domain.PaymentTypeAuthorization},
+			Unprocessed:  true,
+			Unclaimed:    true,
+			Limit:        10,
+		}).
+		Return(actions, nil)
+
+	service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+	pending, err := service.ListPendingAuthorizations(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, actions, pending)
+}
+
+// inTransactionKey marks the context passed to a transaction's function by the mocked store.
+type inTransactionKey struct{}
+
+func TestService_ProcessAuthorization(t *testing.T) {
+	t.Parallel()
+
+	pendingAction := func() *paymentsV1.PaymentAction {
+		return &paymentsV1.PaymentAction{
+			Id:          "action-id",
+			Amount:      1000,
+			PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+			PaymentId:   "id",
+		}
+	}
+
+	for _, tc := range []struct {
+		description string
+		fn          func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault)
+		err         error
+	}{
+		{
+			description: "should skip an action locked by another worker",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(nil, domain.ErrNoPaymentAction)
+			},
+			err: domain.ErrPaymentActionClaimed,
+		},
+		{
+			description: "should skip an action that has already been processed",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				action := pendingAction()
+				action.ProcessedAt = timestamppb.Now()
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(action, nil)
+			},
+			err: domain.ErrPaymentActionClaimed,
+		},
+		{
+			description: "should skip an action claimed by another worker",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(pendingAction(), nil)
+				store.EXPECT().ClaimPaymentAction(gomock.Any(), "action-id").Return(domain.ErrPaymentActionClaimed)
+			},
+			err: domain.ErrPaymentActionClaimed,
+		},
+		{
+			description: "should return error given unable to claim the action",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(pendingAction(), nil)
+				store.EXPECT().ClaimPaymentAction(gomock.Any(), "action-id").Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return error given unable to get action",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(nil, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return error given unable to get the card from the vault",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(pendingAction(), nil)
+				store.EXPECT().ClaimPaymentAction(gomock.Any(), "action-id").Return(nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(&paymentsV1.Payment{
+					Id:            "id",
+					PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+					Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+					PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}},
+				}, nil)
+				vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(nil, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return error given unable to call the issuer",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(pendingAction(), nil)
+				store.EXPECT().ClaimPaymentAction(gomock.Any(), "action-id").Return(nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(&paymentsV1.Payment{
+					Id:            "id",
+					PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+					Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+				}, nil)
+				gateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should authorize the pending payment",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway, vault *mocks.MockVault) {
+				card := &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119", Token: "tok_abc", Bin: "400000", LastFour: "0119"}
+				amount := &amountV1.Money{MinorUnits: 1000, Currency: "GBP"}
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(pendingAction(), nil)
+				store.EXPECT().ClaimPaymentAction(gomock.Any(), "action-id").Return(nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(&paymentsV1.Payment{
+					Id:            "id",
+					PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+					Amount:        amount,
+					PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "400000", LastFour: "0119"}},
+				}, nil)
+				vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(card, nil)
+				gateway.EXPECT().CreateIssuerRequest(gomock.Any(), domain.IssuerRequest{
+					Reference:     "action-id",
+					Amount:        amount,
+					OperationType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+					PaymentMethod: domain.PaymentMethod{Card: card},
+				}).DoAndReturn(func(ctx context.Context, _ domain.IssuerRequest) (domain.IssuerResponse, error) {
+					// the claim is committed before the issuer is called
+					assert.Nil(t, ctx.Value(inTransactionKey{}))
+					return domain.IssuerResponse{AuthCode: "00"}, nil
+				})
+				store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(context.WithValue(ctx, inTransactionKey{}, true))
+					})
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					Return(nil)
+				store.EXPECT().
+					CreateLedgerEntries(gomock.Any(), gomock.Any()).
+					Return(nil)
+				store.EXPECT().
+					UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+					DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+						assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, payment.PaymentStatus)
+						return nil
+					})
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl = gomock.NewController(t)
+
+				mockStore         = mocks.NewMockStore(ctrl)
+				mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+				mockVault         = mocks.NewMockVault(ctrl)
+			)
+			mockStore.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(context.WithValue(ctx, inTransactionKey{}, true))
+				})
+			tc.fn(mockStore, mockIssuerGateway, mockVault)
+
+			service := gateway.NewService(mockStore, mockIssuerGateway, mockVault, gateway.WithAsyncAuthorization())
+			err := service.ProcessAuthorization(context.Background(), "action-id")
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_RecoverPaymentAction(t *testing.T) {
+	t.Parallel()
+
+	unresolvedAction := func(paymentType paymentsV1.PaymentType, amount uint64) *paymentsV1.PaymentAction {
+		return &paymentsV1.PaymentAction{
+			Id:          "action-id",
+			Amount:      amount,
+			PaymentType: paymentType,
+			PaymentId:   "id",
+		}
+	}
+	paymentWithStatus := func(status paymentsV1.PaymentStatus) *paymentsV1.Payment {
+		return &paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: status,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+		}
+	}
+
+	for _, tc := range []struct {
+		description string
+		async       bool
+		fn          func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway)
+		err         error
+	}{
+		{
+			description: "should skip an action locked by another worker",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(nil, domain.ErrNoPaymentAction)
+			},
+		},
+		{
+			description: "should skip an action that has already been processed",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				action := unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, 1000)
+				action.ProcessedAt = timestamppb.Now()
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").Return(action, nil)
+			},
+		},
+		{
+			description: "should return error given unable to get status from the issuer",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, 1000), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED), nil)
+				store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should leave an authorization the issuer has not received to the authorization workers",
+			async:       true,
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, 1000), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, domain.ErrIssuerRequestNotFound)
+				store.EXPECT().ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{
+					PaymentIDs:   []string{"id"},
+					PaymentTypes: []domain.PaymentType{domain.PaymentTypeAuthorization},
+					Unclaimed:    true,
+				}).Return([]*paymentsV1.PaymentAction{unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, 1000)}, nil)
+			},
+		},
+		{
+			description: "should decline an authorization the issuer has not received",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, 1000), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, domain.ErrIssuerRequestNotFound)
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
+						assert.Equal(t, domain.IssuerResponseCodeNoRecord, action.ResponseCode)
+						return nil
+					})
+				store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING), nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(domain.LedgerBalances{}, nil)
+				store.EXPECT().
+					UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+					DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+						assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED, payment.PaymentStatus)
+						return nil
+					})
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, "shared.payment.v1.PaymentDeclined", event.EventType)
+						return nil
+					})
+			},
+		},
+		{
+			description: "should decline an authorization claimed by a worker that the issuer has not received",
+			async:       true,
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, 1000), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, domain.ErrIssuerRequestNotFound)
+				store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return([]*paymentsV1.PaymentAction{}, nil)
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
+						assert.Equal(t, domain.IssuerResponseCodeNoRecord, action.ResponseCode)
+						return nil
+					})
+				store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING), nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(domain.LedgerBalances{}, nil)
+				store.EXPECT().
+					UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+					DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+						assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED, payment.PaymentStatus)
+						return nil
+					})
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, "shared.payment.v1.PaymentDeclined", event.EventType)
+						return nil
+					})
+			},
+		},
+		{
+			description: "should only record the outcome of a failed capture",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, 1000), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{AuthCode: "12"}, nil)
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					Return(nil)
+				store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED), nil)
+				store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+			},
+		},
+		{
+			description: "should partially capture the payment given a successful capture",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, 400), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), domain.IssuerRequest{
+					Reference:     "action-id",
+					Amount:        &amountV1.Money{MinorUnits: 400, Currency: "GBP"},
+					OperationType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
+					PaymentMethod: domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+					Acquirer:      "acquirer-b",
+				}).Return(domain.IssuerResponse{AuthCode: "00", Acquirer: "acquirer-b"}, nil)
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
+						assert.Equal(t, "acquirer-b", action.Acquirer)
+						return nil
+					})
+				store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED), nil)
+				store.EXPECT().ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+					Return([]*paymentsV1.PaymentAction{
+						{Id: "auth-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00", Acquirer: "acquirer-b"},
+						{Id: "action-id", Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+					}, nil)
+				store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(600, 400, 0), nil)
+				store.EXPECT().
+					UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+					DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+						assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED, payment.PaymentStatus)
+						return nil
+					})
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, "shared.payment.v1.PaymentCaptured", event.EventType)
+						return nil
+					})
+			},
+		},
+		{
+			description: "should refund the payment given a successful refund of the remaining captured amount",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_REFUND, 600), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					Return(nil)
+				store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED), nil)
+				store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+					Return([]*paymentsV1.PaymentAction{
+						{Id: "capture-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+						{Id: "refund-id", Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND, ResponseCode: "00"},
+						{Id: "action-id", Amount: 600, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND},
+					}, nil)
+				store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 1000), nil)
+				store.EXPECT().
+					UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+					DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+						assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED, payment.PaymentStatus)
+						return nil
+					})
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+		},
+		{
+			description: "should not change the status of a payment that has moved on",
+			fn: func(store *mocks.MockStore, gateway *mocks.MockIssuerGateway) {
+				store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+					Return(unresolvedAction(paymentsV1.PaymentType_PAYMENT_TYPE_VOID, 1000), nil)
+				store.EXPECT().GetPayment(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED), nil)
+				gateway.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+				store.EXPECT().
+					UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+					Return(nil)
+				store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(paymentWithStatus(paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED), nil)
+				store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+				store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1000, 0), nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl = gomock.NewController(t)
+
+				mockStore         = mocks.NewMockStore(ctrl)
+				mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+			)
+			mockStore.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+			tc.fn(mockStore, mockIssuerGateway)
+
+			var opts []gateway.Option
+			if tc.async {
+				opts = append(opts, gateway.WithAsyncAuthorization())
+			}
+			service := gateway.NewService(mockStore, mockIssuerGateway, mocks.NewMockVault(ctrl), opts...)
+			err := service.RecoverPaymentAction(context.Background(), "action-id")
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_InFlightActions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		status      paymentsV1.PaymentStatus
+		actions     []*paymentsV1.PaymentAction
+		balances    domain.LedgerBalances
+		fn          func(service gateway.Service) (*paymentsV1.Payment, error)
+	}{
+		{
+			description: "should not capture more than the amount left after captures awaiting an outcome",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			actions: []*paymentsV1.PaymentAction{
+				{Amount: 600, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE},
+			},
+			balances: ledgerBalances(1000, 0, 0),
+			fn: func(service gateway.Service) (*paymentsV1.Payment, error) {
+				return service.Capture(context.Background(), "id", 500, false)
+			},
+		},
+		{
+			description: "should not capture whilst a void is awaiting an outcome",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			actions: []*paymentsV1.PaymentAction{
+				{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_VOID},
+			},
+			fn: func(service gateway.Service) (*paymentsV1.Payment, error) {
+				return service.Capture(context.Background(), "id", 100, false)
+			},
+		},
+		{
+			description: "should not refund more than the amount left after refunds awaiting an outcome",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
+			actions: []*paymentsV1.PaymentAction{
+				{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00", ProcessedAt: timestamppb.Now()},
+				{Amount: 700, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND},
+			},
+			balances: ledgerBalances(0, 1000, 0),
+			fn: func(service gateway.Service) (*paymentsV1.Payment, error) {
+				return service.Refund(context.Background(), "id", 400)
+			},
+		},
+		{
+			description: "should not void whilst a capture is awaiting an outcome",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			actions: []*paymentsV1.PaymentAction{
+				{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00", ProcessedAt: timestamppb.Now()},
+				{Amount: 500, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE},
+			},
+			fn: func(service gateway.Service) (*paymentsV1.Payment, error) {
+				return service.Void(context.Background(), "id")
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl = gomock.NewController(t)
+
+				store = mocks.NewMockStore(ctrl)
+			)
+			store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+			store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(&paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: tc.status,
+				Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			}, nil)
+			store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(tc.actions, nil)
+			store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(tc.balances, nil).AnyTimes()
+
+			_, err := tc.fn(gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl)))
+			assert.Equal(t, domain.ErrNotPermitted, err)
+		})
+	}
+}
+
+func TestService_Capture_Declined(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		code        string
+		expCategory paymentsV1.ResponseCategory
+		expReason   string
+	}{
+		{code: "51", expCategory: paymentsV1.ResponseCategory_RESPONSE_CATEGORY_SOFT_DECLINE, expReason: "insufficient funds"},
+		{code: "54", expCategory: paymentsV1.ResponseCategory_RESPONSE_CATEGORY_HARD_DECLINE, expReason: "expired card"},
+		{code: "01", expCategory: paymentsV1.ResponseCategory_RESPONSE_CATEGORY_REFERRAL, expReason: "refer to card issuer"},
+		{code: "43", expCategory: paymentsV1.ResponseCategory_RESPONSE_CATEGORY_FRAUD, expReason: "stolen card"},
+		{code: "X9", expCategory: paymentsV1.ResponseCategory_RESPONSE_CATEGORY_HARD_DECLINE, expReason: "unknown response code"},
+	} {
+		tc := tc
+		t.Run("should surface the decline reason given response code "+tc.code, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl = gomock.NewController(t)
+
+				store             = mocks.NewMockStore(ctrl)
+				mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+				authorized        = func() *paymentsV1.Payment {
+					return &paymentsV1.Payment{
+						Id:            "id",
+						PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+						Amount:        &amountV1.Money{MinorUnits: 1000},
+					}
+				}
+			)
+			store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).Times(2)
+			store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(), nil)
+			store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+			store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+			store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+			mockIssuerGateway.EXPECT().
+				CreateIssuerRequest(gomock.Any(), gomock.Any()).
+				Return(domain.IssuerResponse{AuthCode: tc.code}, nil)
+			store.EXPECT().
+				UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+				DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
+					assert.Equal(t, tc.code, action.ResponseCode)
+					assert.Equal(t, tc.expCategory, action.ResponseCategory)
+					assert.Equal(t, tc.expReason, action.DeclineReason)
+					return nil
+				})
+			store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(), nil)
+			store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+
+			service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+			payment, err := service.Capture(context.Background(), "id", 500, false)
+			require.NoError(t, err)
+			assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, payment.PaymentStatus)
+			assert.Equal(t, tc.expReason, payment.DeclineReason)
+		})
+	}
+}
+
+func TestService_Capture_CircuitOpen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should record the capture as failed given the circuit breaker rejected it", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+			authorized        = func() *paymentsV1.Payment {
+				return &paymentsV1.Payment{
+					Id:            "id",
+					PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+					Amount:        &amountV1.Money{MinorUnits: 1000},
+				}
+			}
+		)
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).Times(2)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(), nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+		store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+		mockIssuerGateway.EXPECT().
+			CreateIssuerRequest(gomock.Any(), gomock.Any()).
+			Return(domain.IssuerResponse{}, errors.Wrap(domain.ErrCircuitOpen, "acquirer"))
+		store.EXPECT().
+			UpdatePaymentAction(gomock.Any(), gomock.Any(), domain.UpdatePaymentActionFieldResponseCode).
+			DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
+				assert.Equal(t, domain.IssuerResponseCodeNoRecord, action.ResponseCode)
+				return nil
+			})
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(), nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		_, err := service.Capture(context.Background(), "id", 500, false)
+		assert.ErrorIs(t, err, domain.ErrIssuerUnavailable)
+	})
+
+	t.Run("should leave the capture for recovery given it may have reached the issuer", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl = gomock.NewController(t)
+
+			store             = mocks.NewMockStore(ctrl)
+			mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		)
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(&paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			Amount:        &amountV1.Money{MinorUnits: 1000},
+		}, nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+		store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+		mockIssuerGateway.EXPECT().
+			CreateIssuerRequest(gomock.Any(), gomock.Any()).
+			Return(domain.IssuerResponse{}, errors.Wrap(domain.ErrIssuerUnavailable, "acquirer timed out"))
+
+		service := gateway.NewService(store, mockIssuerGateway, mocks.NewMockVault(ctrl))
+		_, err := service.Capture(context.Background(), "id", 500, false)
+		assert.ErrorIs(t, err, domain.ErrIssuerUnavailable)
+	})
+}
+
+func TestService_CreatePayment_Expiry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl              = gomock.NewController(t)
+		store             = mocks.NewMockStore(ctrl)
+		vault             = mocks.NewMockVault(ctrl)
+		mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		expiry            = domain.AuthorizationExpiry{
+			Default: 7 * 24 * time.Hour,
+			Brands:  map[domain.CardBrand]time.Duration{domain.CardBrandMastercard: 30 * 24 * time.Hour},
+		}
+	)
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).Times(2)
+	vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).
+		Return(&paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "555555", LastFour: "4444"}, nil)
+	store.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockIssuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+	store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus, domain.UpdatePaymentFieldExpiresAt).
+		Return(nil)
+
+	service := gateway.NewService(store, mockIssuerGateway, vault, gateway.WithAuthorizationExpiry(expiry))
+	payment, err := service.CreatePayment(context.Background(), &amountV1.Money{MinorUnits: 1000, Currency: "GBP"}, "",
+		domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{CardNumber: "5555555555554444"}})
+	require.NoError(t, err)
+	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, payment.PaymentStatus)
+	require.NotNil(t, payment.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), payment.ExpiresAt.AsTime(), time.Minute)
+}
+
+func TestService_Capture_Expired(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		payment     *paymentsV1.Payment
+	}{
+		{
+			description: "given the authorization has expired",
+			payment: &paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+				Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+				ExpiresAt:     timestamppb.New(time.Now().Add(-time.Minute)),
+			},
+		},
+		{
+			description: "given the payment has been marked as expired",
+			payment: &paymentsV1.Payment{
+				Id:            "id",
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED,
+				Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl  = gomock.NewController(t)
+				store = mocks.NewMockStore(ctrl)
+			)
+			store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+			store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(tc.payment, nil)
+
+			service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+			_, err := service.Capture(context.Background(), "id", 1000, false)
+			assert.Equal(t, domain.ErrAuthorizationExpired, err)
+		})
+	}
+}
+
+func TestService_ListExpiredAuthorizations(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl = gomock.NewController(t)
+
+		store         = mocks.NewMockStore(ctrl)
+		payments      = []*paymentsV1.Payment{{Id: "id"}}
+		expiredBefore = time.Now()
+	)
+	store.
+		EXPECT().
+		ListPayments(gomock.Any(), &domain.ListPaymentFilters{
+			Statuses:      []domain.PaymentStatus{domain.PaymentStatusAuthorized, domain.PaymentStatusPartiallyCaptured},
+			ExpiresBefore: expiredBefore,
+			Limit:         10,
+		}).
+		Return(payments, nil)
+
+	service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+	expired, err := service.ListExpiredAuthorizations(context.Background(), expiredBefore, 10)
+	require.NoError(t, err)
+	assert.Equal(t, payments, expired)
+}
+
+func TestService_ExpireAuthorization(t *testing.T) {
+	t.Parallel()
+
+	authorized := func(expiresAt time.Time) *paymentsV1.Payment {
+		return &paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			ExpiresAt:     timestamppb.New(expiresAt),
+		}
+	}
+	inTransaction := func(store *mocks.MockStore) {
+		store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	t.Run("should mark the payment as expired", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(time.Now().Add(-time.Minute)), nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+			Return([]*paymentsV1.PaymentAction{{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"}}, nil)
+		store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+			DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+				assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED, payment.PaymentStatus)
+				return nil
+			})
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1000, 0, 0), nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, entries ...*domain.LedgerEntry) error {
+				// the amount still held is released back to the customer
+				require.Len(t, entries, 1)
+				assert.Equal(t, domain.LedgerAccountCustomer, entries[0].DebitAccount)
+				assert.Equal(t, domain.LedgerAccountAuthorized, entries[0].CreditAccount)
+				assert.Equal(t, uint64(1000), entries[0].Amount)
+				return nil
+			})
+		store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+				assert.Equal(t, "shared.payment.v1.PaymentExpired", event.EventType)
+				return nil
+			})
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		payment, err := service.ExpireAuthorization(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED, payment.PaymentStatus)
+	})
+	t.Run("should capture a partially captured payment releasing the remainder", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl    = gomock.NewController(t)
+			store   = mocks.NewMockStore(ctrl)
+			payment = authorized(time.Now().Add(-time.Minute))
+		)
+		payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(payment, nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return([]*paymentsV1.PaymentAction{
+			{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+			{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, Amount: 400, ResponseCode: "00"},
+		}, nil)
+		store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).Return(nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(600, 400, 0), nil)
+		store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, entries ...*domain.LedgerEntry) error {
+				require.Len(t, entries, 1)
+				assert.Equal(t, uint64(600), entries[0].Amount)
+				return nil
+			})
+		store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		expired, err := service.ExpireAuthorization(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED, expired.PaymentStatus)
+	})
+	t.Run("should not be permitted given the authorization is yet to expire", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(time.Now().Add(time.Hour)), nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.ExpireAuthorization(context.Background(), "id")
+		assert.Equal(t, domain.ErrNotPermitted, err)
+	})
+	t.Run("should not be permitted given an action is awaiting an outcome", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(time.Now().Add(-time.Minute)), nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_VOID}}, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.ExpireAuthorization(context.Background(), "id")
+		assert.Equal(t, domain.ErrNotPermitted, err)
+	})
+}
+
+func TestService_IncrementAuthorization(t *testing.T) {
+	t.Parallel()
+
+	authorized := func() *paymentsV1.Payment {
+		return &paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "400000"}},
+		}
+	}
+	authorization := &paymentsV1.PaymentAction{Id: "auth-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00", Acquirer: "acquirer-a"}
+	inTransaction := func(store *mocks.MockStore) *gomock.Call {
+		return store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	for _, tc := range []struct {
+		description string
+		authCode    string
+		balances    domain.LedgerBalances
+		event       string
+	}{
+		{description: "should increment the authorization given the issuer approves", authCode: "00", balances: ledgerBalances(1250, 0, 0), event: "shared.payment.v1.PaymentAuthorizationIncremented"},
+		{description: "should leave the authorization as is given the issuer declines", authCode: "51", balances: ledgerBalances(1000, 0, 0)},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl              = gomock.NewController(t)
+				store             = mocks.NewMockStore(ctrl)
+				vault             = mocks.NewMockVault(ctrl)
+				mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+			)
+			inTransaction(store).Times(2)
+			store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(), nil).Times(2)
+			store.EXPECT().ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"id"}}).
+				Return([]*paymentsV1.PaymentAction{authorization}, nil)
+			vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(&paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}, nil)
+			store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+					assert.Equal(t, paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, action.PaymentType)
+					assert.Equal(t, uint64(250), action.Amount)
+					action.Id = "increment-id"
+					return nil
+				})
+			mockIssuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, request domain.IssuerRequest) (domain.IssuerResponse, error) {
+					assert.Equal(t, "increment-id", request.Reference)
+					assert.Equal(t, uint64(250), request.Amount.GetMinorUnits())
+					assert.Equal(t, paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, request.OperationType)
+					assert.Equal(t, "acquirer-a", request.Acquirer)
+					return domain.IssuerResponse{AuthCode: tc.authCode, Acquirer: "acquirer-a"}, nil
+				})
+			store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(tc.balances, nil)
+			if tc.event != "" {
+				store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).Return(nil)
+				store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, tc.event, event.EventType)
+						return nil
+					})
+			}
+
+			service := gateway.NewService(store, mockIssuerGateway, vault)
+			payment, err := service.IncrementAuthorization(context.Background(), "id", 250)
+			require.NoError(t, err)
+			assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, payment.PaymentStatus)
+		})
+	}
+	t.Run("should return error given the authorization has expired", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl    = gomock.NewController(t)
+			store   = mocks.NewMockStore(ctrl)
+			payment = authorized()
+		)
+		payment.ExpiresAt = timestamppb.New(time.Now().Add(-time.Minute))
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(payment, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.IncrementAuthorization(context.Background(), "id", 250)
+		assert.Equal(t, domain.ErrAuthorizationExpired, err)
+	})
+	t.Run("should not be permitted given the payment has been captured", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl    = gomock.NewController(t)
+			store   = mocks.NewMockStore(ctrl)
+			payment = authorized()
+		)
+		payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(payment, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.IncrementAuthorization(context.Background(), "id", 250)
+		assert.Equal(t, domain.TransitionError{PaymentType: domain.PaymentTypeIncrementalAuthorization, From: domain.PaymentStatusPartiallyCaptured}, err)
+	})
+	t.Run("should not be permitted given a void is awaiting an outcome", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(authorized(), nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{authorization, {PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_VOID}}, nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.IncrementAuthorization(context.Background(), "id", 250)
+		assert.Equal(t, domain.ErrNotPermitted, err)
+	})
+}
+
+func TestService_Capture_Incremented(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl              = gomock.NewController(t)
+		store             = mocks.NewMockStore(ctrl)
+		vault             = mocks.NewMockVault(ctrl)
+		mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		actions           = []*paymentsV1.PaymentAction{
+			{Id: "auth-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+			{Id: "increment-id", Amount: 250, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_INCREMENTAL_AUTHORIZATION, ResponseCode: "00"},
+		}
+	)
+	payment := func() *paymentsV1.Payment {
+		return &paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}},
+		}
+	}
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).Times(2)
+	store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").DoAndReturn(func(ctx context.Context, id string) (*paymentsV1.Payment, error) {
+		return payment(), nil
+	}).Times(2)
+	store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(actions, nil)
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(1250, 0, 0), nil)
+	vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(&paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}, nil)
+	store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).Return(nil)
+	mockIssuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+	store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(0, 1250, 0), nil)
+	store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).Return(nil)
+	store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil)
+
+	service := gateway.NewService(store, mockIssuerGateway, vault)
+	captured, err := service.Capture(context.Background(), "id", 1250, false)
+	require.NoError(t, err)
+	// the incremented amount is captured in full
+	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED, captured.PaymentStatus)
+}
+
+func TestService_Reverse(t *testing.T) {
+	t.Parallel()
+
+	authorization := &paymentsV1.PaymentAction{Id: "auth-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00", Acquirer: "acquirer-a"}
+	capture := &paymentsV1.PaymentAction{Id: "capture-id", Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"}
+	refund := func(amount uint64) *paymentsV1.PaymentAction {
+		return &paymentsV1.PaymentAction{Id: "refund-id", Amount: amount, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_REFUND, ResponseCode: "00"}
+	}
+	newPayment := func(status paymentsV1.PaymentStatus) *paymentsV1.Payment {
+		return &paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: status,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}},
+		}
+	}
+	inTransaction := func(store *mocks.MockStore) *gomock.Call {
+		return store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	for _, tc := range []struct {
+		description string
+		status      paymentsV1.PaymentStatus
+		actions     []*paymentsV1.PaymentAction
+		balances    domain.LedgerBalances
+		expBalances domain.LedgerBalances
+		amount      uint64
+		expAmount   uint64
+		authCode    string
+		expStatus   paymentsV1.PaymentStatus
+	}{
+		{
+			description: "should capture the payment given the remainder of a partial capture is reversed",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+			actions:     []*paymentsV1.PaymentAction{authorization, capture},
+			balances:    ledgerBalances(600, 400, 0),
+			expBalances: ledgerBalances(0, 400, 0),
+			expAmount:   600,
+			authCode:    "00",
+			expStatus:   paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
+		},
+		{
+			description: "should remain partially captured given part of the remainder is reversed",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+			actions:     []*paymentsV1.PaymentAction{authorization, capture},
+			balances:    ledgerBalances(600, 400, 0),
+			expBalances: ledgerBalances(400, 400, 0),
+			amount:      200,
+			expAmount:   200,
+			authCode:    "00",
+			expStatus:   paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+		},
+		{
+			description: "should void the payment given all of an authorization is reversed",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			actions:     []*paymentsV1.PaymentAction{authorization},
+			balances:    ledgerBalances(1000, 0, 0),
+			expBalances: ledgerBalances(0, 0, 0),
+			expAmount:   1000,
+			authCode:    "00",
+			expStatus:   paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED,
+		},
+		{
+			description: "should remain partially refunded given the remainder of a partial capture is reversed",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
+			actions:     []*paymentsV1.PaymentAction{authorization, capture, refund(100)},
+			balances:    ledgerBalances(600, 400, 100),
+			expBalances: ledgerBalances(0, 400, 100),
+			expAmount:   600,
+			authCode:    "00",
+			expStatus:   paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
+		},
+		{
+			description: "should remain refunded given the remainder of a partial capture is reversed",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED,
+			actions:     []*paymentsV1.PaymentAction{authorization, capture, refund(400)},
+			balances:    ledgerBalances(600, 400, 400),
+			expBalances: ledgerBalances(0, 400, 400),
+			expAmount:   600,
+			authCode:    "00",
+			expStatus:   paymentsV1.PaymentStatus_PAYMENT_STATUS_REFUNDED,
+		},
+		{
+			description: "should leave the payment as is given the issuer declines",
+			status:      paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+			actions:     []*paymentsV1.PaymentAction{authorization, capture},
+			balances:    ledgerBalances(600, 400, 0),
+			expBalances: ledgerBalances(600, 400, 0),
+			expAmount:   600,
+			authCode:    "51",
+			expStatus:   paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl              = gomock.NewController(t)
+				store             = mocks.NewMockStore(ctrl)
+				vault             = mocks.NewMockVault(ctrl)
+				mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+			)
+			inTransaction(store).Times(2)
+			store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").
+				DoAndReturn(func(ctx context.Context, id string) (*paymentsV1.Payment, error) {
+					return newPayment(tc.status), nil
+				}).Times(2)
+			store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(tc.actions, nil)
+			store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(tc.balances, nil)
+			vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(&paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}, nil)
+			store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+					assert.Equal(t, paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL, action.PaymentType)
+					assert.Equal(t, tc.expAmount, action.Amount)
+					action.Id = "reversal-id"
+					return nil
+				})
+			mockIssuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, request domain.IssuerRequest) (domain.IssuerResponse, error) {
+					assert.Equal(t, "reversal-id", request.Reference)
+					assert.Equal(t, tc.expAmount, request.Amount.GetMinorUnits())
+					assert.Equal(t, paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL, request.OperationType)
+					assert.Equal(t, "acquirer-a", request.Acquirer)
+					return domain.IssuerResponse{AuthCode: tc.authCode, Acquirer: "acquirer-a"}, nil
+				})
+			store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(tc.expBalances, nil)
+			if tc.authCode == "00" {
+				store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).Return(nil)
+				store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, "shared.payment.v1.PaymentAuthorizationReversed", event.EventType)
+						return nil
+					})
+			}
+
+			service := gateway.NewService(store, mockIssuerGateway, vault)
+			payment, err := service.Reverse(context.Background(), "id", tc.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expStatus, payment.PaymentStatus)
+		})
+	}
+	t.Run("should not be permitted given the amount exceeds what is left uncaptured", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(newPayment(paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED), nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{authorization, capture}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(600, 400, 0), nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.Reverse(context.Background(), "id", 601)
+		assert.Equal(t, domain.ErrNotPermitted, err)
+	})
+	t.Run("should not be permitted given a capture awaiting an outcome holds the remainder", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(newPayment(paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED), nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{authorization, capture, {Amount: 600, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE}}, nil)
+		store.EXPECT().GetLedgerBalances(gomock.Any(), "id").Return(ledgerBalances(600, 400, 0), nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.Reverse(context.Background(), "id", 0)
+		assert.Equal(t, domain.ErrNotPermitted, err)
+	})
+	t.Run("should not be permitted given the payment has been captured", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		inTransaction(store)
+		store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(newPayment(paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED), nil)
+
+		service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+		_, err := service.Reverse(context.Background(), "id", 0)
+		assert.Equal(t, domain.TransitionError{PaymentType: domain.PaymentTypeReversal, From: domain.PaymentStatusCaptured}, err)
+	})
+}
+
+func TestService_Capture_Final(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl              = gomock.NewController(t)
+		store             = mocks.NewMockStore(ctrl)
+		vault             = mocks.NewMockVault(ctrl)
+		mockIssuerGateway = mocks.NewMockIssuerGateway(ctrl)
+		status            = paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
+		actions           = []*paymentsV1.PaymentAction{
+			{Id: "auth-id", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+		}
+		balances = ledgerBalances(1000, 0, 0)
+		events   []string
+	)
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).Times(4)
+	store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").DoAndReturn(func(ctx context.Context, id string) (*paymentsV1.Payment, error) {
+		return &paymentsV1.Payment{
+			Id:            "id",
+			PaymentStatus: status,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}},
+		}, nil
+	}).Times(4)
+	store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, filters *domain.ListPaymentActionFilters) ([]*paymentsV1.PaymentAction, error) {
+			return actions, nil
+		}).Times(2)
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "id").
+		DoAndReturn(func(ctx context.Context, id string) (domain.LedgerBalances, error) {
+			return balances, nil
+		}).Times(4)
+	vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").Return(&paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}, nil).Times(2)
+	store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+			action.Id = action.PaymentType.String()
+			actions = append(actions, action)
+			return nil
+		}).Times(2)
+	mockIssuerGateway.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{AuthCode: "00"}, nil).Times(2)
+	store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entries ...*domain.LedgerEntry) error {
+			balances.Post(entries...)
+			return nil
+		}).Times(2)
+	store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), domain.UpdatePaymentFieldStatus).
+		DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+			status = payment.PaymentStatus
+			return nil
+		}).Times(2)
+	store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+			events = append(events, event.EventType)
+			return nil
+		}).Times(2)
+
+	service := gateway.NewService(store, mockIssuerGateway, vault)
+	payment, err := service.Capture(context.Background(), "id", 400, true)
+	require.NoError(t, err)
+	// the remaining 600 is reversed once the final capture succeeds
+	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED, payment.PaymentStatus)
+	assert.Equal(t, uint64(600), actions[2].Amount)
+	assert.Equal(t, paymentsV1.PaymentType_PAYMENT_TYPE_REVERSAL, actions[2].PaymentType)
+	assert.Equal(t, []string{"shared.payment.v1.PaymentCaptured", "shared.payment.v1.PaymentAuthorizationReversed"}, events)
+	assert.Equal(t, ledgerBalances(0, 400, 0), balances)
+}
+
+func TestService_Capture_DeclinedPayment(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl  = gomock.NewController(t)
+		store = mocks.NewMockStore(ctrl)
+	)
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+	store.EXPECT().GetPaymentForUpdate(gomock.Any(), "id").Return(&paymentsV1.Payment{
+		Id:            "id",
+		PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED,
+		Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+	}, nil)
+	store.EXPECT().ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{
+		PaymentIDs:   []string{"id"},
+		PaymentTypes: []domain.PaymentType{domain.PaymentTypeAuthorization},
+	}).Return([]*paymentsV1.PaymentAction{
+		{PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "54", DeclineReason: "expired card"},
+	}, nil)
+
+	service := gateway.NewService(store, mocks.NewMockIssuerGateway(ctrl), mocks.NewMockVault(ctrl))
+	_, err := service.Capture(context.Background(), "id", 1000, false)
+	// the reason the payment was declined is reported alongside the rejected transition
+	var declinedErr domain.DeclinedError
+	require.True(t, errors.As(err, &declinedErr))
+	assert.Equal(t, "expired card", declinedErr.Reason)
+	assert.True(t, errors.Is(err, domain.ErrNotPermitted))
 }
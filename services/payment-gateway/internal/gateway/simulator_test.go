@@ -0,0 +1,198 @@
+package gateway_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/gateway"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/gateway/mocks"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/resilience"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// simulatedPayment holds the payment and its authorization as stored by the service, standing in for the store
+// whilst the service is driven against the simulator.
+type simulatedPayment struct {
+	mu      sync.Mutex
+	payment *paymentsV1.Payment
+	action  *paymentsV1.PaymentAction
+}
+
+func (p *simulatedPayment) status() paymentsV1.PaymentStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.payment.PaymentStatus
+}
+
+func (p *simulatedPayment) responseCode() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.action.ResponseCode
+}
+
+// newSimulatedService returns a service authorizing against the issuer, with its store and vault keeping the payment
+// it creates.
+func newSimulatedService(t *testing.T, issuer gateway.IssuerGateway) (gateway.Service, *simulatedPayment) {
+	var (
+		ctrl      = gomock.NewController(t)
+		store     = mocks.NewMockStore(ctrl)
+		vault     = mocks.NewMockVault(ctrl)
+		simulated = &simulatedPayment{}
+		card      = &paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "400000", LastFour: "0077"}
+	)
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	vault.EXPECT().Tokenize(gomock.Any(), gomock.Any()).Return(card, nil).AnyTimes()
+	vault.EXPECT().Detokenize(gomock.Any(), "tok_abc").
+		Return(&paymentsV1.PaymentMethodCard{CardNumber: "4000000000000077", Token: "tok_abc"}, nil).AnyTimes()
+
+	store.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment) error {
+			simulated.mu.Lock()
+			defer simulated.mu.Unlock()
+			payment.Id = "payment-id"
+			simulated.payment = proto.Clone(payment).(*paymentsV1.Payment)
+			return nil
+		}).AnyTimes()
+	store.EXPECT().CreatePaymentAction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction) error {
+			simulated.mu.Lock()
+			defer simulated.mu.Unlock()
+			action.Id = "action-id"
+			simulated.action = proto.Clone(action).(*paymentsV1.PaymentAction)
+			return nil
+		}).AnyTimes()
+	store.EXPECT().UpdatePaymentAction(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
+			simulated.mu.Lock()
+			defer simulated.mu.Unlock()
+			simulated.action = proto.Clone(action).(*paymentsV1.PaymentAction)
+			return nil
+		}).AnyTimes()
+	store.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, payment *paymentsV1.Payment, fields ...domain.UpdatePaymentField) error {
+			simulated.mu.Lock()
+			defer simulated.mu.Unlock()
+			simulated.payment = proto.Clone(payment).(*paymentsV1.Payment)
+			return nil
+		}).AnyTimes()
+	getPayment := func(ctx context.Context, id string) (*paymentsV1.Payment, error) {
+		simulated.mu.Lock()
+		defer simulated.mu.Unlock()
+		return proto.Clone(simulated.payment).(*paymentsV1.Payment), nil
+	}
+	store.EXPECT().GetPayment(gomock.Any(), "payment-id").DoAndReturn(getPayment).AnyTimes()
+	store.EXPECT().GetPaymentForUpdate(gomock.Any(), "payment-id").DoAndReturn(getPayment).AnyTimes()
+	store.EXPECT().GetPaymentActionForUpdate(gomock.Any(), "action-id").
+		DoAndReturn(func(ctx context.Context, id string) (*paymentsV1.PaymentAction, error) {
+			simulated.mu.Lock()
+			defer simulated.mu.Unlock()
+			return proto.Clone(simulated.action).(*paymentsV1.PaymentAction), nil
+		}).AnyTimes()
+	store.EXPECT().GetLedgerBalances(gomock.Any(), "payment-id").Return(domain.LedgerBalances{}, nil).AnyTimes()
+	store.EXPECT().CreateLedgerEntries(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	store.EXPECT().CreateOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return gateway.NewService(store, issuer, vault), simulated
+}
+
+func newSimulator(t *testing.T, rules ...simulator.Rule) *simulator.Simulator {
+	issuer, err := simulator.New(simulator.Scenario{Rules: rules}, simulator.WithSeed(1))
+	require.NoError(t, err)
+	return issuer
+}
+
+func TestService_Simulator(t *testing.T) {
+	t.Parallel()
+
+	var (
+		amount = &amountV1.Money{MinorUnits: 1000, Currency: "GBP"}
+		method = func(cardNumber string) domain.PaymentMethod {
+			return domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{
+				CardNumber: cardNumber,
+				Cvv:        "123",
+				Expiry:     &paymentsV1.PaymentMethodCard_ExpiryDate{Month: 12, Year: 2030},
+			}}
+		}
+	)
+
+	t.Run("should decline the payment with the issuer's reason", func(t *testing.T) {
+		t.Parallel()
+		service, simulated := newSimulatedService(t, newSimulator(t, simulator.Rule{
+			CardNumbers: []string{"4000000000000077"},
+			Operations:  []string{"authorization"},
+			// insufficient funds
+			ResponseCode: "51",
+		}))
+
+		payment, err := service.CreatePayment(context.Background(), amount, "", method("4000000000000077"))
+		require.NoError(t, err)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED, payment.PaymentStatus)
+		assert.Equal(t, "insufficient funds", payment.DeclineReason)
+		assert.Equal(t, "51", simulated.responseCode())
+	})
+
+	t.Run("should wait out the issuer's latency", func(t *testing.T) {
+		t.Parallel()
+		const latency = 50 * time.Millisecond
+		service, _ := newSimulatedService(t, newSimulator(t, simulator.Rule{Latency: latency}))
+
+		start := time.Now()
+		payment, err := service.CreatePayment(context.Background(), amount, "", method("4000000000000077"))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(latency))
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, payment.PaymentStatus)
+	})
+
+	t.Run("should give up on an issuer slower than the timeout", func(t *testing.T) {
+		t.Parallel()
+		issuer := resilience.NewGateway(t.Name(), newSimulator(t, simulator.Rule{Latency: time.Second}),
+			resilience.WithTimeouts(20*time.Millisecond, time.Second))
+		service, simulated := newSimulatedService(t, issuer)
+
+		_, err := service.CreatePayment(context.Background(), amount, "", method("4000000000000077"))
+		assert.ErrorIs(t, err, domain.ErrIssuerUnavailable)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING, simulated.status())
+	})
+
+	t.Run("should decline the payment given the issuer's circuit breaker is open", func(t *testing.T) {
+		t.Parallel()
+		issuer := resilience.NewGateway(t.Name(), newSimulator(t), resilience.WithBreaker(1, time.Minute))
+		issuer.Breaker().Failure()
+		service, simulated := newSimulatedService(t, issuer)
+
+		_, err := service.CreatePayment(context.Background(), amount, "", method("4000000000000077"))
+		assert.ErrorIs(t, err, domain.ErrCircuitOpen)
+		// nothing was sent so the authorization is recorded as failed rather than left for the recovery sweeper
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED, simulated.status())
+		assert.Equal(t, domain.IssuerResponseCodeNoRecord, simulated.responseCode())
+	})
+
+	t.Run("should recover the outcome of an authorization whose response timed out", func(t *testing.T) {
+		t.Parallel()
+		issuer := resilience.NewGateway(t.Name(), newSimulator(t, simulator.Rule{Timeout: true}),
+			resilience.WithTimeouts(20*time.Millisecond, time.Second))
+		service, simulated := newSimulatedService(t, issuer)
+
+		_, err := service.CreatePayment(context.Background(), amount, "", method("4000000000000077"))
+		assert.ErrorIs(t, err, domain.ErrIssuerUnavailable)
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING, simulated.status())
+		assert.Empty(t, simulated.responseCode())
+
+		// the issuer authorized the payment, the recovery sweeper learns as much from a status inquiry
+		require.NoError(t, service.RecoverPaymentAction(context.Background(), "action-id"))
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED, simulated.status())
+		assert.Equal(t, "00", simulated.responseCode())
+	})
+}
@@ -0,0 +1,133 @@
+//go:generate mockgen -source=checker.go -destination=mocks/mocks.go -package=mocks
+
+package ledger
+
+import (
+	"context"
+	"fmt"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+)
+
+const DefaultBatchSize = 100
+
+type Store interface {
+	ListPayments(ctx context.Context, filters *domain.ListPaymentFilters) ([]*paymentsV1.Payment, error)
+	ListPaymentActions(ctx context.Context, filters *domain.ListPaymentActionFilters) ([]*paymentsV1.PaymentAction, error)
+	ListLedgerBalances(ctx context.Context, paymentIDs []string) (map[string]domain.LedgerBalances, error)
+}
+
+// Discrepancy is a payment whose ledger does not reconcile with its payment actions.
+type Discrepancy struct {
+	PaymentID string
+	// Expected are the balances derived from the payment's successful actions.
+	Expected domain.LedgerBalances
+	Actual   domain.LedgerBalances
+	Err      error
+}
+
+func (d Discrepancy) Error() string {
+	return fmt.Sprintf("payment %s: %v", d.PaymentID, d.Err)
+}
+
+// Report is the outcome of checking the ledger.
+type Report struct {
+	Checked       int
+	Discrepancies []Discrepancy
+}
+
+// Checker proves the ledger reconciles with the payment actions it records. Every payment's balances are
+// recomputed from its successful actions and compared against those posted to the ledger, which must also hold the
+// ledger's invariants.
+type Checker struct {
+	store     Store
+	batchSize uint64
+}
+
+func NewChecker(store Store) Checker {
+	return Checker{store: store, batchSize: DefaultBatchSize}
+}
+
+// Check checks the ledger of every payment, a batch at a time. An error is only returned should the payments be unable
+// to be read, discrepancies found are reported.
+func (c Checker) Check(ctx context.Context) (Report, error) {
+	var (
+		report Report
+		cursor *domain.PaymentCursor
+	)
+	for {
+		payments, err := c.store.ListPayments(ctx, &domain.ListPaymentFilters{Cursor: cursor, Limit: c.batchSize})
+		if err != nil {
+			return report, err
+		}
+		if len(payments) == 0 {
+			return report, nil
+		}
+		discrepancies, err := c.checkBatch(ctx, payments)
+		if err != nil {
+			return report, err
+		}
+		report.Checked += len(payments)
+		report.Discrepancies = append(report.Discrepancies, discrepancies...)
+
+		if uint64(len(payments)) < c.batchSize {
+			return report, nil
+		}
+		last := payments[len(payments)-1]
+		cursor = &domain.PaymentCursor{ID: last.Id}
+	}
+}
+
+func (c Checker) checkBatch(ctx context.Context, payments []*paymentsV1.Payment) ([]Discrepancy, error) {
+	paymentIDs := make([]string, 0, len(payments))
+	for _, payment := range payments {
+		paymentIDs = append(paymentIDs, payment.Id)
+	}
+	actions, err := c.store.ListPaymentActions(ctx, &domain.ListPaymentActionFilters{PaymentIDs: paymentIDs})
+	if err != nil {
+		return nil, err
+	}
+	balances, err := c.store.ListLedgerBalances(ctx, paymentIDs)
+	if err != nil {
+		return nil, err
+	}
+	actionsByPayment := make(map[string][]*paymentsV1.PaymentAction, len(payments))
+	for _, action := range actions {
+		actionsByPayment[action.PaymentId] = append(actionsByPayment[action.PaymentId], action)
+	}
+
+	var discrepancies []Discrepancy
+	for _, payment := range payments {
+		expected := ExpectedBalances(payment, actionsByPayment[payment.Id])
+		actual := balances[payment.Id]
+		if err = actual.Validate(); err == nil && actual != expected {
+			err = fmt.Errorf("ledger balances %+v do not match payment actions %+v", actual, expected)
+		}
+		if err != nil {
+			discrepancies = append(discrepancies, Discrepancy{PaymentID: payment.Id, Expected: expected, Actual: actual, Err: err})
+		}
+	}
+	return discrepancies, nil
+}
+
+// ExpectedBalances returns the balances the ledger should hold for the payment given its actions. Only actions the
+// issuer approved move funds, a payment whose hold was released on expiry (see domain.HoldReleased) has whatever was
+// still held released.
+func ExpectedBalances(payment *paymentsV1.Payment, actions []*paymentsV1.PaymentAction) domain.LedgerBalances {
+	var balances domain.LedgerBalances
+	for _, action := range actions {
+		if !domain.LookupResponseCode(action.ResponseCode).Approved() {
+			continue
+		}
+		var paymentType domain.PaymentType
+		_ = paymentType.FromProto(action.PaymentType)
+		balances.Post(domain.NewLedgerEntries(payment.Id, action.Id, paymentType, action.Amount, payment.Amount.GetCurrency())...)
+	}
+	var status domain.PaymentStatus
+	_ = status.FromProto(payment.PaymentStatus)
+	if domain.HoldReleased(status) {
+		balances.Post(domain.NewLedgerRelease(payment.Id, balances.Held(), payment.Amount.GetCurrency())...)
+	}
+	return balances
+}
@@ -0,0 +1,149 @@
+package ledger_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/ledger"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/ledger/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	newPayment := func(id string, status paymentsV1.PaymentStatus) *paymentsV1.Payment {
+		return &paymentsV1.Payment{
+			Id:            id,
+			PaymentStatus: status,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			CreatedAt:     timestamppb.Now(),
+		}
+	}
+
+	t.Run("should return error given unable to list payments", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		store.EXPECT().ListPayments(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := ledger.NewChecker(store).Check(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("should report payments whose ledger does not reconcile with their actions", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		store.EXPECT().ListPayments(gomock.Any(), &domain.ListPaymentFilters{Limit: ledger.DefaultBatchSize}).
+			Return([]*paymentsV1.Payment{
+				newPayment("captured", paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED),
+				newPayment("expired", paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED),
+				newPayment("missing", paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED),
+				newPayment("unbalanced", paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED),
+			}, nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), &domain.ListPaymentActionFilters{PaymentIDs: []string{"captured", "expired", "missing", "unbalanced"}}).
+			Return([]*paymentsV1.PaymentAction{
+				{PaymentId: "captured", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+				{PaymentId: "captured", Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+				{PaymentId: "captured", Amount: 600, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "51"},
+				{PaymentId: "expired", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+				{PaymentId: "missing", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+				{PaymentId: "missing", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+				{PaymentId: "unbalanced", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+			}, nil)
+		store.EXPECT().ListLedgerBalances(gomock.Any(), []string{"captured", "expired", "missing", "unbalanced"}).
+			Return(map[string]domain.LedgerBalances{
+				"captured":   {Customer: 600, Authorized: 600, Captured: 400, MerchantPayable: 400},
+				"missing":    {Customer: 1000, Authorized: 1000},
+				"unbalanced": {Authorized: 1000},
+			}, nil)
+
+		report, err := ledger.NewChecker(store).Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.Checked)
+		require.Len(t, report.Discrepancies, 2)
+		assert.Equal(t, "missing", report.Discrepancies[0].PaymentID)
+		assert.Equal(t, domain.LedgerBalances{Captured: 1000, MerchantPayable: 1000}, report.Discrepancies[0].Expected)
+		assert.Contains(t, report.Discrepancies[0].Error(), "do not match payment actions")
+		assert.Equal(t, "unbalanced", report.Discrepancies[1].PaymentID)
+		assert.Contains(t, report.Discrepancies[1].Error(), "accounts do not balance")
+	})
+
+	t.Run("should expect the remainder of a partial capture that expired to be released", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl  = gomock.NewController(t)
+			store = mocks.NewMockStore(ctrl)
+		)
+		store.EXPECT().ListPayments(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.Payment{newPayment("expired", paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED)}, nil)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.PaymentAction{
+				{PaymentId: "expired", Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, ResponseCode: "00"},
+				{PaymentId: "expired", Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, ResponseCode: "00"},
+			}, nil)
+		// as posted by the gateway expiring the authorization, the remaining 600 released without an action
+		store.EXPECT().ListLedgerBalances(gomock.Any(), []string{"expired"}).
+			Return(map[string]domain.LedgerBalances{"expired": {Captured: 400, MerchantPayable: 400}}, nil)
+
+		report, err := ledger.NewChecker(store).Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Checked)
+		assert.Empty(t, report.Discrepancies)
+	})
+
+	t.Run("should page through the payments", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl     = gomock.NewController(t)
+			store    = mocks.NewMockStore(ctrl)
+			payments = make([]*paymentsV1.Payment, ledger.DefaultBatchSize)
+		)
+		for i := range payments {
+			payments[i] = newPayment("id", paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED)
+		}
+		last := payments[len(payments)-1]
+		gomock.InOrder(
+			store.EXPECT().ListPayments(gomock.Any(), &domain.ListPaymentFilters{Limit: ledger.DefaultBatchSize}).Return(payments, nil),
+			store.EXPECT().ListPayments(gomock.Any(), &domain.ListPaymentFilters{
+				Cursor: &domain.PaymentCursor{ID: last.Id},
+				Limit:  ledger.DefaultBatchSize,
+			}).Return(nil, nil),
+		)
+		store.EXPECT().ListPaymentActions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		store.EXPECT().ListLedgerBalances(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		report, err := ledger.NewChecker(store).Check(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, ledger.DefaultBatchSize, report.Checked)
+		assert.Empty(t, report.Discrepancies)
+	})
+}
+
+func TestHoldReleasedStatuses_Backfill(t *testing.T) {
+	t.Parallel()
+
+	// the ledger backfill must release the same holds the checker expects released
+	migration, err := ioutil.ReadFile("../migrations/14_ledger.up.sql")
+	require.NoError(t, err)
+	statuses := make([]string, 0, len(domain.HoldReleasedStatuses))
+	for _, status := range domain.HoldReleasedStatuses {
+		statuses = append(statuses, fmt.Sprintf("'%s'", status))
+	}
+	assert.Contains(t, string(migration), fmt.Sprintf("p.status IN (%s)", strings.Join(statuses, ", ")))
+}
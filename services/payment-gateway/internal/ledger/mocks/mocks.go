@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: checker.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	domain "github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ListLedgerBalances mocks base method.
+func (m *MockStore) ListLedgerBalances(ctx context.Context, paymentIDs []string) (map[string]domain.LedgerBalances, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerBalances", ctx, paymentIDs)
+	ret0, _ := ret[0].(map[string]domain.LedgerBalances)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerBalances indicates an expected call of ListLedgerBalances.
+func (mr *MockStoreMockRecorder) ListLedgerBalances(ctx, paymentIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerBalances", reflect.TypeOf((*MockStore)(nil).ListLedgerBalances), ctx, paymentIDs)
+}
+
+// ListPaymentActions mocks base method.
+func (m *MockStore) ListPaymentActions(ctx context.Context, filters *domain.ListPaymentActionFilters) ([]*v1.PaymentAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentActions", ctx, filters)
+	ret0, _ := ret[0].([]*v1.PaymentAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentActions indicates an expected call of ListPaymentActions.
+func (mr *MockStoreMockRecorder) ListPaymentActions(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentActions", reflect.TypeOf((*MockStore)(nil).ListPaymentActions), ctx, filters)
+}
+
+// ListPayments mocks base method.
+func (m *MockStore) ListPayments(ctx context.Context, filters *domain.ListPaymentFilters) ([]*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayments", ctx, filters)
+	ret0, _ := ret[0].([]*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayments indicates an expected call of ListPayments.
+func (mr *MockStoreMockRecorder) ListPayments(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayments", reflect.TypeOf((*MockStore)(nil).ListPayments), ctx, filters)
+}
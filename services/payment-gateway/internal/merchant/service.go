@@ -0,0 +1,126 @@
+//go:generate mockgen -source=service.go -destination=mocks/mocks.go -package=mocks
+package merchant
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	// APIKeyPrefix starts every api key so that they are recognisable, such as by secret scanners.
+	APIKeyPrefix = "sk_"
+	apiKeyBytes  = 32
+	// displayPrefixLen is how much of the key is stored in the clear to tell keys apart.
+	displayPrefixLen = 11
+)
+
+type Store interface {
+	ExecInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	CreateMerchant(ctx context.Context, merchant *domain.Merchant) error
+	GetMerchant(ctx context.Context, id string) (*domain.Merchant, error)
+	UpdateMerchantCardBrands(ctx context.Context, merchant *domain.Merchant) error
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	RevokeAPIKeys(ctx context.Context, merchantID string) error
+}
+
+// Service manages merchants and authenticates them by their api keys. Keys are only held as a hash, they are
+// returned once when issued and cannot be retrieved afterwards.
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return Service{store: store}
+}
+
+// CreateMerchant creates the merchant along with its first api key.
+func (s Service) CreateMerchant(ctx context.Context, name string) (*domain.Merchant, string, error) {
+	merchant := &domain.Merchant{Name: name}
+	var apiKey string
+	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.CreateMerchant(ctx, merchant); err != nil {
+			return err
+		}
+		var err error
+		apiKey, err = s.issueAPIKey(ctx, merchant)
+		return err
+	}); err != nil {
+		return nil, "", err
+	}
+	return merchant, apiKey, nil
+}
+
+// RotateAPIKey revokes the merchant's api keys issuing a new one in their place.
+func (s Service) RotateAPIKey(ctx context.Context, merchantID string) (string, error) {
+	var apiKey string
+	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		merchant, err := s.store.GetMerchant(ctx, merchantID)
+		if err != nil {
+			return err
+		}
+		if err = s.store.RevokeAPIKeys(ctx, merchantID); err != nil {
+			return err
+		}
+		apiKey, err = s.issueAPIKey(ctx, merchant)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return apiKey, nil
+}
+
+// SetAcceptedCardBrands sets the card brands the merchant accepts payments with, every brand is accepted when none
+// are set.
+func (s Service) SetAcceptedCardBrands(ctx context.Context, merchantID string, brands []domain.CardBrand) (*domain.Merchant, error) {
+	var merchant *domain.Merchant
+	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if merchant, err = s.store.GetMerchant(ctx, merchantID); err != nil {
+			return err
+		}
+		merchant.AcceptedCardBrands = brands
+		return s.store.UpdateMerchantCardBrands(ctx, merchant)
+	}); err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// Authenticate returns the id of the merchant the api key belongs to, domain.ErrNoAPIKey is returned for unknown and
+// revoked keys.
+func (s Service) Authenticate(ctx context.Context, apiKey string) (string, error) {
+	key, err := s.store.GetAPIKeyByHash(ctx, hashAPIKey(apiKey))
+	if err != nil {
+		return "", err
+	}
+	return key.MerchantID.String(), nil
+}
+
+func (s Service) issueAPIKey(ctx context.Context, merchant *domain.Merchant) (string, error) {
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "unable to generate api key")
+	}
+	apiKey := APIKeyPrefix + hex.EncodeToString(secret)
+	if err := s.store.CreateAPIKey(ctx, &domain.APIKey{
+		MerchantID: merchant.ID,
+		Prefix:     apiKey[:displayPrefixLen],
+		KeyHash:    hashAPIKey(apiKey),
+	}); err != nil {
+		return "", err
+	}
+	return apiKey, nil
+}
+
+// hashAPIKey hashes the key for storage. Keys are random so a fast hash is enough, unlike a password they cannot be
+// guessed from a dictionary.
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
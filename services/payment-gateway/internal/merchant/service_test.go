@@ -0,0 +1,164 @@
+package merchant_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/merchant"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/merchant/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func execInTransaction(store *mocks.MockStore) {
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestService_CreateMerchant(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl       = gomock.NewController(t)
+		store      = mocks.NewMockStore(ctrl)
+		merchantID = uuid.NewV4()
+		storedKey  *domain.APIKey
+	)
+	execInTransaction(store)
+	store.EXPECT().CreateMerchant(gomock.Any(), &domain.Merchant{Name: "merchant"}).
+		DoAndReturn(func(ctx context.Context, m *domain.Merchant) error {
+			m.ID = merchantID
+			return nil
+		})
+	store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key *domain.APIKey) error {
+		storedKey = key
+		return nil
+	})
+
+	m, apiKey, err := merchant.NewService(store).CreateMerchant(context.Background(), "merchant")
+	require.NoError(t, err)
+	assert.Equal(t, merchantID, m.ID)
+	assert.True(t, strings.HasPrefix(apiKey, merchant.APIKeyPrefix))
+
+	require.NotNil(t, storedKey)
+	assert.Equal(t, merchantID, storedKey.MerchantID)
+	assert.Equal(t, hash(apiKey), storedKey.KeyHash)
+	assert.True(t, strings.HasPrefix(apiKey, storedKey.Prefix))
+}
+
+func TestService_RotateAPIKey(t *testing.T) {
+	t.Parallel()
+	merchantID := uuid.NewV4()
+
+	for _, tc := range []struct {
+		description string
+		fn          func(store *mocks.MockStore)
+		err         error
+	}{
+		{
+			description: "should revoke the merchant's keys issuing a new one",
+			fn: func(store *mocks.MockStore) {
+				execInTransaction(store)
+				gomock.InOrder(
+					store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(&domain.Merchant{ID: merchantID}, nil),
+					store.EXPECT().RevokeAPIKeys(gomock.Any(), merchantID.String()).Return(nil),
+					store.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+		},
+		{
+			description: "should return error given the merchant does not exist",
+			fn: func(store *mocks.MockStore) {
+				execInTransaction(store)
+				store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(nil, domain.ErrNoMerchant)
+			},
+			err: domain.ErrNoMerchant,
+		},
+		{
+			description: "should return error given the keys cannot be revoked",
+			fn: func(store *mocks.MockStore) {
+				execInTransaction(store)
+				store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(&domain.Merchant{ID: merchantID}, nil)
+				store.EXPECT().RevokeAPIKeys(gomock.Any(), merchantID.String()).Return(errors.New("boom"))
+			},
+			err: errors.New("boom"),
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			store := mocks.NewMockStore(gomock.NewController(t))
+			tc.fn(store)
+
+			apiKey, err := merchant.NewService(store).RotateAPIKey(context.Background(), merchantID.String())
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(apiKey, merchant.APIKeyPrefix))
+		})
+	}
+}
+
+func TestService_SetAcceptedCardBrands(t *testing.T) {
+	t.Parallel()
+	merchantID := uuid.NewV4()
+	brands := []domain.CardBrand{domain.CardBrandVisa, domain.CardBrandMastercard}
+
+	t.Run("should store the brands against the merchant", func(t *testing.T) {
+		t.Parallel()
+		store := mocks.NewMockStore(gomock.NewController(t))
+		execInTransaction(store)
+		gomock.InOrder(
+			store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(&domain.Merchant{ID: merchantID}, nil),
+			store.EXPECT().UpdateMerchantCardBrands(gomock.Any(), &domain.Merchant{ID: merchantID, AcceptedCardBrands: brands}).Return(nil),
+		)
+
+		m, err := merchant.NewService(store).SetAcceptedCardBrands(context.Background(), merchantID.String(), brands)
+		require.NoError(t, err)
+		assert.Equal(t, brands, m.AcceptedCardBrands)
+	})
+	t.Run("should return error given the merchant does not exist", func(t *testing.T) {
+		t.Parallel()
+		store := mocks.NewMockStore(gomock.NewController(t))
+		execInTransaction(store)
+		store.EXPECT().GetMerchant(gomock.Any(), merchantID.String()).Return(nil, domain.ErrNoMerchant)
+
+		_, err := merchant.NewService(store).SetAcceptedCardBrands(context.Background(), merchantID.String(), brands)
+		assert.Equal(t, domain.ErrNoMerchant, err)
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl       = gomock.NewController(t)
+		store      = mocks.NewMockStore(ctrl)
+		merchantID = uuid.NewV4()
+	)
+	store.EXPECT().GetAPIKeyByHash(gomock.Any(), hash("sk_valid")).Return(&domain.APIKey{MerchantID: merchantID}, nil)
+	store.EXPECT().GetAPIKeyByHash(gomock.Any(), hash("sk_revoked")).Return(nil, domain.ErrNoAPIKey)
+	service := merchant.NewService(store)
+
+	got, err := service.Authenticate(context.Background(), "sk_valid")
+	require.NoError(t, err)
+	assert.Equal(t, merchantID.String(), got)
+
+	_, err = service.Authenticate(context.Background(), "sk_revoked")
+	assert.ErrorIs(t, err, domain.ErrNoAPIKey)
+}
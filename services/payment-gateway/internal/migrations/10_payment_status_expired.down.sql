@@ -0,0 +1,2 @@
+-- enum values cannot be dropped, expired payments are returned to authorized so the value goes unused
+UPDATE payment SET status = 'AUTHORIZED' WHERE status = 'EXPIRED';
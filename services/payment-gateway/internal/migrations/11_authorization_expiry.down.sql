@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS payment_authorized_expires_at_idx;
+
+ALTER TABLE payment DROP COLUMN IF EXISTS expires_at;
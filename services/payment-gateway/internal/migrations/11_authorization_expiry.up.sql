@@ -0,0 +1,3 @@
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS payment_authorized_expires_at_idx ON payment (expires_at) WHERE status = 'AUTHORIZED';
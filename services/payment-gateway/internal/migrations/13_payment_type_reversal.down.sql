@@ -0,0 +1,2 @@
+-- enum values cannot be dropped, reversals are removed so the value goes unused
+DELETE FROM payment_action WHERE payment_type = 'REVERSAL';
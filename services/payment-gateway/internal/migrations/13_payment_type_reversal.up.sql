@@ -0,0 +1,2 @@
+-- ADD VALUE cannot run within a transaction so it is kept to a migration of its own
+ALTER TYPE payment_type ADD VALUE IF NOT EXISTS 'REVERSAL';
@@ -0,0 +1,46 @@
+CREATE TYPE ledger_account as enum ('CUSTOMER','AUTHORIZED','CAPTURED','REFUNDED','MERCHANT_PAYABLE');
+
+CREATE TABLE IF NOT EXISTS ledger_entry
+(
+    id                UUID UNIQUE DEFAULT uuid_generate_v4(),
+    payment_id        UUID           NOT NULL references payment (id),
+    payment_action_id UUID references payment_action (id),
+    debit_account     ledger_account NOT NULL,
+    credit_account    ledger_account NOT NULL,
+    amount            BIGINT         NOT NULL CHECK (amount > 0),
+    currency          VARCHAR(3)     NOT NULL,
+    created_at        timestamptz default clock_timestamp(),
+    CHECK (debit_account <> credit_account)
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entry_payment_id_idx ON ledger_entry (payment_id);
+
+-- payments made before the ledger existed are posted from their successful actions, actions made before response
+-- categories were recorded are approved by the codes the catalogue approves
+INSERT INTO ledger_entry (payment_id, payment_action_id, debit_account, credit_account, amount, currency)
+SELECT a.payment_id, a.id, e.debit_account::ledger_account, e.credit_account::ledger_account, a.amount, p.currency
+FROM payment_action a
+         JOIN payment p ON p.id = a.payment_id
+         JOIN (VALUES ('AUTHORIZATION', 'AUTHORIZED', 'CUSTOMER'),
+                      ('INCREMENTAL_AUTHORIZATION', 'AUTHORIZED', 'CUSTOMER'),
+                      ('VOID', 'CUSTOMER', 'AUTHORIZED'),
+                      ('REVERSAL', 'CUSTOMER', 'AUTHORIZED'),
+                      ('CAPTURE', 'CUSTOMER', 'AUTHORIZED'),
+                      ('CAPTURE', 'CAPTURED', 'MERCHANT_PAYABLE'),
+                      ('REFUND', 'MERCHANT_PAYABLE', 'REFUNDED')) AS e (payment_type, debit_account, credit_account)
+              ON e.payment_type = a.payment_type::text
+WHERE (a.response_category = 'APPROVED' OR (a.response_category IS NULL AND a.response_code IN ('00', '08', '11')))
+  AND a.amount > 0
+ORDER BY a.created_at;
+
+-- payments in domain.HoldReleasedStatuses had the rest of their hold released by the scheme on expiry, as the ledger
+-- checker expects
+INSERT INTO ledger_entry (payment_id, debit_account, credit_account, amount, currency)
+SELECT l.payment_id, 'CUSTOMER', 'AUTHORIZED',
+       SUM(CASE WHEN l.debit_account = 'AUTHORIZED' THEN l.amount ELSE -l.amount END), p.currency
+FROM ledger_entry l
+         JOIN payment p ON p.id = l.payment_id
+WHERE p.status IN ('EXPIRED', 'CAPTURED')
+  AND 'AUTHORIZED' IN (l.debit_account, l.credit_account)
+GROUP BY l.payment_id, p.currency
+HAVING SUM(CASE WHEN l.debit_account = 'AUTHORIZED' THEN l.amount ELSE -l.amount END) > 0;
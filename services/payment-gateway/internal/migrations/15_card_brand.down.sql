@@ -0,0 +1,3 @@
+ALTER TABLE merchant DROP COLUMN IF EXISTS accepted_card_brands;
+
+ALTER TABLE payment DROP COLUMN IF EXISTS card_brand;
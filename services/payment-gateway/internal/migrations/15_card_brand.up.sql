@@ -0,0 +1,5 @@
+-- payments made before brands were stored have theirs detected from their BIN when read.
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS card_brand VARCHAR(16) NOT NULL DEFAULT '';
+
+-- merchants accept every brand until they configure otherwise.
+ALTER TABLE merchant ADD COLUMN IF NOT EXISTS accepted_card_brands TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS payment_held_expires_at_idx;
+
+CREATE INDEX IF NOT EXISTS payment_authorized_expires_at_idx ON payment (expires_at) WHERE status = 'AUTHORIZED';
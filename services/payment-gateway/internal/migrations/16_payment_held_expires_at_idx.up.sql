@@ -0,0 +1,5 @@
+-- the expiry sweeper lists partially captured payments as well as authorized ones.
+DROP INDEX IF EXISTS payment_authorized_expires_at_idx;
+
+CREATE INDEX IF NOT EXISTS payment_held_expires_at_idx ON payment (expires_at)
+    WHERE status IN ('AUTHORIZED', 'PARTIALLY_CAPTURED');
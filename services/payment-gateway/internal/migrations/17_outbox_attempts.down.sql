@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS outbox_unpublished_payment_id_idx;
+DROP INDEX IF EXISTS outbox_unpublished_idx;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS last_error;
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS attempts;
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (created_at) WHERE published_at IS NULL;
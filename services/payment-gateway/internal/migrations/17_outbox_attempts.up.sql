@@ -0,0 +1,12 @@
+-- an event that fails to publish is retried with backoff until it runs out of attempts and is failed, so that it no
+-- longer holds up the events behind it.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS attempts int NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS last_error TEXT;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS failed_at timestamptz;
+
+DROP INDEX IF EXISTS outbox_unpublished_idx;
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (created_at) WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_unpublished_payment_id_idx ON outbox (payment_id, created_at)
+    WHERE published_at IS NULL AND failed_at IS NULL;
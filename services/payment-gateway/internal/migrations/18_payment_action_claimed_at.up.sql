@@ -0,0 +1,3 @@
+-- an authorization made asynchronously is claimed by a worker before it is sent to the issuer, outside of the
+-- transaction locking it, so that no other worker sends it again.
+ALTER TABLE payment_action ADD COLUMN IF NOT EXISTS claimed_at timestamptz;
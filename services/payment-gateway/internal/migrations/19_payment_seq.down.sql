@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS payment_currency_seq_idx;
+DROP INDEX IF EXISTS payment_status_seq_idx;
+DROP INDEX IF EXISTS payment_seq_idx;
+
+CREATE INDEX IF NOT EXISTS payment_status_created_at_id_idx ON payment (status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS payment_currency_created_at_id_idx ON payment (currency, created_at DESC, id DESC);
+
+ALTER TABLE payment DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,20 @@
+-- payments are paged by the order they were inserted in rather than created_at, which is when the transaction creating
+-- them started. Existing payments are numbered in the order they were listed by before.
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS seq bigint;
+
+UPDATE payment SET seq = ordered.seq
+FROM (SELECT id, row_number() OVER (ORDER BY created_at, id) AS seq FROM payment) ordered
+WHERE payment.id = ordered.id;
+
+CREATE SEQUENCE IF NOT EXISTS payment_seq_seq OWNED BY payment.seq;
+SELECT setval('payment_seq_seq', COALESCE((SELECT max(seq) FROM payment), 0) + 1, false);
+
+ALTER TABLE payment ALTER COLUMN seq SET DEFAULT nextval('payment_seq_seq');
+ALTER TABLE payment ALTER COLUMN seq SET NOT NULL;
+
+DROP INDEX IF EXISTS payment_status_created_at_id_idx;
+DROP INDEX IF EXISTS payment_currency_created_at_id_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS payment_seq_idx ON payment (seq DESC);
+CREATE INDEX IF NOT EXISTS payment_status_seq_idx ON payment (status, seq DESC);
+CREATE INDEX IF NOT EXISTS payment_currency_seq_idx ON payment (currency, seq DESC);
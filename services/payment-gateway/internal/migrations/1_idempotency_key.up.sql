@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+    key             VARCHAR(255) PRIMARY KEY,
+    request_path    VARCHAR(255) NOT NULL,
+    fingerprint     VARCHAR(64)  NOT NULL,
+    response_status int,
+    response_body   bytea,
+    created_at      timestamptz default now(),
+    completed_at    timestamptz
+);
@@ -0,0 +1,5 @@
+DROP INDEX payment_card_last_four_idx;
+DROP INDEX payment_amount_idx;
+DROP INDEX payment_currency_created_at_id_idx;
+DROP INDEX payment_status_created_at_id_idx;
+DROP INDEX payment_created_at_id_idx;
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS payment_created_at_id_idx ON payment (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS payment_status_created_at_id_idx ON payment (status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS payment_currency_created_at_id_idx ON payment (currency, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS payment_amount_idx ON payment (amount);
+CREATE INDEX IF NOT EXISTS payment_card_last_four_idx ON payment (right(card_number, 4));
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           UUID UNIQUE DEFAULT uuid_generate_v4(),
+    payment_id   UUID         NOT NULL references payment (id),
+    event_type   VARCHAR(255) NOT NULL,
+    payload      bytea        NOT NULL,
+    created_at   timestamptz default clock_timestamp(),
+    published_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (created_at) WHERE published_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE payment_action DROP COLUMN IF EXISTS response_category;
+DROP TYPE response_category;
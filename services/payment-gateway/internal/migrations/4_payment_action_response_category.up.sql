@@ -0,0 +1,3 @@
+CREATE TYPE response_category as enum ('APPROVED','SOFT_DECLINE','HARD_DECLINE','REFERRAL','FRAUD');
+
+ALTER TABLE payment_action ADD COLUMN IF NOT EXISTS response_category response_category;
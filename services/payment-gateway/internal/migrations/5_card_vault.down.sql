@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS payment_card_last_four_idx;
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS card_number VARCHAR(16) NOT NULL DEFAULT '';
+UPDATE payment SET card_number = card_bin || card_last_four;
+ALTER TABLE payment DROP COLUMN IF EXISTS card_last_four;
+ALTER TABLE payment DROP COLUMN IF EXISTS card_bin;
+ALTER TABLE payment DROP COLUMN IF EXISTS card_token;
+CREATE INDEX IF NOT EXISTS payment_card_last_four_idx ON payment (right(card_number, 4));
+
+DROP TABLE IF EXISTS card_token;
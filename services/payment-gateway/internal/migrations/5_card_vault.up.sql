@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS card_token
+(
+    token              VARCHAR(64) PRIMARY KEY,
+    encrypted_pan      bytea       NOT NULL,
+    encrypted_data_key bytea       NOT NULL,
+    key_id             VARCHAR(64) NOT NULL,
+    bin                VARCHAR(6)  NOT NULL,
+    last_four          VARCHAR(4)  NOT NULL,
+    expiry_month       int         NOT NULL,
+    expiry_year        int         NOT NULL,
+    created_at         timestamptz default now()
+);
+
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS card_token VARCHAR(64) references card_token (token);
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS card_bin VARCHAR(6) NOT NULL DEFAULT '';
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS card_last_four VARCHAR(4) NOT NULL DEFAULT '';
+
+-- payments made before the vault keep only the BIN and last four of their card.
+UPDATE payment SET card_bin = left(card_number, 6), card_last_four = right(card_number, 4);
+
+DROP INDEX IF EXISTS payment_card_last_four_idx;
+ALTER TABLE payment DROP COLUMN IF EXISTS card_number;
+CREATE INDEX IF NOT EXISTS payment_card_last_four_idx ON payment (card_last_four);
@@ -0,0 +1 @@
+ALTER TABLE payment_action DROP COLUMN IF EXISTS acquirer;
@@ -0,0 +1 @@
+ALTER TABLE payment_action ADD COLUMN IF NOT EXISTS acquirer VARCHAR(64);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idempotency_key_merchant_key_idx;
+DELETE FROM idempotency_key WHERE merchant_id IS NOT NULL;
+ALTER TABLE idempotency_key DROP COLUMN IF EXISTS merchant_id;
+ALTER TABLE idempotency_key ADD PRIMARY KEY (key);
+
+ALTER TABLE card_token DROP COLUMN IF EXISTS merchant_id;
+
+DROP INDEX IF EXISTS payment_merchant_id_idx;
+ALTER TABLE payment DROP COLUMN IF EXISTS merchant_id;
+
+DROP TABLE IF EXISTS merchant_api_key;
+DROP TABLE IF EXISTS merchant;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS merchant
+(
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name       VARCHAR(255) NOT NULL,
+    created_at timestamptz default now()
+);
+
+CREATE TABLE IF NOT EXISTS merchant_api_key
+(
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    merchant_id UUID        NOT NULL references merchant (id),
+    prefix      VARCHAR(16) NOT NULL,
+    key_hash    VARCHAR(64) NOT NULL UNIQUE,
+    created_at  timestamptz default now(),
+    revoked_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS merchant_api_key_merchant_id_idx ON merchant_api_key (merchant_id);
+
+-- payments, card tokens and idempotency keys made before merchants existed have no merchant.
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS merchant_id UUID references merchant (id);
+CREATE INDEX IF NOT EXISTS payment_merchant_id_idx ON payment (merchant_id, created_at DESC, id DESC);
+
+ALTER TABLE card_token ADD COLUMN IF NOT EXISTS merchant_id UUID references merchant (id);
+
+-- idempotency keys are unique per merchant rather than globally.
+ALTER TABLE idempotency_key ADD COLUMN IF NOT EXISTS merchant_id UUID references merchant (id);
+ALTER TABLE idempotency_key DROP CONSTRAINT IF EXISTS idempotency_key_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idempotency_key_merchant_key_idx
+    ON idempotency_key (key, COALESCE(merchant_id, '00000000-0000-0000-0000-000000000000'));
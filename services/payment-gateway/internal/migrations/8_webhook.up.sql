@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoint
+(
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    merchant_id UUID          NOT NULL references merchant (id),
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(128)  NOT NULL,
+    created_at  timestamptz default now(),
+    disabled_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS webhook_endpoint_merchant_id_idx ON webhook_endpoint (merchant_id) WHERE disabled_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+    id                   UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    endpoint_id          UUID         NOT NULL references webhook_endpoint (id),
+    event_id             UUID         NOT NULL references outbox (id),
+    payment_id           UUID         NOT NULL references payment (id),
+    event_type           VARCHAR(255) NOT NULL,
+    payload              bytea        NOT NULL,
+    status               VARCHAR(16)  NOT NULL DEFAULT 'PENDING',
+    attempts             int          NOT NULL DEFAULT 0,
+    next_attempt_at      timestamptz  NOT NULL DEFAULT now(),
+    last_response_status int,
+    last_error           TEXT,
+    created_at           timestamptz default clock_timestamp(),
+    delivered_at         timestamptz,
+    UNIQUE (endpoint_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS webhook_delivery_payment_id_idx ON webhook_delivery (payment_id);
@@ -0,0 +1,5 @@
+ALTER TABLE payment_action DROP COLUMN IF EXISTS settlement_amount;
+
+ALTER TABLE payment DROP COLUMN IF EXISTS fx_rate;
+ALTER TABLE payment DROP COLUMN IF EXISTS settlement_amount;
+ALTER TABLE payment DROP COLUMN IF EXISTS settlement_currency;
@@ -0,0 +1,5 @@
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS settlement_currency VARCHAR(3);
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS settlement_amount BIGINT;
+ALTER TABLE payment ADD COLUMN IF NOT EXISTS fx_rate NUMERIC;
+
+ALTER TABLE payment_action ADD COLUMN IF NOT EXISTS settlement_amount BIGINT;
@@ -0,0 +1,116 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	// registers the payment event types so that payloads can be decoded by name
+	_ "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// MemoryPublisher keeps published events in memory. Useful within tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []*domain.OutboxEvent
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, event *domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns the events published so far.
+func (p *MemoryPublisher) Events() []*domain.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*domain.OutboxEvent(nil), p.events...)
+}
+
+// FilePublisher appends published events to a file as JSON lines. It stands in for a message broker.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// FileEvent is the JSON representation of an event written by the FilePublisher.
+type FileEvent struct {
+	ID        string          `json:"id"`
+	PaymentID string          `json:"payment_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{file: file}, nil
+}
+
+func (p *FilePublisher) Publish(_ context.Context, event *domain.OutboxEvent) error {
+	payload, err := DecodePayload(event)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(FileEvent{
+		ID:        event.ID.String(),
+		PaymentID: event.PaymentID.String(),
+		EventType: event.EventType,
+		Payload:   payload,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.file.Write(append(b, '\n'))
+	return err
+}
+
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+// DecodePayload converts the event payload to JSON using its registered type.
+func DecodePayload(event *domain.OutboxEvent) ([]byte, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(event.EventType))
+	if err != nil {
+		return nil, err
+	}
+	msg := messageType.New().Interface()
+	if err = proto.Unmarshal(event.Payload, msg); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(msg)
+}
+
+// MultiPublisher publishes each event to several publishers in turn, stopping at the first failure. The event is
+// republished to every publisher when retried.
+type MultiPublisher []Publisher
+
+func (p MultiPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package outbox_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/outbox"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestMemoryPublisher(t *testing.T) {
+	t.Parallel()
+
+	event := &domain.OutboxEvent{ID: uuid.NewV4()}
+	publisher := outbox.NewMemoryPublisher()
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	assert.Equal(t, []*domain.OutboxEvent{event}, publisher.Events())
+}
+
+func TestFilePublisher(t *testing.T) {
+	t.Parallel()
+
+	paymentID := uuid.NewV4().String()
+	event, err := domain.NewOutboxEvent(paymentID, &paymentsV1.PaymentVoided{
+		Payment: &paymentsV1.Payment{
+			Id:            paymentID,
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED,
+		},
+	})
+	require.NoError(t, err)
+	event.ID = uuid.NewV4()
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	publisher, err := outbox.NewFilePublisher(path)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, publisher.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+		var fileEvent outbox.FileEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &fileEvent))
+		assert.Equal(t, event.ID.String(), fileEvent.ID)
+		assert.Equal(t, paymentID, fileEvent.PaymentID)
+		assert.Equal(t, "shared.payment.v1.PaymentVoided", fileEvent.EventType)
+
+		var voided paymentsV1.PaymentVoided
+		require.NoError(t, protojson.Unmarshal(fileEvent.Payload, &voided))
+		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED, voided.Payment.PaymentStatus)
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestMultiPublisher(t *testing.T) {
+	t.Parallel()
+
+	event := &domain.OutboxEvent{ID: uuid.NewV4()}
+	first, second := outbox.NewMemoryPublisher(), outbox.NewMemoryPublisher()
+	require.NoError(t, outbox.MultiPublisher{first, second}.Publish(context.Background(), event))
+	assert.Equal(t, []*domain.OutboxEvent{event}, first.Events())
+	assert.Equal(t, []*domain.OutboxEvent{event}, second.Events())
+}
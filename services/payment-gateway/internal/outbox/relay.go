@@ -0,0 +1,162 @@
+//go:generate mockgen -source=relay.go -destination=mocks/mocks.go -package=mocks
+
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultBatchSize   = 100
+	DefaultInterval    = time.Second
+	DefaultMaxAttempts = 10
+	// DefaultBackoff is the wait before the first retry of an event, doubling after each attempt up to MaxBackoff.
+	DefaultBackoff = time.Second
+	MaxBackoff     = 5 * time.Minute
+
+	maxErrorLen = 1024
+)
+
+type Store interface {
+	ExecInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	ListUnpublishedOutboxEvents(ctx context.Context, limit uint64) ([]*domain.OutboxEvent, error)
+	MarkOutboxEventPublished(ctx context.Context, id string) error
+	UpdateOutboxEventAttempt(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// Publisher publishes outbox events to where they are consumed, i.e. a message broker.
+// Events are delivered at least once so consumers should handle duplicates by the event ID.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// Relay moves events written to the outbox to a Publisher. An event that fails to publish is retried with exponential
+// backoff until it runs out of attempts and is failed, holding up only the later events of its own payment meanwhile.
+type Relay struct {
+	store       Store
+	publisher   Publisher
+	batchSize   uint64
+	interval    time.Duration
+	maxAttempts int
+	backoff     time.Duration
+}
+
+type Option func(r *Relay)
+
+// WithRetries makes up to maxAttempts attempts at publishing each event, waiting backoff before the first retry.
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(r *Relay) {
+		r.maxAttempts = maxAttempts
+		r.backoff = backoff
+	}
+}
+
+func NewRelay(store Store, publisher Publisher, batchSize uint64, interval time.Duration, opts ...Option) Relay {
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	r := Relay{
+		store:       store,
+		publisher:   publisher,
+		batchSize:   batchSize,
+		interval:    interval,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+// Run relays events every interval until the context is cancelled.
+func (r Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// keep relaying whilst there is a backlog of events
+			for {
+				n, err := r.RelayBatch(ctx)
+				if err != nil {
+					log.WithError(err).Error("failed to relay outbox events")
+					break
+				}
+				if uint64(n) < r.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// RelayBatch publishes the oldest batch of due events in order, returning how many were in the batch. An event that
+// fails to publish has the attempt recorded against it and the rest of its payment's events in the batch are left for
+// later, so that events for a payment are never published out of order, whilst other payments' events carry on.
+func (r Relay) RelayBatch(ctx context.Context) (int, error) {
+	var listed int
+	err := r.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		events, err := r.store.ListUnpublishedOutboxEvents(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+		listed = len(events)
+		held := make(map[string]bool)
+		for _, event := range events {
+			if held[event.PaymentID.String()] {
+				continue
+			}
+			if err = r.publisher.Publish(ctx, event); err != nil {
+				held[event.PaymentID.String()] = true
+				if err = r.recordFailure(ctx, event, err); err != nil {
+					return err
+				}
+				continue
+			}
+			if err = r.store.MarkOutboxEventPublished(ctx, event.ID.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return listed, nil
+}
+
+// recordFailure records the failed attempt to publish the event, failing it once it has run out of attempts.
+func (r Relay) recordFailure(ctx context.Context, event *domain.OutboxEvent, publishErr error) error {
+	now := time.Now()
+	message := publishErr.Error()
+	if len(message) > maxErrorLen {
+		message = message[:maxErrorLen]
+	}
+	event.Attempts++
+	event.LastError = sql.NullString{String: message, Valid: true}
+	logger := log.WithError(publishErr).WithFields(log.Fields{"outbox.id": event.ID, "attempts": event.Attempts})
+	if event.Attempts >= r.maxAttempts {
+		event.FailedAt = sql.NullTime{Time: now, Valid: true}
+		logger.Error("outbox event failed to publish and ran out of attempts")
+	} else {
+		backoff := r.backoff << (event.Attempts - 1)
+		if backoff > MaxBackoff || backoff <= 0 {
+			backoff = MaxBackoff
+		}
+		event.NextAttemptAt = now.Add(backoff)
+		logger.Warn("failed to publish outbox event")
+	}
+	return r.store.UpdateOutboxEventAttempt(ctx, event)
+}
@@ -0,0 +1,152 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/outbox"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/outbox/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelay_RelayBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		paymentID = uuid.NewV4()
+		newEvent  = func(paymentID uuid.UUID, eventType string) *domain.OutboxEvent {
+			return &domain.OutboxEvent{ID: uuid.NewV4(), PaymentID: paymentID, EventType: eventType}
+		}
+	)
+
+	for _, tc := range []struct {
+		description string
+		fn          func(store *mocks.MockStore, publisher *mocks.MockPublisher)
+		expListed   int
+		err         error
+	}{
+		{
+			description: "should return error given unable to list events",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).Return(nil, errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should retry an event that failed to publish later whilst publishing other payments' events",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				var (
+					first  = newEvent(paymentID, "shared.payment.v1.PaymentCreated")
+					second = newEvent(paymentID, "shared.payment.v1.PaymentAuthorized")
+					other  = newEvent(uuid.NewV4(), "shared.payment.v1.PaymentCreated")
+				)
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).
+					Return([]*domain.OutboxEvent{first, second, other}, nil)
+				publisher.EXPECT().Publish(gomock.Any(), first).Return(errors.New("unknown event type"))
+				store.EXPECT().UpdateOutboxEventAttempt(gomock.Any(), first).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, 1, event.Attempts)
+						assert.Equal(t, "unknown event type", event.LastError.String)
+						assert.True(t, event.NextAttemptAt.After(time.Now()))
+						assert.False(t, event.FailedAt.Valid)
+						return nil
+					})
+				// the payment's later event waits for the one that failed
+				publisher.EXPECT().Publish(gomock.Any(), other).Return(nil)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), other.ID.String()).Return(nil)
+			},
+			expListed: 3,
+		},
+		{
+			description: "should fail an event that runs out of attempts",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				event := newEvent(paymentID, "shared.payment.v1.PaymentCreated")
+				event.Attempts = 2
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).Return([]*domain.OutboxEvent{event}, nil)
+				publisher.EXPECT().Publish(gomock.Any(), event).Return(errors.New("unknown event type"))
+				store.EXPECT().UpdateOutboxEventAttempt(gomock.Any(), event).
+					DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent) error {
+						assert.Equal(t, 3, event.Attempts)
+						assert.True(t, event.FailedAt.Valid)
+						return nil
+					})
+			},
+			expListed: 1,
+		},
+		{
+			description: "should return error given unable to record a failed attempt",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				event := newEvent(paymentID, "shared.payment.v1.PaymentCreated")
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).Return([]*domain.OutboxEvent{event}, nil)
+				publisher.EXPECT().Publish(gomock.Any(), event).Return(errors.New("unknown event type"))
+				store.EXPECT().UpdateOutboxEventAttempt(gomock.Any(), event).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should return error given unable to mark event published",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				first := newEvent(paymentID, "shared.payment.v1.PaymentCreated")
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).
+					Return([]*domain.OutboxEvent{first, newEvent(paymentID, "shared.payment.v1.PaymentAuthorized")}, nil)
+				publisher.EXPECT().Publish(gomock.Any(), first).Return(nil)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), first.ID.String()).Return(errors.New("error"))
+			},
+			err: errors.New("error"),
+		},
+		{
+			description: "should publish events in order",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				var (
+					first  = newEvent(paymentID, "shared.payment.v1.PaymentCreated")
+					second = newEvent(paymentID, "shared.payment.v1.PaymentAuthorized")
+				)
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).Return([]*domain.OutboxEvent{first, second}, nil)
+				gomock.InOrder(
+					publisher.EXPECT().Publish(gomock.Any(), first).Return(nil),
+					store.EXPECT().MarkOutboxEventPublished(gomock.Any(), first.ID.String()).Return(nil),
+					publisher.EXPECT().Publish(gomock.Any(), second).Return(nil),
+					store.EXPECT().MarkOutboxEventPublished(gomock.Any(), second.ID.String()).Return(nil),
+				)
+			},
+			expListed: 2,
+		},
+		{
+			description: "should publish nothing given no events",
+			fn: func(store *mocks.MockStore, publisher *mocks.MockPublisher) {
+				store.EXPECT().ListUnpublishedOutboxEvents(gomock.Any(), uint64(10)).Return([]*domain.OutboxEvent{}, nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl          = gomock.NewController(t)
+				mockStore     = mocks.NewMockStore(ctrl)
+				mockPublisher = mocks.NewMockPublisher(ctrl)
+			)
+			mockStore.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+			tc.fn(mockStore, mockPublisher)
+
+			relay := outbox.NewRelay(mockStore, mockPublisher, 10, time.Second, outbox.WithRetries(3, time.Second))
+			listed, err := relay.RelayBatch(context.Background())
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Equal(t, tc.err.Error(), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expListed, listed)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package resilience
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// breakers exposes the state of every circuit breaker by acquirer name at /debug/vars.
+var breakers = expvar.NewMap("issuer_circuit_breakers")
+
+type State int
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = iota
+	// StateOpen rejects every call until the open duration has passed.
+	StateOpen
+	// StateHalfOpen lets a single probe through, closing the breaker if it succeeds and re-opening it otherwise.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	}
+	return "unknown"
+}
+
+// BreakerMetrics is a snapshot of a breaker published to expvar.
+type BreakerMetrics struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	// Opened is the number of times the breaker has opened.
+	Opened uint64 `json:"opened"`
+	// Rejected is the number of calls rejected whilst open.
+	Rejected uint64 `json:"rejected"`
+}
+
+// Breaker opens after a number of consecutive failures so that calls to an unhealthy issuer fail fast.
+type Breaker struct {
+	mu           sync.Mutex
+	threshold    int
+	openDuration time.Duration
+	now          func() time.Time
+
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+	opened   uint64
+	rejected uint64
+}
+
+// NewBreaker returns a closed breaker published to expvar under name.
+func NewBreaker(name string, threshold int, openDuration time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	if openDuration == 0 {
+		openDuration = DefaultOpenDuration
+	}
+	b := &Breaker{threshold: threshold, openDuration: openDuration, now: time.Now}
+	breakers.Set(name, expvar.Func(func() interface{} { return b.Metrics() }))
+	return b
+}
+
+// Allow reports whether a call may be made, every allowed call must be followed by Success, Failure or Release.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.openDuration {
+		b.state = StateHalfOpen
+	}
+	switch b.state {
+	case StateOpen:
+		b.rejected++
+		return false
+	case StateHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Success closes the breaker as the issuer answered.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	b.state = StateClosed
+	b.failures = 0
+}
+
+// Release ends an allowed call without an outcome, such as one the caller gave up on.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// Failure opens the breaker once the threshold of consecutive failures is reached or a half open probe fails.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		if b.state != StateOpen {
+			b.opened++
+		}
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) Metrics() BreakerMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BreakerMetrics{
+		State:               b.state.String(),
+		ConsecutiveFailures: b.failures,
+		Opened:              b.opened,
+		Rejected:            b.rejected,
+	}
+}
@@ -0,0 +1,68 @@
+package resilience_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Parallel()
+	t.Run("should open after consecutive failures", func(t *testing.T) {
+		breaker := resilience.NewBreaker(t.Name(), 2, time.Minute)
+
+		require.True(t, breaker.Allow())
+		breaker.Failure()
+		require.True(t, breaker.Allow())
+		breaker.Success()
+		require.True(t, breaker.Allow())
+		breaker.Failure()
+		assert.Equal(t, resilience.StateClosed, breaker.State())
+
+		require.True(t, breaker.Allow())
+		breaker.Failure()
+		assert.Equal(t, resilience.StateOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+		assert.Equal(t, resilience.BreakerMetrics{State: "open", ConsecutiveFailures: 2, Opened: 1, Rejected: 1}, breaker.Metrics())
+	})
+	t.Run("should allow a single probe once the open duration has passed", func(t *testing.T) {
+		breaker := resilience.NewBreaker(t.Name(), 1, 10*time.Millisecond)
+		require.True(t, breaker.Allow())
+		breaker.Failure()
+		require.False(t, breaker.Allow())
+
+		time.Sleep(20 * time.Millisecond)
+		require.True(t, breaker.Allow())
+		assert.Equal(t, resilience.StateHalfOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+
+		breaker.Success()
+		assert.Equal(t, resilience.StateClosed, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+	t.Run("should re-open given the probe fails", func(t *testing.T) {
+		breaker := resilience.NewBreaker(t.Name(), 3, 10*time.Millisecond)
+		for i := 0; i < 3; i++ {
+			require.True(t, breaker.Allow())
+			breaker.Failure()
+		}
+		time.Sleep(20 * time.Millisecond)
+		require.True(t, breaker.Allow())
+		breaker.Failure()
+		assert.Equal(t, resilience.StateOpen, breaker.State())
+		assert.Equal(t, uint64(2), breaker.Metrics().Opened)
+	})
+	t.Run("should allow another probe given the probe is released", func(t *testing.T) {
+		breaker := resilience.NewBreaker(t.Name(), 1, 10*time.Millisecond)
+		require.True(t, breaker.Allow())
+		breaker.Failure()
+		time.Sleep(20 * time.Millisecond)
+		require.True(t, breaker.Allow())
+		breaker.Release()
+		assert.Equal(t, resilience.StateHalfOpen, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+}
@@ -0,0 +1,149 @@
+//go:generate mockgen -source=gateway.go -destination=mocks/mocks.go -package=mocks
+package resilience
+
+import (
+	"context"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultCreateTimeout    = 10 * time.Second
+	DefaultStatusTimeout    = 5 * time.Second
+	DefaultStatusAttempts   = 3
+	DefaultRetryBackoff     = 100 * time.Millisecond
+	DefaultFailureThreshold = 5
+	DefaultOpenDuration     = 30 * time.Second
+)
+
+type IssuerGateway interface {
+	CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error)
+	GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error)
+}
+
+// Gateway is an IssuerGateway that isolates the service from a slow or failing issuer. Each call is bounded by a
+// timeout and rejected with domain.ErrIssuerUnavailable whilst the issuer's circuit breaker is open.
+//
+// Status inquiries are retried as they are read only. Creating a request is never retried, one that timed out may
+// have reached the issuer and is left for recovery to resolve with a status inquiry instead.
+type Gateway struct {
+	name           string
+	next           IssuerGateway
+	breaker        *Breaker
+	createTimeout  time.Duration
+	statusTimeout  time.Duration
+	statusAttempts int
+	retryBackoff   time.Duration
+}
+
+type Option func(g *Gateway)
+
+// WithTimeouts bounds how long creating a request and a status inquiry may take.
+func WithTimeouts(create, status time.Duration) Option {
+	return func(g *Gateway) {
+		g.createTimeout = create
+		g.statusTimeout = status
+	}
+}
+
+// WithStatusRetries makes a status inquiry up to attempts times, waiting backoff doubling between each.
+func WithStatusRetries(attempts int, backoff time.Duration) Option {
+	return func(g *Gateway) {
+		g.statusAttempts = attempts
+		g.retryBackoff = backoff
+	}
+}
+
+// WithBreaker opens the circuit breaker after threshold consecutive failures for openDuration.
+func WithBreaker(threshold int, openDuration time.Duration) Option {
+	return func(g *Gateway) {
+		g.breaker = NewBreaker(g.name, threshold, openDuration)
+	}
+}
+
+// NewGateway wraps the issuer named name, the name identifies its circuit breaker in metrics.
+func NewGateway(name string, next IssuerGateway, opts ...Option) *Gateway {
+	g := &Gateway{
+		name:           name,
+		next:           next,
+		createTimeout:  DefaultCreateTimeout,
+		statusTimeout:  DefaultStatusTimeout,
+		statusAttempts: DefaultStatusAttempts,
+		retryBackoff:   DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.breaker == nil {
+		g.breaker = NewBreaker(name, DefaultFailureThreshold, DefaultOpenDuration)
+	}
+	if g.statusAttempts <= 0 {
+		g.statusAttempts = 1
+	}
+	return g
+}
+
+func (g *Gateway) Breaker() *Breaker {
+	return g.breaker
+}
+
+func (g *Gateway) CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	return g.call(ctx, g.createTimeout, func(ctx context.Context) (domain.IssuerResponse, error) {
+		return g.next.CreateIssuerRequest(ctx, issuerRequest)
+	})
+}
+
+func (g *Gateway) GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	backoff := g.retryBackoff
+	for attempt := 1; ; attempt++ {
+		response, err := g.call(ctx, g.statusTimeout, func(ctx context.Context) (domain.IssuerResponse, error) {
+			return g.next.GetIssuerRequestStatus(ctx, issuerRequest)
+		})
+		if err == nil || !g.retryable(ctx, err) || attempt >= g.statusAttempts {
+			return response, err
+		}
+		select {
+		case <-ctx.Done():
+			return domain.IssuerResponse{}, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// call makes a single call to the issuer through the circuit breaker.
+func (g *Gateway) call(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (domain.IssuerResponse, error)) (domain.IssuerResponse, error) {
+	if !g.breaker.Allow() {
+		return domain.IssuerResponse{}, errors.Wrap(domain.ErrCircuitOpen, g.name)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response, err := fn(callCtx)
+	switch {
+	case err == nil, errors.Is(err, domain.ErrIssuerRequestNotFound):
+		// the issuer answered
+		g.breaker.Success()
+	case ctx.Err() != nil:
+		// the caller gave up, which says nothing of the issuer's health
+		g.breaker.Release()
+		return response, err
+	default:
+		g.breaker.Failure()
+	}
+	if err != nil && callCtx.Err() == context.DeadlineExceeded {
+		return response, errors.Wrapf(domain.ErrIssuerUnavailable, "%s timed out after %s", g.name, timeout)
+	}
+	return response, err
+}
+
+// retryable reports whether a failed status inquiry is worth repeating.
+func (g *Gateway) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, domain.ErrIssuerRequestNotFound) {
+		return false
+	}
+	// once open the breaker rejects every attempt until its open duration passes
+	return g.breaker.State() != StateOpen
+}
@@ -0,0 +1,162 @@
+package resilience_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/resilience"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/resilience/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGateway_CreateIssuerRequest(t *testing.T) {
+	t.Parallel()
+	request := domain.IssuerRequest{Reference: "action-id"}
+
+	for _, tc := range []struct {
+		description string
+		fn          func(issuer *mocks.MockIssuerGateway)
+		expResponse domain.IssuerResponse
+		err         error
+		expState    resilience.State
+	}{
+		{
+			description: "should return the issuer's response",
+			fn: func(issuer *mocks.MockIssuerGateway) {
+				issuer.EXPECT().CreateIssuerRequest(gomock.Any(), request).Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+			},
+			expResponse: domain.IssuerResponse{AuthCode: "00"},
+			expState:    resilience.StateClosed,
+		},
+		{
+			description: "should not retry given the issuer fails",
+			fn: func(issuer *mocks.MockIssuerGateway) {
+				issuer.EXPECT().CreateIssuerRequest(gomock.Any(), request).Return(domain.IssuerResponse{}, errors.New("connection reset"))
+			},
+			err:      errors.New("connection reset"),
+			expState: resilience.StateOpen,
+		},
+		{
+			description: "should return unavailable given the issuer times out",
+			fn: func(issuer *mocks.MockIssuerGateway) {
+				issuer.EXPECT().CreateIssuerRequest(gomock.Any(), request).
+					DoAndReturn(func(ctx context.Context, _ domain.IssuerRequest) (domain.IssuerResponse, error) {
+						<-ctx.Done()
+						return domain.IssuerResponse{}, ctx.Err()
+					})
+			},
+			err:      domain.ErrIssuerUnavailable,
+			expState: resilience.StateOpen,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			issuer := mocks.NewMockIssuerGateway(gomock.NewController(t))
+			tc.fn(issuer)
+			g := resilience.NewGateway(t.Name(), issuer,
+				resilience.WithTimeouts(10*time.Millisecond, 10*time.Millisecond),
+				resilience.WithBreaker(1, time.Minute))
+
+			response, err := g.CreateIssuerRequest(context.Background(), request)
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expResponse, response)
+			}
+			assert.Equal(t, tc.expState, g.Breaker().State())
+		})
+	}
+}
+
+func TestGateway_CreateIssuerRequest_BreakerOpen(t *testing.T) {
+	t.Parallel()
+	issuer := mocks.NewMockIssuerGateway(gomock.NewController(t))
+	g := resilience.NewGateway(t.Name(), issuer, resilience.WithBreaker(1, time.Minute))
+
+	issuer.EXPECT().CreateIssuerRequest(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, errors.New("connection reset"))
+	_, err := g.CreateIssuerRequest(context.Background(), domain.IssuerRequest{})
+	require.Error(t, err)
+
+	// the issuer is not called whilst the breaker is open
+	_, err = g.CreateIssuerRequest(context.Background(), domain.IssuerRequest{})
+	assert.ErrorIs(t, err, domain.ErrIssuerUnavailable)
+	assert.ErrorIs(t, err, domain.ErrCircuitOpen)
+	_, err = g.GetIssuerRequestStatus(context.Background(), domain.IssuerRequest{})
+	assert.ErrorIs(t, err, domain.ErrIssuerUnavailable)
+}
+
+func TestGateway_GetIssuerRequestStatus(t *testing.T) {
+	t.Parallel()
+	request := domain.IssuerRequest{Reference: "action-id"}
+
+	for _, tc := range []struct {
+		description string
+		fn          func(issuer *mocks.MockIssuerGateway)
+		expResponse domain.IssuerResponse
+		err         error
+	}{
+		{
+			description: "should retry given the issuer fails",
+			fn: func(issuer *mocks.MockIssuerGateway) {
+				gomock.InOrder(
+					issuer.EXPECT().GetIssuerRequestStatus(gomock.Any(), request).Return(domain.IssuerResponse{}, errors.New("connection reset")),
+					issuer.EXPECT().GetIssuerRequestStatus(gomock.Any(), request).Return(domain.IssuerResponse{AuthCode: "00"}, nil),
+				)
+			},
+			expResponse: domain.IssuerResponse{AuthCode: "00"},
+		},
+		{
+			description: "should return the last error given every attempt fails",
+			fn: func(issuer *mocks.MockIssuerGateway) {
+				issuer.EXPECT().GetIssuerRequestStatus(gomock.Any(), request).Return(domain.IssuerResponse{}, errors.New("connection reset")).Times(3)
+			},
+			err: errors.New("connection reset"),
+		},
+		{
+			description: "should not retry given the issuer has no record of the request",
+			fn: func(issuer *mocks.MockIssuerGateway) {
+				issuer.EXPECT().GetIssuerRequestStatus(gomock.Any(), request).Return(domain.IssuerResponse{}, domain.ErrIssuerRequestNotFound)
+			},
+			err: domain.ErrIssuerRequestNotFound,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			issuer := mocks.NewMockIssuerGateway(gomock.NewController(t))
+			tc.fn(issuer)
+			g := resilience.NewGateway(t.Name(), issuer, resilience.WithStatusRetries(3, time.Millisecond))
+
+			response, err := g.GetIssuerRequestStatus(context.Background(), request)
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expResponse, response)
+			assert.Equal(t, resilience.StateClosed, g.Breaker().State())
+		})
+	}
+}
+
+func TestGateway_GetIssuerRequestStatus_StopsRetryingOnceOpen(t *testing.T) {
+	t.Parallel()
+	issuer := mocks.NewMockIssuerGateway(gomock.NewController(t))
+	g := resilience.NewGateway(t.Name(), issuer,
+		resilience.WithStatusRetries(5, time.Millisecond),
+		resilience.WithBreaker(2, time.Minute))
+
+	issuer.EXPECT().GetIssuerRequestStatus(gomock.Any(), gomock.Any()).Return(domain.IssuerResponse{}, errors.New("connection reset")).Times(2)
+	_, err := g.GetIssuerRequestStatus(context.Background(), domain.IssuerRequest{})
+	require.Error(t, err)
+	assert.Equal(t, resilience.StateOpen, g.Breaker().State())
+}
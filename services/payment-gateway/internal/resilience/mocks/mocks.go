@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gateway.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+)
+
+// MockIssuerGateway is a mock of IssuerGateway interface.
+type MockIssuerGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockIssuerGatewayMockRecorder
+}
+
+// MockIssuerGatewayMockRecorder is the mock recorder for MockIssuerGateway.
+type MockIssuerGatewayMockRecorder struct {
+	mock *MockIssuerGateway
+}
+
+// NewMockIssuerGateway creates a new mock instance.
+func NewMockIssuerGateway(ctrl *gomock.Controller) *MockIssuerGateway {
+	mock := &MockIssuerGateway{ctrl: ctrl}
+	mock.recorder = &MockIssuerGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIssuerGateway) EXPECT() *MockIssuerGatewayMockRecorder {
+	return m.recorder
+}
+
+// CreateIssuerRequest mocks base method.
+func (m *MockIssuerGateway) CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIssuerRequest", ctx, issuerRequest)
+	ret0, _ := ret[0].(domain.IssuerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIssuerRequest indicates an expected call of CreateIssuerRequest.
+func (mr *MockIssuerGatewayMockRecorder) CreateIssuerRequest(ctx, issuerRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssuerRequest", reflect.TypeOf((*MockIssuerGateway)(nil).CreateIssuerRequest), ctx, issuerRequest)
+}
+
+// GetIssuerRequestStatus mocks base method.
+func (m *MockIssuerGateway) GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssuerRequestStatus", ctx, issuerRequest)
+	ret0, _ := ret[0].(domain.IssuerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuerRequestStatus indicates an expected call of GetIssuerRequestStatus.
+func (mr *MockIssuerGatewayMockRecorder) GetIssuerRequestStatus(ctx, issuerRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuerRequestStatus", reflect.TypeOf((*MockIssuerGateway)(nil).GetIssuerRequestStatus), ctx, issuerRequest)
+}
@@ -0,0 +1,140 @@
+//go:generate mockgen -source=router.go -destination=mocks/mocks.go -package=mocks
+package routing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+var ErrUnknownAcquirer = errors.New("unknown acquirer")
+
+type IssuerGateway interface {
+	CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error)
+	GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error)
+}
+
+// Config configures the acquirers requests are routed between, it is loaded from config.yaml.
+type Config struct {
+	Acquirers []string `yaml:"acquirers"`
+	// DefaultAcquirer handles requests that match no rule.
+	DefaultAcquirer string `yaml:"default_acquirer"`
+	Rules           []Rule `yaml:"rules"`
+}
+
+// Rule routes requests to the acquirer when they match all of its conditions, empty conditions match every request.
+type Rule struct {
+	Acquirer    string   `yaml:"acquirer"`
+	BINPrefixes []string `yaml:"bin_prefixes"`
+	Currencies  []string `yaml:"currencies"`
+	// MinAmount and MaxAmount are inclusive and in minor units.
+	MinAmount uint64 `yaml:"min_amount"`
+	MaxAmount uint64 `yaml:"max_amount"`
+}
+
+func (r Rule) matches(request domain.IssuerRequest) bool {
+	if len(r.BINPrefixes) != 0 {
+		card := request.PaymentMethod.Card
+		number := strings.ReplaceAll(card.GetCardNumber(), " ", "")
+		if number == "" {
+			number = card.GetBin()
+		}
+		matched := false
+		for _, prefix := range r.BINPrefixes {
+			if strings.HasPrefix(number, prefix) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.Currencies) != 0 {
+		matched := false
+		for _, currency := range r.Currencies {
+			if strings.EqualFold(currency, request.Amount.GetCurrency()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	amount := request.Amount.GetMinorUnits()
+	if r.MinAmount != 0 && amount < r.MinAmount {
+		return false
+	}
+	if r.MaxAmount != 0 && amount > r.MaxAmount {
+		return false
+	}
+	return true
+}
+
+// Router is an IssuerGateway that sends each request to one of several acquirers. New requests are routed by the
+// first rule they match, requests pinned to an acquirer are always sent to it.
+type Router struct {
+	acquirers       map[string]IssuerGateway
+	defaultAcquirer string
+	rules           []Rule
+}
+
+// NewRouter returns an error if the default acquirer or an acquirer of a rule is not one of the acquirers.
+func NewRouter(acquirers map[string]IssuerGateway, defaultAcquirer string, rules ...Rule) (Router, error) {
+	if _, ok := acquirers[defaultAcquirer]; !ok {
+		return Router{}, errors.Wrapf(ErrUnknownAcquirer, "default acquirer %q", defaultAcquirer)
+	}
+	for _, rule := range rules {
+		if _, ok := acquirers[rule.Acquirer]; !ok {
+			return Router{}, errors.Wrapf(ErrUnknownAcquirer, "rule acquirer %q", rule.Acquirer)
+		}
+	}
+	return Router{acquirers: acquirers, defaultAcquirer: defaultAcquirer, rules: rules}, nil
+}
+
+// Route returns the acquirer the request is sent to.
+func (r Router) Route(request domain.IssuerRequest) (string, error) {
+	if request.Acquirer != "" {
+		if _, ok := r.acquirers[request.Acquirer]; !ok {
+			return "", errors.Wrapf(ErrUnknownAcquirer, "pinned acquirer %q", request.Acquirer)
+		}
+		return request.Acquirer, nil
+	}
+	for _, rule := range r.rules {
+		if rule.matches(request) {
+			return rule.Acquirer, nil
+		}
+	}
+	return r.defaultAcquirer, nil
+}
+
+func (r Router) CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	acquirer, err := r.Route(issuerRequest)
+	if err != nil {
+		return domain.IssuerResponse{}, err
+	}
+	response, err := r.acquirers[acquirer].CreateIssuerRequest(ctx, issuerRequest)
+	if err != nil {
+		return domain.IssuerResponse{}, err
+	}
+	response.Acquirer = acquirer
+	return response, nil
+}
+
+// GetIssuerRequestStatus asks the acquirer the original request was routed to, as routing is deterministic an
+// unpinned request is routed the same way it was when it was made.
+func (r Router) GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	acquirer, err := r.Route(issuerRequest)
+	if err != nil {
+		return domain.IssuerResponse{}, err
+	}
+	response, err := r.acquirers[acquirer].GetIssuerRequestStatus(ctx, issuerRequest)
+	if err != nil {
+		return domain.IssuerResponse{}, err
+	}
+	response.Acquirer = acquirer
+	return response, nil
+}
@@ -0,0 +1,145 @@
+package routing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/routing"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/routing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issuerRequest(cardNumber, currency string, amount uint64) domain.IssuerRequest {
+	return domain.IssuerRequest{
+		Amount:        &amountV1.Money{MinorUnits: amount, Currency: currency},
+		OperationType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+		PaymentMethod: domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{CardNumber: cardNumber}},
+	}
+}
+
+func TestRouter_Route(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	acquirers := map[string]routing.IssuerGateway{
+		"default": mocks.NewMockIssuerGateway(ctrl),
+		"amex":    mocks.NewMockIssuerGateway(ctrl),
+		"euro":    mocks.NewMockIssuerGateway(ctrl),
+		"large":   mocks.NewMockIssuerGateway(ctrl),
+	}
+	router, err := routing.NewRouter(acquirers, "default",
+		routing.Rule{Acquirer: "amex", BINPrefixes: []string{"34", "37"}},
+		routing.Rule{Acquirer: "euro", Currencies: []string{"EUR"}, MaxAmount: 100000},
+		routing.Rule{Acquirer: "large", MinAmount: 100001},
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		description string
+		request     domain.IssuerRequest
+		expAcquirer string
+		err         error
+	}{
+		{
+			description: "should route by the card's BIN",
+			request:     issuerRequest("378282246310005", "GBP", 1000),
+			expAcquirer: "amex",
+		},
+		{
+			description: "should route by the card's BIN given only the BIN is known",
+			request: domain.IssuerRequest{
+				Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+				PaymentMethod: domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{Bin: "371449"}},
+			},
+			expAcquirer: "amex",
+		},
+		{
+			description: "should route by currency and amount",
+			request:     issuerRequest("4000000000000119", "EUR", 1000),
+			expAcquirer: "euro",
+		},
+		{
+			description: "should route by the first matching rule",
+			request:     issuerRequest("378282246310005", "EUR", 1000),
+			expAcquirer: "amex",
+		},
+		{
+			description: "should route by minimum amount",
+			request:     issuerRequest("4000000000000119", "EUR", 200000),
+			expAcquirer: "large",
+		},
+		{
+			description: "should route to the default acquirer given no rule matches",
+			request:     issuerRequest("4000000000000119", "GBP", 1000),
+			expAcquirer: "default",
+		},
+		{
+			description: "should route to the pinned acquirer regardless of the rules",
+			request: func() domain.IssuerRequest {
+				request := issuerRequest("378282246310005", "GBP", 1000)
+				request.Acquirer = "euro"
+				return request
+			}(),
+			expAcquirer: "euro",
+		},
+		{
+			description: "should return error given the pinned acquirer is unknown",
+			request: func() domain.IssuerRequest {
+				request := issuerRequest("4000000000000119", "GBP", 1000)
+				request.Acquirer = "removed"
+				return request
+			}(),
+			err: routing.ErrUnknownAcquirer,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			acquirer, err := router.Route(tc.request)
+			if tc.err != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expAcquirer, acquirer)
+		})
+	}
+}
+
+func TestRouter_CreateIssuerRequest(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl        = gomock.NewController(t)
+		defaultMock = mocks.NewMockIssuerGateway(ctrl)
+		euroMock    = mocks.NewMockIssuerGateway(ctrl)
+		request     = issuerRequest("4000000000000119", "EUR", 1000)
+	)
+	router, err := routing.NewRouter(map[string]routing.IssuerGateway{"default": defaultMock, "euro": euroMock}, "default",
+		routing.Rule{Acquirer: "euro", Currencies: []string{"EUR"}})
+	require.NoError(t, err)
+
+	euroMock.EXPECT().CreateIssuerRequest(gomock.Any(), request).Return(domain.IssuerResponse{AuthCode: "00"}, nil)
+	response, err := router.CreateIssuerRequest(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssuerResponse{AuthCode: "00", Acquirer: "euro"}, response)
+
+	euroMock.EXPECT().GetIssuerRequestStatus(gomock.Any(), request).Return(domain.IssuerResponse{}, domain.ErrIssuerRequestNotFound)
+	_, err = router.GetIssuerRequestStatus(context.Background(), request)
+	assert.ErrorIs(t, err, domain.ErrIssuerRequestNotFound)
+}
+
+func TestNewRouter(t *testing.T) {
+	t.Parallel()
+	acquirers := map[string]routing.IssuerGateway{"default": mocks.NewMockIssuerGateway(gomock.NewController(t))}
+
+	_, err := routing.NewRouter(acquirers, "missing")
+	assert.ErrorIs(t, err, routing.ErrUnknownAcquirer)
+
+	_, err = routing.NewRouter(acquirers, "default", routing.Rule{Acquirer: "missing"})
+	assert.ErrorIs(t, err, routing.ErrUnknownAcquirer)
+}
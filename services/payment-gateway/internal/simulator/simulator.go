@@ -0,0 +1,207 @@
+// Package simulator is an issuer that responds according to a scenario rather than a real acquirer, it is used to
+// run the gateway locally and to exercise issuer behaviour in tests.
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSimulated is returned for requests a rule fails with an intermittent error, the request never reaches the issuer.
+var ErrSimulated = errors.New("simulated issuer error")
+
+// Scenario describes how the simulator responds, it is loaded from YAML with LoadScenario.
+type Scenario struct {
+	// ResponseCode is returned to requests matching no rule, it defaults to approved.
+	ResponseCode string `yaml:"response_code"`
+	// Rules are evaluated in order, the first matching rule decides the response.
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule matches requests by all of its conditions, empty conditions match every request.
+type Rule struct {
+	CardNumbers []string `yaml:"card_numbers"`
+	// Amounts are magic amounts in minor units.
+	Amounts []uint64 `yaml:"amounts"`
+	// Operations are payment types without their prefix such as authorization or capture.
+	Operations []string `yaml:"operations"`
+
+	// ResponseCode is the ISO 8583 response code returned, it defaults to the scenario's.
+	ResponseCode string `yaml:"response_code"`
+	// Latency delays the response, such as 500ms or 2s.
+	Latency time.Duration `yaml:"latency"`
+	// Timeout records the response but never returns it, the caller waits until its context is done.
+	Timeout bool `yaml:"timeout"`
+	// ErrorRate is the probability between 0 and 1 the request fails with ErrSimulated.
+	ErrorRate float64 `yaml:"error_rate"`
+
+	operations map[paymentsV1.PaymentType]bool
+}
+
+func (r Rule) matches(request domain.IssuerRequest) bool {
+	if len(r.CardNumbers) != 0 {
+		cardNumber := strings.ReplaceAll(request.PaymentMethod.Card.GetCardNumber(), " ", "")
+		matched := false
+		for _, number := range r.CardNumbers {
+			if strings.ReplaceAll(number, " ", "") == cardNumber {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.Amounts) != 0 {
+		matched := false
+		for _, amount := range r.Amounts {
+			if amount == request.Amount.GetMinorUnits() {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.operations) != 0 && !r.operations[request.OperationType] {
+		return false
+	}
+	return true
+}
+
+// DefaultScenario declines the test cards that were historically hardcoded for each operation.
+func DefaultScenario() Scenario {
+	return Scenario{Rules: []Rule{
+		{CardNumbers: []string{"4000000000000119"}, Operations: []string{"authorization"}, ResponseCode: "12"},
+		{CardNumbers: []string{"4000000000000259"}, Operations: []string{"capture"}, ResponseCode: "12"},
+		{CardNumbers: []string{"4000000000003238"}, Operations: []string{"refund"}, ResponseCode: "12"},
+	}}
+}
+
+// LoadScenario reads a scenario from the YAML file at path.
+func LoadScenario(path string) (Scenario, error) {
+	var scenario Scenario
+	file, err := os.Open(path)
+	if err != nil {
+		return scenario, err
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&scenario); err != nil {
+		return scenario, errors.Wrapf(err, "unable to decode scenario %s", path)
+	}
+	return scenario, nil
+}
+
+// Simulator is an IssuerGateway responding according to its scenario. Responses are remembered by reference so
+// status inquiries return the outcome of the original request.
+type Simulator struct {
+	scenario  Scenario
+	responses sync.Map
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+type Option func(s *Simulator)
+
+// WithSeed seeds the random source deciding intermittent errors so that they are reproducible.
+func WithSeed(seed int64) Option {
+	return func(s *Simulator) {
+		s.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// New returns a simulator for the scenario, an error is returned if the scenario is invalid.
+func New(scenario Scenario, opts ...Option) (*Simulator, error) {
+	if scenario.ResponseCode == "" {
+		scenario.ResponseCode = "00"
+	}
+	rules := make([]Rule, len(scenario.Rules))
+	for i, rule := range scenario.Rules {
+		if rule.ErrorRate < 0 || rule.ErrorRate > 1 {
+			return nil, fmt.Errorf("rule %d: error_rate must be between 0 and 1", i)
+		}
+		if rule.ResponseCode == "" {
+			rule.ResponseCode = scenario.ResponseCode
+		}
+		rule.operations = make(map[paymentsV1.PaymentType]bool, len(rule.Operations))
+		for _, operation := range rule.Operations {
+			paymentType, ok := paymentsV1.PaymentType_value["PAYMENT_TYPE_"+strings.ToUpper(operation)]
+			if !ok {
+				return nil, fmt.Errorf("rule %d: unknown operation %s", i, operation)
+			}
+			rule.operations[paymentsV1.PaymentType(paymentType)] = true
+		}
+		rules[i] = rule
+	}
+	scenario.Rules = rules
+
+	s := &Simulator{scenario: scenario, rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+func (s *Simulator) CreateIssuerRequest(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	if issuerRequest.PaymentMethod.Card == nil {
+		return domain.IssuerResponse{}, errors.New("unsupported method")
+	}
+	rule := Rule{ResponseCode: s.scenario.ResponseCode}
+	for _, r := range s.scenario.Rules {
+		if r.matches(issuerRequest) {
+			rule = r
+			break
+		}
+	}
+
+	if rule.Latency > 0 {
+		timer := time.NewTimer(rule.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return domain.IssuerResponse{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if rule.ErrorRate > 0 && s.float64() < rule.ErrorRate {
+		return domain.IssuerResponse{}, ErrSimulated
+	}
+
+	response := domain.IssuerResponse{AuthCode: rule.ResponseCode}
+	s.responses.Store(issuerRequest.Reference, response)
+	if rule.Timeout {
+		// the issuer processed the request but the response is lost
+		<-ctx.Done()
+		return domain.IssuerResponse{}, ctx.Err()
+	}
+	return response, nil
+}
+
+func (s *Simulator) GetIssuerRequestStatus(ctx context.Context, issuerRequest domain.IssuerRequest) (domain.IssuerResponse, error) {
+	response, ok := s.responses.Load(issuerRequest.Reference)
+	if !ok {
+		return domain.IssuerResponse{}, domain.ErrIssuerRequestNotFound
+	}
+	return response.(domain.IssuerResponse), nil
+}
+
+func (s *Simulator) float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rand.Float64()
+}
@@ -0,0 +1,157 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issuerRequest(reference, cardNumber string, amount uint64, operation paymentsV1.PaymentType) domain.IssuerRequest {
+	return domain.IssuerRequest{
+		Reference:     reference,
+		Amount:        &amountV1.Money{MinorUnits: amount, Currency: "GBP"},
+		OperationType: operation,
+		PaymentMethod: domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{CardNumber: cardNumber}},
+	}
+}
+
+func TestSimulator_CreateIssuerRequest(t *testing.T) {
+	t.Parallel()
+	scenario, err := simulator.LoadScenario("testdata/scenario.yaml")
+	require.NoError(t, err)
+	sim, err := simulator.New(scenario)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		description string
+		request     domain.IssuerRequest
+		expCode     string
+		minLatency  time.Duration
+		err         error
+	}{
+		{
+			description: "should approve given no rule matches",
+			request:     issuerRequest("a", "4242424242424242", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION),
+			expCode:     "00",
+		},
+		{
+			description: "should respond by card number and operation",
+			request:     issuerRequest("b", "4000000000000119", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION),
+			expCode:     "05",
+		},
+		{
+			description: "should not match the card for other operations",
+			request:     issuerRequest("c", "4000000000000119", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE),
+			expCode:     "00",
+		},
+		{
+			description: "should respond by magic amount",
+			request:     issuerRequest("d", "4242424242424242", 5100, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION),
+			expCode:     "51",
+		},
+		{
+			description: "should delay the response",
+			request:     issuerRequest("e", "4000000000000259", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_REFUND),
+			expCode:     "91",
+			minLatency:  20 * time.Millisecond,
+		},
+		{
+			description: "should return an error",
+			request:     issuerRequest("f", "4000000000009995", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION),
+			err:         simulator.ErrSimulated,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			start := time.Now()
+			response, err := sim.CreateIssuerRequest(context.Background(), tc.request)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				_, err = sim.GetIssuerRequestStatus(context.Background(), tc.request)
+				assert.ErrorIs(t, err, domain.ErrIssuerRequestNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expCode, response.AuthCode)
+			assert.GreaterOrEqual(t, time.Since(start), tc.minLatency)
+
+			status, err := sim.GetIssuerRequestStatus(context.Background(), tc.request)
+			require.NoError(t, err)
+			assert.Equal(t, response, status)
+		})
+	}
+}
+
+func TestSimulator_CreateIssuerRequest_Timeout(t *testing.T) {
+	t.Parallel()
+	scenario, err := simulator.LoadScenario("testdata/scenario.yaml")
+	require.NoError(t, err)
+	sim, err := simulator.New(scenario)
+	require.NoError(t, err)
+
+	request := issuerRequest("a", "4000000000000341", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = sim.CreateIssuerRequest(ctx, request)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the issuer processed the request so a status inquiry recovers it
+	status, err := sim.GetIssuerRequestStatus(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "00", status.AuthCode)
+}
+
+func TestSimulator_CreateIssuerRequest_ErrorRate(t *testing.T) {
+	t.Parallel()
+	sim, err := simulator.New(simulator.Scenario{Rules: []simulator.Rule{{ErrorRate: 0.5}}}, simulator.WithSeed(1))
+	require.NoError(t, err)
+
+	var failed int
+	for i := 0; i < 100; i++ {
+		if _, err = sim.CreateIssuerRequest(context.Background(),
+			issuerRequest("a", "4242424242424242", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION)); err != nil {
+			failed++
+		}
+	}
+	assert.Greater(t, failed, 25)
+	assert.Less(t, failed, 75)
+}
+
+func TestSimulator_DefaultScenario(t *testing.T) {
+	t.Parallel()
+	sim, err := simulator.New(simulator.DefaultScenario())
+	require.NoError(t, err)
+
+	response, err := sim.CreateIssuerRequest(context.Background(),
+		issuerRequest("a", "4000000000000119", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION))
+	require.NoError(t, err)
+	assert.Equal(t, "12", response.AuthCode)
+
+	response, err = sim.CreateIssuerRequest(context.Background(),
+		issuerRequest("b", "4000000000000259", 1000, paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION))
+	require.NoError(t, err)
+	assert.Equal(t, "00", response.AuthCode)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	_, err := simulator.New(simulator.Scenario{Rules: []simulator.Rule{{Operations: []string{"chargeback"}}}})
+	assert.EqualError(t, err, "rule 0: unknown operation chargeback")
+
+	_, err = simulator.New(simulator.Scenario{Rules: []simulator.Rule{{ErrorRate: 2}}})
+	assert.EqualError(t, err, "rule 0: error_rate must be between 0 and 1")
+}
+
+func TestLoadScenario(t *testing.T) {
+	t.Parallel()
+	_, err := simulator.LoadScenario("testdata/missing.yaml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+func (r Store) CreateCardToken(ctx context.Context, cardToken *domain.CardToken) error {
+	cardToken.MerchantID = merchantFromContext(ctx)
+	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
+		INSERT INTO card_token (token, encrypted_pan, encrypted_data_key, key_id, bin, last_four, expiry_month, expiry_year, merchant_id)
+		VALUES(:token,:encrypted_pan,:encrypted_data_key,:key_id,:bin,:last_four,:expiry_month,:expiry_year,:merchant_id)
+		RETURNING created_at
+		`, cardToken)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return errors.New("row unaffected")
+	}
+	if err = rows.Scan(&cardToken.CreatedAt); err != nil {
+		return errors.Wrap(err, "unable to scan row")
+	}
+	return nil
+}
+
+func (r Store) GetCardToken(ctx context.Context, token string) (*domain.CardToken, error) {
+	var cardToken domain.CardToken
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"SELECT * FROM card_token WHERE token=$1 AND ($2::uuid IS NULL OR merchant_id=$2)", token, merchantFromContext(ctx)).StructScan(&cardToken); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoCardToken
+		}
+		return nil, err
+	}
+	return &cardToken, nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_CardToken(t *testing.T) {
+	t.Parallel()
+
+	cardToken := &domain.CardToken{
+		Token:            "tok_" + uuid.NewV4().String(),
+		EncryptedPAN:     []byte("encrypted pan"),
+		EncryptedDataKey: []byte("encrypted data key"),
+		KeyID:            "key",
+		Bin:              "400000",
+		LastFour:         "0119",
+		ExpiryMonth:      12,
+		ExpiryYear:       2030,
+	}
+	require.NoError(t, testStore.CreateCardToken(context.Background(), cardToken))
+	assert.False(t, cardToken.CreatedAt.IsZero())
+
+	t.Run("should get the card token", func(t *testing.T) {
+		got, err := testStore.GetCardToken(context.Background(), cardToken.Token)
+		require.NoError(t, err)
+		assert.Equal(t, cardToken.EncryptedPAN, got.EncryptedPAN)
+		assert.Equal(t, cardToken.EncryptedDataKey, got.EncryptedDataKey)
+		assert.Equal(t, cardToken.Bin, got.Bin)
+		assert.Equal(t, cardToken.LastFour, got.LastFour)
+	})
+	t.Run("should return error for unknown token", func(t *testing.T) {
+		_, err := testStore.GetCardToken(context.Background(), "tok_unknown")
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNoCardToken, err)
+	})
+	t.Run("should store only the token, BIN and last four against the payment", func(t *testing.T) {
+		card := &paymentsV1.PaymentMethodCard{Token: cardToken.Token, Bin: "400000", LastFour: "0119"}
+		payment := &paymentsV1.Payment{
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: card},
+		}
+		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+		p, err := testStore.GetPayment(context.Background(), payment.Id)
+		require.NoError(t, err)
+		assert.Equal(t, card.String(), p.GetCard().String())
+	})
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+func (r Store) GetIdempotencyKey(ctx context.Context, key string) (*domain.IdempotencyKey, error) {
+	var k domain.IdempotencyKey
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"SELECT * FROM idempotency_key WHERE key=$1 AND merchant_id IS NOT DISTINCT FROM $2", key, merchantFromContext(ctx)).StructScan(&k); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoIdempotencyKey
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+// CreateIdempotencyKey reserves the key for the request. If the key has already been
+// reserved domain.ErrIdempotencyKeyExists is returned.
+func (r Store) CreateIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey) error {
+	key.MerchantID = merchantFromContext(ctx)
+	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
+		INSERT INTO idempotency_key (key, request_path, fingerprint, merchant_id)
+		VALUES(:key,:request_path,:fingerprint,:merchant_id)
+		RETURNING created_at
+		`, key)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Constraint == "idempotency_key_merchant_key_idx" {
+				return domain.ErrIdempotencyKeyExists
+			}
+		}
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return errors.New("row unaffected")
+	}
+	if err = rows.Scan(&key.CreatedAt); err != nil {
+		return errors.Wrap(err, "unable to scan row")
+	}
+	return nil
+}
+
+// CompleteIdempotencyKey stores the response returned for the key so that it can be replayed.
+func (r Store) CompleteIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey) error {
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx,
+		`UPDATE idempotency_key SET response_status=$1, response_body=$2, completed_at=now()
+		WHERE key=$3 AND merchant_id IS NOT DISTINCT FROM $4`,
+		key.ResponseStatus, key.ResponseBody, key.Key, merchantFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	affected, err := execContext.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNoIdempotencyKey
+	}
+	return nil
+}
+
+// ReclaimIdempotencyKey takes over a key whose request was reserved before staleBefore and never completed, such as
+// when the instance processing it stopped. domain.ErrIdempotencyKeyExists is returned if the key is not stale, as when
+// another retry reclaimed it first.
+func (r Store) ReclaimIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey, staleBefore time.Time) error {
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		`UPDATE idempotency_key SET created_at=now()
+		WHERE key=$1 AND merchant_id IS NOT DISTINCT FROM $2 AND completed_at IS NULL AND created_at < $3
+		RETURNING created_at`,
+		key.Key, merchantFromContext(ctx), staleBefore).Scan(&key.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrIdempotencyKeyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey releases the key so that the request can be retried.
+func (r Store) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	_, err := r.connFromContext(ctx).ExecContext(ctx,
+		`DELETE FROM idempotency_key WHERE key=$1 AND merchant_id IS NOT DISTINCT FROM $2`, key, merchantFromContext(ctx))
+	return err
+}
@@ -0,0 +1,106 @@
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStore_CreateIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should successfully create an idempotency key", func(t *testing.T) {
+		key := &domain.IdempotencyKey{
+			Key:         uuid.NewV4().String(),
+			RequestPath: "/capture",
+			Fingerprint: "fingerprint",
+		}
+		require.NoError(t, testStore.CreateIdempotencyKey(context.Background(), key))
+		assert.False(t, key.CreatedAt.IsZero())
+	})
+	t.Run("should return error given the key already exists", func(t *testing.T) {
+		key := &domain.IdempotencyKey{
+			Key:         uuid.NewV4().String(),
+			RequestPath: "/capture",
+			Fingerprint: "fingerprint",
+		}
+		require.NoError(t, testStore.CreateIdempotencyKey(context.Background(), key))
+		err := testStore.CreateIdempotencyKey(context.Background(), key)
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrIdempotencyKeyExists, err)
+	})
+}
+
+func TestStore_GetIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should successfully get a completed idempotency key", func(t *testing.T) {
+		key := &domain.IdempotencyKey{
+			Key:         uuid.NewV4().String(),
+			RequestPath: "/capture",
+			Fingerprint: "fingerprint",
+		}
+		require.NoError(t, testStore.CreateIdempotencyKey(context.Background(), key))
+
+		key.ResponseStatus = sql.NullInt32{Int32: 200, Valid: true}
+		key.ResponseBody = []byte(`{"id":"1"}`)
+		require.NoError(t, testStore.CompleteIdempotencyKey(context.Background(), key))
+
+		k, err := testStore.GetIdempotencyKey(context.Background(), key.Key)
+		require.NoError(t, err)
+		assert.Equal(t, key.Fingerprint, k.Fingerprint)
+		assert.Equal(t, key.ResponseStatus, k.ResponseStatus)
+		assert.Equal(t, key.ResponseBody, k.ResponseBody)
+		assert.True(t, k.CompletedAt.Valid)
+	})
+	t.Run("should return error for unknown idempotency key", func(t *testing.T) {
+		_, err := testStore.GetIdempotencyKey(context.Background(), uuid.NewV4().String())
+		require.Error(t, err)
+		assert.Equal(t, domain.ErrNoIdempotencyKey, err)
+	})
+}
+
+func TestStore_DeleteIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should release the idempotency key", func(t *testing.T) {
+		key := &domain.IdempotencyKey{
+			Key:         uuid.NewV4().String(),
+			RequestPath: "/capture",
+			Fingerprint: "fingerprint",
+		}
+		require.NoError(t, testStore.CreateIdempotencyKey(context.Background(), key))
+		require.NoError(t, testStore.DeleteIdempotencyKey(context.Background(), key.Key))
+
+		_, err := testStore.GetIdempotencyKey(context.Background(), key.Key)
+		assert.Equal(t, domain.ErrNoIdempotencyKey, err)
+	})
+}
+
+func TestStore_ReclaimIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	key := &domain.IdempotencyKey{
+		Key:         uuid.NewV4().String(),
+		RequestPath: "/capture",
+		Fingerprint: "fingerprint",
+	}
+	require.NoError(t, testStore.CreateIdempotencyKey(context.Background(), key))
+	reservedAt := key.CreatedAt
+
+	t.Run("should not reclaim a key reserved after staleBefore", func(t *testing.T) {
+		err := testStore.ReclaimIdempotencyKey(context.Background(), key, reservedAt.Add(-time.Minute))
+		assert.Equal(t, domain.ErrIdempotencyKeyExists, err)
+	})
+	t.Run("should reclaim a key reserved before staleBefore", func(t *testing.T) {
+		require.NoError(t, testStore.ReclaimIdempotencyKey(context.Background(), key, reservedAt.Add(time.Minute)))
+		assert.True(t, key.CreatedAt.After(reservedAt))
+	})
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// CreateLedgerEntries posts the entries to the ledger. It should be called within the same transaction as the payment
+// action the entries record.
+func (r Store) CreateLedgerEntries(ctx context.Context, entries ...*domain.LedgerEntry) error {
+	for _, entry := range entries {
+		rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
+			INSERT INTO ledger_entry (payment_id, payment_action_id, debit_account, credit_account, amount, currency)
+			VALUES(:payment_id, :payment_action_id, CAST(:debit_account AS ledger_account), CAST(:credit_account AS ledger_account), :amount, :currency)
+			RETURNING id, created_at
+			`, map[string]interface{}{
+			"payment_id":        entry.PaymentID,
+			"payment_action_id": entry.PaymentActionID,
+			"debit_account":     entry.DebitAccount,
+			"credit_account":    entry.CreditAccount,
+			"amount":            int64(entry.Amount),
+			"currency":          entry.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		var (
+			id        uuid.UUID
+			createdAt time.Time
+		)
+		if !rows.Next() {
+			rows.Close()
+			return errors.New("row unaffected")
+		}
+		err = rows.Scan(&id, &createdAt)
+		rows.Close()
+		if err != nil {
+			return errors.Wrap(err, "unable to scan row")
+		}
+		entry.ID = id
+		entry.CreatedAt = createdAt
+	}
+	return nil
+}
+
+// GetLedgerBalances returns the balances of the payment's accounts, accounts without entries have a zero balance.
+func (r Store) GetLedgerBalances(ctx context.Context, paymentID string) (domain.LedgerBalances, error) {
+	balances, err := r.ListLedgerBalances(ctx, []string{paymentID})
+	if err != nil {
+		return domain.LedgerBalances{}, err
+	}
+	return balances[paymentID], nil
+}
+
+// ListLedgerBalances returns the balances of each of the payments' accounts keyed by payment id, payments without
+// entries are omitted.
+func (r Store) ListLedgerBalances(ctx context.Context, paymentIDs []string) (map[string]domain.LedgerBalances, error) {
+	balances := make(map[string]domain.LedgerBalances, len(paymentIDs))
+	if len(paymentIDs) == 0 {
+		return balances, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT payment_id, account, SUM(net_debit) FROM (
+			SELECT payment_id, debit_account AS account, amount AS net_debit FROM ledger_entry WHERE payment_id IN (?)
+			UNION ALL
+			SELECT payment_id, credit_account AS account, -amount AS net_debit FROM ledger_entry WHERE payment_id IN (?)
+		) AS postings
+		GROUP BY payment_id, account`, paymentIDs, paymentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.connFromContext(ctx).QueryxContext(ctx, r.db.DB.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			paymentID uuid.UUID
+			account   domain.LedgerAccount
+			netDebit  int64
+		)
+		if err := rows.Scan(&paymentID, &account, &netDebit); err != nil {
+			return nil, errors.Wrap(err, "unable to scan row")
+		}
+		balance := balances[paymentID.String()]
+		balance.Add(account, netDebit)
+		balances[paymentID.String()] = balance
+	}
+	return balances, rows.Err()
+}
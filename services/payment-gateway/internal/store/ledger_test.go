@@ -0,0 +1,58 @@
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStore_Ledger(t *testing.T) {
+	t.Parallel()
+
+	payment := &paymentsV1.Payment{
+		Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+		PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+		PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+	}
+	require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+	balances, err := testStore.GetLedgerBalances(context.Background(), payment.Id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LedgerBalances{}, balances)
+
+	authorization := &paymentsV1.PaymentAction{Amount: 1000, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION, PaymentId: payment.Id}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), authorization))
+	capture := &paymentsV1.PaymentAction{Amount: 400, PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE, PaymentId: payment.Id}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), capture))
+
+	entries := append(
+		domain.NewLedgerEntries(payment.Id, authorization.Id, domain.PaymentTypeAuthorization, 1000, "GBP"),
+		domain.NewLedgerEntries(payment.Id, capture.Id, domain.PaymentTypeCapture, 400, "GBP")...,
+	)
+	require.NoError(t, testStore.ExecInTransaction(context.Background(), func(ctx context.Context) error {
+		return testStore.CreateLedgerEntries(ctx, entries...)
+	}))
+	for _, entry := range entries {
+		assert.NotEmpty(t, entry.ID)
+		assert.False(t, entry.CreatedAt.IsZero())
+	}
+
+	balances, err = testStore.GetLedgerBalances(context.Background(), payment.Id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.LedgerBalances{
+		Customer:        600,
+		Authorized:      600,
+		Captured:        400,
+		MerchantPayable: 400,
+	}, balances)
+
+	listed, err := testStore.ListLedgerBalances(context.Background(), []string{payment.Id})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.LedgerBalances{payment.Id: balances}, listed)
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+func (r Store) CreateMerchant(ctx context.Context, merchant *domain.Merchant) error {
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"INSERT INTO merchant (name) VALUES($1) RETURNING id, created_at", merchant.Name).
+		Scan(&merchant.ID, &merchant.CreatedAt); err != nil {
+		return errors.Wrap(err, "unable to scan row")
+	}
+	return nil
+}
+
+// merchantRow is a merchant as stored, its accepted card brands held in an array.
+type merchantRow struct {
+	domain.Merchant
+	AcceptedCardBrands pq.StringArray `db:"accepted_card_brands"`
+}
+
+func (r Store) GetMerchant(ctx context.Context, id string) (*domain.Merchant, error) {
+	var row merchantRow
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx, "SELECT * FROM merchant WHERE id=$1", uuid.FromStringOrNil(id)).StructScan(&row); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoMerchant
+		}
+		return nil, err
+	}
+	merchant := row.Merchant
+	for _, brand := range row.AcceptedCardBrands {
+		merchant.AcceptedCardBrands = append(merchant.AcceptedCardBrands, domain.CardBrand(brand))
+	}
+	return &merchant, nil
+}
+
+// UpdateMerchantCardBrands replaces the card brands the merchant accepts, domain.ErrNoMerchant is returned for an
+// unknown merchant.
+func (r Store) UpdateMerchantCardBrands(ctx context.Context, merchant *domain.Merchant) error {
+	brands := make(pq.StringArray, 0, len(merchant.AcceptedCardBrands))
+	for _, brand := range merchant.AcceptedCardBrands {
+		brands = append(brands, string(brand))
+	}
+	result, err := r.connFromContext(ctx).ExecContext(ctx,
+		"UPDATE merchant SET accepted_card_brands=$1 WHERE id=$2", brands, merchant.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNoMerchant
+	}
+	return nil
+}
+
+func (r Store) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
+		INSERT INTO merchant_api_key (merchant_id, prefix, key_hash)
+		VALUES(:merchant_id,:prefix,:key_hash)
+		RETURNING id, created_at
+		`, key)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return errors.New("row unaffected")
+	}
+	if err = rows.Scan(&key.ID, &key.CreatedAt); err != nil {
+		return errors.Wrap(err, "unable to scan row")
+	}
+	return nil
+}
+
+// GetAPIKeyByHash returns the unrevoked api key with the hash, domain.ErrNoAPIKey is returned if there is none.
+func (r Store) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"SELECT * FROM merchant_api_key WHERE key_hash=$1 AND revoked_at IS NULL", keyHash).StructScan(&key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoAPIKey
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeAPIKeys revokes every api key of the merchant.
+func (r Store) RevokeAPIKeys(ctx context.Context, merchantID string) error {
+	_, err := r.connFromContext(ctx).ExecContext(ctx,
+		"UPDATE merchant_api_key SET revoked_at=now() WHERE merchant_id=$1 AND revoked_at IS NULL", uuid.FromStringOrNil(merchantID))
+	return err
+}
@@ -0,0 +1,118 @@
+//go:build integration
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMerchant(t *testing.T, name string) context.Context {
+	merchant := &domain.Merchant{Name: name}
+	require.NoError(t, testStore.CreateMerchant(context.Background(), merchant))
+	return domain.ContextWithMerchant(context.Background(), merchant.ID.String())
+}
+
+func TestStore_Merchant(t *testing.T) {
+	t.Parallel()
+
+	merchant := &domain.Merchant{Name: "merchant"}
+	require.NoError(t, testStore.CreateMerchant(context.Background(), merchant))
+	assert.False(t, merchant.CreatedAt.IsZero())
+
+	got, err := testStore.GetMerchant(context.Background(), merchant.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, merchant.Name, got.Name)
+
+	_, err = testStore.GetMerchant(context.Background(), uuid.NewV4().String())
+	assert.Equal(t, domain.ErrNoMerchant, err)
+
+	merchant.AcceptedCardBrands = []domain.CardBrand{domain.CardBrandVisa, domain.CardBrandMastercard}
+	require.NoError(t, testStore.UpdateMerchantCardBrands(context.Background(), merchant))
+	got, err = testStore.GetMerchant(context.Background(), merchant.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, merchant.AcceptedCardBrands, got.AcceptedCardBrands)
+	assert.Equal(t, domain.ErrNoMerchant, testStore.UpdateMerchantCardBrands(context.Background(),
+		&domain.Merchant{ID: uuid.NewV4()}))
+
+	key := &domain.APIKey{MerchantID: merchant.ID, Prefix: "sk_abc", KeyHash: uuid.NewV4().String()}
+	require.NoError(t, testStore.CreateAPIKey(context.Background(), key))
+
+	gotKey, err := testStore.GetAPIKeyByHash(context.Background(), key.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, merchant.ID, gotKey.MerchantID)
+
+	require.NoError(t, testStore.RevokeAPIKeys(context.Background(), merchant.ID.String()))
+	_, err = testStore.GetAPIKeyByHash(context.Background(), key.KeyHash)
+	assert.Equal(t, domain.ErrNoAPIKey, err)
+}
+
+func TestStore_MerchantScoping(t *testing.T) {
+	t.Parallel()
+
+	var (
+		merchantA = createMerchant(t, "merchant a")
+		merchantB = createMerchant(t, "merchant b")
+		payment   = &paymentsV1.Payment{
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+		}
+	)
+	require.NoError(t, testStore.CreatePayment(merchantA, payment))
+	merchantID, _ := domain.MerchantFromContext(merchantA)
+	assert.Equal(t, merchantID, payment.MerchantId)
+
+	t.Run("should only get the merchant's own payments", func(t *testing.T) {
+		p, err := testStore.GetPayment(merchantA, payment.Id)
+		require.NoError(t, err)
+		assert.Equal(t, merchantID, p.MerchantId)
+
+		_, err = testStore.GetPayment(merchantB, payment.Id)
+		assert.Equal(t, domain.ErrNoPayment, err)
+
+		// the gateway itself is not scoped to a merchant
+		_, err = testStore.GetPayment(context.Background(), payment.Id)
+		require.NoError(t, err)
+	})
+	t.Run("should only list the merchant's own payments", func(t *testing.T) {
+		payments, err := testStore.ListPayments(merchantB, &domain.ListPaymentFilters{})
+		require.NoError(t, err)
+		assert.Empty(t, payments)
+
+		payments, err = testStore.ListPayments(merchantA, &domain.ListPaymentFilters{})
+		require.NoError(t, err)
+		require.Len(t, payments, 1)
+		assert.Equal(t, payment.Id, payments[0].Id)
+	})
+	t.Run("should not make actions towards another merchant's payment", func(t *testing.T) {
+		action := &paymentsV1.PaymentAction{
+			Amount:      1000,
+			PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
+			PaymentId:   payment.Id,
+		}
+		assert.Equal(t, domain.ErrNoPaymentForAction, testStore.CreatePaymentAction(merchantB, action))
+		require.NoError(t, testStore.CreatePaymentAction(merchantA, action))
+
+		actions, err := testStore.ListPaymentActions(merchantB, &domain.ListPaymentActionFilters{PaymentIDs: []string{payment.Id}})
+		require.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+	t.Run("should scope idempotency keys to the merchant", func(t *testing.T) {
+		key := uuid.NewV4().String()
+		require.NoError(t, testStore.CreateIdempotencyKey(merchantA, &domain.IdempotencyKey{Key: key, RequestPath: "/capture", Fingerprint: "a"}))
+		require.NoError(t, testStore.CreateIdempotencyKey(merchantB, &domain.IdempotencyKey{Key: key, RequestPath: "/capture", Fingerprint: "b"}))
+
+		got, err := testStore.GetIdempotencyKey(merchantB, key)
+		require.NoError(t, err)
+		assert.Equal(t, "b", got.Fingerprint)
+	})
+}
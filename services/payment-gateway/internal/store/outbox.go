@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+func (r Store) CreateOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
+		INSERT INTO outbox (payment_id, event_type, payload)
+		VALUES(:payment_id,:event_type,:payload)
+		RETURNING id, created_at
+		`, event)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return errors.New("row unaffected")
+	}
+	if err = rows.Scan(&event.ID, &event.CreatedAt); err != nil {
+		return errors.Wrap(err, "unable to scan row")
+	}
+	return nil
+}
+
+// ListUnpublishedOutboxEvents returns the oldest unpublished events that are due to be published, skipping any event
+// of a payment with an earlier event waiting to be retried so that a payment's events are published in order. Events
+// that have failed are never returned. The events are locked until the transaction ends with any events locked by
+// another relay being skipped.
+func (r Store) ListUnpublishedOutboxEvents(ctx context.Context, limit uint64) ([]*domain.OutboxEvent, error) {
+	rows, err := r.connFromContext(ctx).QueryxContext(ctx, `
+		SELECT * FROM outbox WHERE published_at IS NULL AND failed_at IS NULL AND next_attempt_at <= now()
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox earlier WHERE earlier.payment_id = outbox.payment_id
+			AND earlier.published_at IS NULL AND earlier.failed_at IS NULL AND earlier.next_attempt_at > now()
+			AND earlier.created_at < outbox.created_at
+		)
+		ORDER BY created_at LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domain.OutboxEvent, 0)
+	for rows.Next() {
+		var event domain.OutboxEvent
+		if err := rows.StructScan(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+func (r Store) MarkOutboxEventPublished(ctx context.Context, id string) error {
+	_, err := r.connFromContext(ctx).ExecContext(ctx, `UPDATE outbox SET published_at=now() where id=$1`, id)
+	return err
+}
+
+// UpdateOutboxEventAttempt records a failed attempt to publish the event.
+func (r Store) UpdateOutboxEventAttempt(ctx context.Context, event *domain.OutboxEvent) error {
+	_, err := r.connFromContext(ctx).ExecContext(ctx, `
+		UPDATE outbox SET attempts=$1, next_attempt_at=$2, last_error=$3, failed_at=$4 WHERE id=$5`,
+		event.Attempts, event.NextAttemptAt, event.LastError, event.FailedAt, event.ID)
+	return err
+}
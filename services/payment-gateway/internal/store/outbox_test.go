@@ -0,0 +1,101 @@
+// +build integration
+
+package store_test
+
+import (
+	"context"
+	"database/sql"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStore_OutboxEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create, list and publish an outbox event", func(t *testing.T) {
+		payment := &paymentsV1.Payment{
+			Amount: &amountV1.Money{
+				MinorUnits: 1000,
+				Currency:   "GBP",
+			},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+		}
+		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+		event, err := domain.NewOutboxEvent(payment.Id, &paymentsV1.PaymentCreated{Payment: payment})
+		require.NoError(t, err)
+		require.NoError(t, testStore.CreateOutboxEvent(context.Background(), event))
+		assert.NotEmpty(t, event.ID)
+		assert.False(t, event.CreatedAt.IsZero())
+
+		require.NoError(t, testStore.ExecInTransaction(context.Background(), func(ctx context.Context) error {
+			events, err := testStore.ListUnpublishedOutboxEvents(ctx, 10000)
+			require.NoError(t, err)
+
+			var found *domain.OutboxEvent
+			for _, e := range events {
+				if e.ID == event.ID {
+					found = e
+				}
+			}
+			require.NotNil(t, found)
+			assert.Equal(t, event.Payload, found.Payload)
+			assert.Equal(t, event.EventType, found.EventType)
+			return testStore.MarkOutboxEventPublished(ctx, event.ID.String())
+		}))
+
+		events, err := testStore.ListUnpublishedOutboxEvents(context.Background(), 10000)
+		require.NoError(t, err)
+		for _, e := range events {
+			assert.NotEqual(t, event.ID, e.ID)
+		}
+	})
+	t.Run("should hold back a payment's events behind one waiting to be retried until it fails", func(t *testing.T) {
+		payment := &paymentsV1.Payment{
+			Amount: &amountV1.Money{
+				MinorUnits: 1000,
+				Currency:   "GBP",
+			},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+		}
+		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+		first, err := domain.NewOutboxEvent(payment.Id, &paymentsV1.PaymentCreated{Payment: payment})
+		require.NoError(t, err)
+		require.NoError(t, testStore.CreateOutboxEvent(context.Background(), first))
+		second, err := domain.NewOutboxEvent(payment.Id, &paymentsV1.PaymentAuthorized{Payment: payment})
+		require.NoError(t, err)
+		require.NoError(t, testStore.CreateOutboxEvent(context.Background(), second))
+
+		listed := func() map[string]bool {
+			events, err := testStore.ListUnpublishedOutboxEvents(context.Background(), 10000)
+			require.NoError(t, err)
+			ids := make(map[string]bool)
+			for _, e := range events {
+				ids[e.ID.String()] = true
+			}
+			return ids
+		}
+
+		first.Attempts = 1
+		first.NextAttemptAt = time.Now().Add(time.Hour)
+		first.LastError = sql.NullString{String: "error", Valid: true}
+		require.NoError(t, testStore.UpdateOutboxEventAttempt(context.Background(), first))
+		ids := listed()
+		assert.False(t, ids[first.ID.String()])
+		assert.False(t, ids[second.ID.String()])
+
+		first.FailedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		require.NoError(t, testStore.UpdateOutboxEventAttempt(context.Background(), first))
+		ids = listed()
+		assert.False(t, ids[first.ID.String()])
+		assert.True(t, ids[second.ID.String()])
+	})
+}
@@ -2,8 +2,9 @@ package store
 
 import (
 	"context"
-	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	"database/sql"
 	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
 	"github.com/jmoiron/sqlx"
 	uuid "github.com/kevinburke/go.uuid"
@@ -16,22 +17,129 @@ import (
 
 func (r Store) GetPayment(ctx context.Context, id string) (*paymentsV1.Payment, error) {
 	var p domain.Payment
-	if err := r.connFromContext(ctx).QueryRowxContext(ctx, "SELECT * FROM payment WHERE id=$1", uuid.FromStringOrNil(id)).StructScan(&p); err != nil {
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"SELECT * FROM payment WHERE id=$1 AND ($2::uuid IS NULL OR merchant_id=$2)", uuid.FromStringOrNil(id), merchantFromContext(ctx)).StructScan(&p); err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return nil, domain.ErrNoPayment
 		}
 		return nil, err
 	}
 
+	return paymentToProto(p), nil
+}
+
+// GetPaymentForUpdate gets the payment locking it until the surrounding transaction ends, so that concurrent
+// actions on the same payment are made one after another. It must be called within ExecInTransaction.
+func (r Store) GetPaymentForUpdate(ctx context.Context, id string) (*paymentsV1.Payment, error) {
+	var p domain.Payment
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"SELECT * FROM payment WHERE id=$1 AND ($2::uuid IS NULL OR merchant_id=$2) FOR UPDATE", uuid.FromStringOrNil(id), merchantFromContext(ctx)).StructScan(&p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoPayment
+		}
+		return nil, err
+	}
+
+	return paymentToProto(p), nil
+}
+
+// ListPayments returns the payments matching the filters ordered newest first, by the order they were inserted in.
+func (r Store) ListPayments(ctx context.Context, filters *domain.ListPaymentFilters) ([]*paymentsV1.Payment, error) {
+	var (
+		conditions []string
+		arg        = map[string]interface{}{}
+	)
+	if merchantID := merchantFromContext(ctx); merchantID.Valid {
+		conditions = append(conditions, "merchant_id = :merchant_id")
+		arg["merchant_id"] = merchantID
+	}
+	if len(filters.Statuses) != 0 {
+		conditions = append(conditions, "status IN (:statuses)")
+		arg["statuses"] = filters.Statuses
+	}
+	if len(filters.Currencies) != 0 {
+		conditions = append(conditions, "currency IN (:currencies)")
+		arg["currencies"] = filters.Currencies
+	}
+	if filters.MinAmount != 0 {
+		conditions = append(conditions, "amount >= :min_amount")
+		arg["min_amount"] = filters.MinAmount
+	}
+	if filters.MaxAmount != 0 {
+		conditions = append(conditions, "amount <= :max_amount")
+		arg["max_amount"] = filters.MaxAmount
+	}
+	if !filters.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= :created_after")
+		arg["created_after"] = filters.CreatedAfter
+	}
+	if !filters.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < :created_before")
+		arg["created_before"] = filters.CreatedBefore
+	}
+	if filters.CardLastFour != "" {
+		conditions = append(conditions, "card_last_four = :card_last_four")
+		arg["card_last_four"] = filters.CardLastFour
+	}
+	if !filters.ExpiresBefore.IsZero() {
+		conditions = append(conditions, "expires_at < :expires_before")
+		arg["expires_before"] = filters.ExpiresBefore
+	}
+	if filters.Cursor != nil {
+		conditions = append(conditions, "seq < (SELECT seq FROM payment WHERE id = :cursor_id)")
+		arg["cursor_id"] = uuid.FromStringOrNil(filters.Cursor.ID)
+	}
+
+	query := "SELECT * FROM payment"
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY seq DESC"
+	if filters.Limit != 0 {
+		query += " LIMIT :limit"
+		arg["limit"] = filters.Limit
+	}
+
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	query = r.db.DB.Rebind(query)
+	rows, err := r.connFromContext(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]*paymentsV1.Payment, 0)
+	for rows.Next() {
+		var p domain.Payment
+		if err := rows.StructScan(&p); err != nil {
+			return nil, err
+		}
+		payments = append(payments, paymentToProto(p))
+	}
+	return payments, rows.Err()
+}
+
+func paymentToProto(p domain.Payment) *paymentsV1.Payment {
+	if p.CardBrand == "" {
+		p.CardBrand = string(domain.CardBrandFromBIN(p.CardBin))
+	}
 	pbPayment := &paymentsV1.Payment{
-		Id: p.ID.String(),
-		Amount: &amountV1.Money{
-			MinorUnits: uint64(p.Amount),
-			Currency:   p.Currency,
-		},
+		Id:     p.ID.String(),
+		Amount: currencyV1.NewMoney(uint64(p.Amount), p.Currency),
 		PaymentMethod: &paymentsV1.Payment_Card{
 			Card: &paymentsV1.PaymentMethodCard{
-				CardNumber: p.CardNumber,
+				Token:    p.CardToken.String,
+				Bin:      p.CardBin,
+				LastFour: p.CardLastFour,
+				Brand:    p.CardBrand,
 			}},
 		PaymentStatus: p.Status.ToProto(),
 		CreatedAt:     timestamppb.New(p.CreatedAt),
@@ -39,15 +147,58 @@ func (r Store) GetPayment(ctx context.Context, id string) (*paymentsV1.Payment,
 	if p.UpdatedAt.Valid {
 		pbPayment.UpdatedAt = timestamppb.New(p.UpdatedAt.Time)
 	}
-	return pbPayment, nil
+	if p.MerchantID.Valid {
+		pbPayment.MerchantId = p.MerchantID.UUID.String()
+	}
+	if p.SettlementCurrency.Valid {
+		pbPayment.SettlementAmount = currencyV1.NewMoney(uint64(p.SettlementAmount.Int64), p.SettlementCurrency.String)
+		pbPayment.FxRate = p.FXRate.String
+	}
+	if p.ExpiresAt.Valid {
+		pbPayment.ExpiresAt = timestamppb.New(p.ExpiresAt.Time)
+	}
+	return pbPayment
 }
 
 func (r Store) ListPaymentActions(ctx context.Context, filters *domain.ListPaymentActionFilters) ([]*paymentsV1.PaymentAction, error) {
-	arg := map[string]interface{}{}
+	var (
+		conditions []string
+		arg        = map[string]interface{}{}
+	)
+	if merchantID := merchantFromContext(ctx); merchantID.Valid {
+		conditions = append(conditions, "payment_id IN (SELECT id FROM payment WHERE merchant_id = :merchant_id)")
+		arg["merchant_id"] = merchantID
+	}
 	if len(filters.PaymentIDs) != 0 {
+		conditions = append(conditions, "payment_id IN (:payment_id)")
 		arg["payment_id"] = filters.PaymentIDs
 	}
-	query, args, err := sqlx.Named("SELECT * FROM payment_action WHERE payment_id=:payment_id", arg)
+	if len(filters.PaymentTypes) != 0 {
+		conditions = append(conditions, "payment_type IN (:payment_types)")
+		arg["payment_types"] = filters.PaymentTypes
+	}
+	if filters.Unprocessed {
+		conditions = append(conditions, "processed_at IS NULL")
+	}
+	if filters.Unclaimed {
+		conditions = append(conditions, "claimed_at IS NULL")
+	}
+	if !filters.CreatedBefore.IsZero() {
+		conditions = append(conditions, "COALESCE(claimed_at, created_at) < :created_before")
+		arg["created_before"] = filters.CreatedBefore
+	}
+
+	query := "SELECT * FROM payment_action"
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at"
+	if filters.Limit != 0 {
+		query += " LIMIT :limit"
+		arg["limit"] = filters.Limit
+	}
+
+	query, args, err := sqlx.Named(query, arg)
 	if err != nil {
 		return nil, err
 	}
@@ -57,30 +208,83 @@ func (r Store) ListPaymentActions(ctx context.Context, filters *domain.ListPayme
 	}
 
 	query = r.db.DB.Rebind(query)
-	rows, err := r.connFromContext(ctx).Queryx(query, args...)
+	rows, err := r.connFromContext(ctx).QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
 	paymentActions := make([]*paymentsV1.PaymentAction, 0)
 	for rows.Next() {
 		var action domain.PaymentAction
 		if err := rows.StructScan(&action); err != nil {
 			return nil, err
 		}
-		paymentAction := &paymentsV1.PaymentAction{
-			Id:           action.ID.String(),
-			Amount:       uint64(action.Amount),
-			PaymentType:  action.PaymentType.ToProto(),
-			ResponseCode: action.ResponseCode.String,
-			PaymentId:    action.PaymentID.String(),
-			CreatedAt:    timestamppb.New(action.CreatedAt),
+		paymentActions = append(paymentActions, paymentActionToProto(action))
+	}
+	return paymentActions, rows.Err()
+}
+
+// GetPaymentActionForUpdate returns the payment action locking it until the transaction ends.
+// If the action is already locked by another transaction domain.ErrNoPaymentAction is returned
+// rather than waiting. It must be called within ExecInTransaction.
+func (r Store) GetPaymentActionForUpdate(ctx context.Context, id string) (*paymentsV1.PaymentAction, error) {
+	var action domain.PaymentAction
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		`SELECT * FROM payment_action WHERE id=$1
+		AND ($2::uuid IS NULL OR payment_id IN (SELECT id FROM payment WHERE merchant_id=$2))
+		FOR UPDATE SKIP LOCKED`, uuid.FromStringOrNil(id), merchantFromContext(ctx)).StructScan(&action); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoPaymentAction
 		}
-		if action.ProcessedAt.Valid {
-			paymentAction.ProcessedAt = timestamppb.New(action.ProcessedAt.Time)
+		return nil, err
+	}
+	return paymentActionToProto(action), nil
+}
+
+// ClaimPaymentAction marks the payment action as claimed by an authorization worker, returning
+// domain.ErrPaymentActionClaimed if it has already been claimed. It must be called within ExecInTransaction holding
+// the lock on the action.
+func (r Store) ClaimPaymentAction(ctx context.Context, id string) error {
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `
+		UPDATE payment_action SET claimed_at=clock_timestamp()
+		WHERE id=$1 AND claimed_at IS NULL
+		AND ($2::uuid IS NULL OR payment_id IN (SELECT id FROM payment WHERE merchant_id=$2))`,
+		uuid.FromStringOrNil(id), merchantFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	affected, err := execContext.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPaymentActionClaimed
+	}
+	return nil
+}
+
+func paymentActionToProto(action domain.PaymentAction) *paymentsV1.PaymentAction {
+	paymentAction := &paymentsV1.PaymentAction{
+		Id:               action.ID.String(),
+		Amount:           uint64(action.Amount),
+		PaymentType:      action.PaymentType.ToProto(),
+		ResponseCode:     action.ResponseCode.String,
+		PaymentId:        action.PaymentID.String(),
+		CreatedAt:        timestamppb.New(action.CreatedAt),
+		Acquirer:         action.Acquirer.String,
+		SettlementAmount: uint64(action.SettlementAmount.Int64),
+	}
+	if action.ProcessedAt.Valid {
+		paymentAction.ProcessedAt = timestamppb.New(action.ProcessedAt.Time)
+	}
+	if action.ResponseCategory.Valid {
+		paymentAction.ResponseCategory = domain.ResponseCategory(action.ResponseCategory.String).ToProto()
+		if paymentAction.ResponseCategory != paymentsV1.ResponseCategory_RESPONSE_CATEGORY_APPROVED {
+			paymentAction.DeclineReason = domain.LookupResponseCode(action.ResponseCode.String).Reason
 		}
-		paymentActions = append(paymentActions, paymentAction)
 	}
-	return paymentActions, nil
+	return paymentAction
 }
 
 func (r Store) CreatePayment(ctx context.Context, payment *paymentsV1.Payment) error {
@@ -90,14 +294,23 @@ func (r Store) CreatePayment(ctx context.Context, payment *paymentsV1.Payment) e
 	}
 
 	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
-		INSERT INTO payment (amount, currency, status, card_number)
-		VALUES(:amount,:currency,:status,:card_number)
+		INSERT INTO payment (amount, currency, status, card_token, card_bin, card_last_four, card_brand, merchant_id,
+			settlement_currency, settlement_amount, fx_rate)
+		VALUES(:amount,:currency,:status,:card_token,:card_bin,:card_last_four,:card_brand,:merchant_id,
+			:settlement_currency,:settlement_amount,:fx_rate)
 		RETURNING id, created_at;
 		`, &domain.Payment{
-		Amount:     int64(payment.Amount.MinorUnits),
-		Status:     paymentStatus,
-		Currency:   payment.Amount.Currency,
-		CardNumber: strings.ReplaceAll(payment.GetCard().GetCardNumber(), " ", ""),
+		Amount:             int64(payment.Amount.MinorUnits),
+		Status:             paymentStatus,
+		Currency:           payment.Amount.Currency,
+		CardToken:          sql.NullString{String: payment.GetCard().GetToken(), Valid: payment.GetCard().GetToken() != ""},
+		CardBin:            payment.GetCard().GetBin(),
+		CardLastFour:       payment.GetCard().GetLastFour(),
+		CardBrand:          payment.GetCard().GetBrand(),
+		MerchantID:         merchantFromContext(ctx),
+		SettlementCurrency: sql.NullString{String: payment.SettlementAmount.GetCurrency(), Valid: payment.SettlementAmount != nil},
+		SettlementAmount:   sql.NullInt64{Int64: int64(payment.SettlementAmount.GetMinorUnits()), Valid: payment.SettlementAmount != nil},
+		FXRate:             sql.NullString{String: payment.FxRate, Valid: payment.SettlementAmount != nil},
 	})
 	if err != nil {
 		return err
@@ -114,6 +327,9 @@ func (r Store) CreatePayment(ctx context.Context, payment *paymentsV1.Payment) e
 	}
 	payment.Id = id
 	payment.CreatedAt = timestamppb.New(createdAt)
+	if merchantID := merchantFromContext(ctx); merchantID.Valid {
+		payment.MerchantId = merchantID.UUID.String()
+	}
 	return nil
 }
 
@@ -122,14 +338,19 @@ func (r Store) CreatePaymentAction(ctx context.Context, action *paymentsV1.Payme
 	if err := paymentType.FromProto(action.PaymentType); err != nil {
 		return err
 	}
+	// the payment is selected rather than referenced directly so actions cannot be made towards other merchants' payments
 	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
-		INSERT INTO payment_action (amount, payment_type,payment_id)
-		VALUES(:amount,:payment_type,:payment_id)
+		INSERT INTO payment_action (amount, payment_type, payment_id, settlement_amount)
+		SELECT CAST(:amount AS int), CAST(:payment_type AS payment_type), id, CAST(:settlement_amount AS bigint)
+		FROM payment
+		WHERE id = :payment_id AND (CAST(:merchant_id AS uuid) IS NULL OR merchant_id = CAST(:merchant_id AS uuid))
 		RETURNING id,created_at
-		`, &domain.PaymentAction{
-		Amount:      int64(action.Amount),
-		PaymentType: paymentType,
-		PaymentID:   uuid.FromStringOrNil(action.PaymentId),
+		`, map[string]interface{}{
+		"amount":            int64(action.Amount),
+		"payment_type":      paymentType,
+		"payment_id":        uuid.FromStringOrNil(action.PaymentId),
+		"merchant_id":       merchantFromContext(ctx),
+		"settlement_amount": sql.NullInt64{Int64: int64(action.SettlementAmount), Valid: action.SettlementAmount != 0},
 	})
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -140,8 +361,10 @@ func (r Store) CreatePaymentAction(ctx context.Context, action *paymentsV1.Payme
 		return err
 	}
 
+	defer rows.Close()
+
 	if !rows.Next() {
-		return errors.New("row unaffected")
+		return domain.ErrNoPaymentForAction
 	}
 	var (
 		id        uuid.UUID
@@ -162,7 +385,16 @@ func (r Store) UpdatePayment(ctx context.Context, payment *paymentsV1.Payment, f
 		return err
 	}
 
-	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `UPDATE payment SET status=$1,updated_at=now() where id=$2`, paymentStatus, payment.Id)
+	query := "UPDATE payment SET status=$1,updated_at=now()"
+	args := []interface{}{paymentStatus, payment.Id, merchantFromContext(ctx)}
+	for _, field := range fields {
+		if field == domain.UpdatePaymentFieldExpiresAt {
+			query += ",expires_at=$4"
+			args = append(args, sql.NullTime{Time: payment.ExpiresAt.AsTime(), Valid: payment.ExpiresAt != nil})
+		}
+	}
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx,
+		query+" WHERE id=$2 AND ($3::uuid IS NULL OR merchant_id=$3)", args...)
 	if err != nil {
 		return err
 	}
@@ -175,7 +407,16 @@ func (r Store) UpdatePayment(ctx context.Context, payment *paymentsV1.Payment, f
 
 // TODO(Jack): Update these to use dynamic update statements
 func (r Store) UpdatePaymentAction(ctx context.Context, action *paymentsV1.PaymentAction, fields ...domain.UpdatePaymentActionField) error {
-	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `UPDATE payment_action SET response_code=$1, processed_at=now() where id=$2`, action.ResponseCode, action.Id)
+	var category domain.ResponseCategory
+	responseCategory := sql.NullString{}
+	if err := category.FromProto(action.ResponseCategory); err == nil {
+		responseCategory = sql.NullString{String: string(category), Valid: true}
+	}
+	acquirer := sql.NullString{String: action.Acquirer, Valid: action.Acquirer != ""}
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `
+		UPDATE payment_action SET response_code=$1, response_category=$2, acquirer=$3, processed_at=now()
+		WHERE id=$4 AND ($5::uuid IS NULL OR payment_id IN (SELECT id FROM payment WHERE merchant_id=$5))`,
+		action.ResponseCode, responseCategory, acquirer, action.Id, merchantFromContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -10,7 +10,10 @@ import (
 	uuid "github.com/kevinburke/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestStore_CreatePaymentAction(t *testing.T) {
@@ -34,7 +37,7 @@ func TestStore_CreatePaymentAction(t *testing.T) {
 					Currency:   "GBP",
 				},
 				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-				PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+				PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
 			}
 		)
 
@@ -50,6 +53,74 @@ func TestStore_CreatePaymentAction(t *testing.T) {
 	})
 }
 
+func TestStore_PaymentSettlement(t *testing.T) {
+	t.Parallel()
+
+	payment := &paymentsV1.Payment{
+		Amount:           &amountV1.Money{MinorUnits: 10000, Currency: "EUR", MajorUnits: "100.00"},
+		SettlementAmount: &amountV1.Money{MinorUnits: 8563, Currency: "GBP", MajorUnits: "85.63"},
+		FxRate:           "0.8563",
+		PaymentStatus:    paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+		PaymentMethod:    &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+	}
+	require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+	action := &paymentsV1.PaymentAction{
+		Amount:           10000,
+		SettlementAmount: 8563,
+		PaymentType:      paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+		PaymentId:        payment.Id,
+	}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), action))
+
+	p, err := testStore.GetPayment(context.Background(), payment.Id)
+	require.NoError(t, err)
+	assert.Equal(t, payment.SettlementAmount, p.SettlementAmount)
+	assert.Equal(t, payment.FxRate, p.FxRate)
+
+	actions, err := testStore.ListPaymentActions(context.Background(), &domain.ListPaymentActionFilters{PaymentIDs: []string{payment.Id}})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, uint64(8563), actions[0].SettlementAmount)
+}
+
+func TestStore_AuthorizationExpiry(t *testing.T) {
+	t.Parallel()
+
+	payment := &paymentsV1.Payment{
+		Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+		PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+		PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+	}
+	require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+	payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED
+	payment.ExpiresAt = timestamppb.New(time.Now().Add(-time.Minute).Truncate(time.Microsecond))
+	require.NoError(t, testStore.UpdatePayment(context.Background(), payment,
+		domain.UpdatePaymentFieldStatus, domain.UpdatePaymentFieldExpiresAt))
+
+	p, err := testStore.GetPayment(context.Background(), payment.Id)
+	require.NoError(t, err)
+	assert.True(t, payment.ExpiresAt.AsTime().Equal(p.ExpiresAt.AsTime()))
+
+	expired, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+		Statuses:      []domain.PaymentStatus{domain.PaymentStatusAuthorized},
+		ExpiresBefore: time.Now(),
+	})
+	require.NoError(t, err)
+	var ids []string
+	for _, e := range expired {
+		ids = append(ids, e.Id)
+	}
+	assert.Contains(t, ids, payment.Id)
+
+	payment.PaymentStatus = paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED
+	require.NoError(t, testStore.UpdatePayment(context.Background(), payment, domain.UpdatePaymentFieldStatus))
+	p, err = testStore.GetPayment(context.Background(), payment.Id)
+	require.NoError(t, err)
+	assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED, p.PaymentStatus)
+	assert.NotNil(t, p.ExpiresAt)
+}
+
 func TestStore_CreatePayment(t *testing.T) {
 	t.Parallel()
 
@@ -60,7 +131,7 @@ func TestStore_CreatePayment(t *testing.T) {
 				Currency:   "GBP",
 			},
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
 		}
 		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
 		assert.NotEmpty(t, payment.Id)
@@ -75,9 +146,10 @@ func TestStore_GetPayment(t *testing.T) {
 			Amount: &amountV1.Money{
 				MinorUnits: 1000,
 				Currency:   "GBP",
+				MajorUnits: "10.00",
 			},
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
 		}
 		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
 
@@ -101,7 +173,7 @@ func TestStore_ListPaymentActions(t *testing.T) {
 				Currency:   "GBP",
 			},
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
 		}
 		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
 
@@ -135,7 +207,7 @@ func TestStore_UpdatePaymentAction(t *testing.T) {
 				Currency:   "GBP",
 			},
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
 		}
 		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
 
@@ -147,6 +219,7 @@ func TestStore_UpdatePaymentAction(t *testing.T) {
 		require.NoError(t, testStore.CreatePaymentAction(context.Background(), paymentAction))
 
 		paymentAction.ResponseCode = "123"
+		paymentAction.Acquirer = "acquirer-a"
 		err := testStore.UpdatePaymentAction(context.Background(), paymentAction, domain.UpdatePaymentActionFieldResponseCode)
 		require.NoError(t, err)
 
@@ -154,6 +227,36 @@ func TestStore_UpdatePaymentAction(t *testing.T) {
 			&domain.ListPaymentActionFilters{PaymentIDs: []string{paymentAction.PaymentId}})
 		require.NoError(t, err)
 		assert.Equal(t, "123", p[0].ResponseCode)
+		assert.Equal(t, "acquirer-a", p[0].Acquirer)
+	})
+	t.Run("should store the response category and derive the decline reason", func(t *testing.T) {
+		payment := &paymentsV1.Payment{
+			Amount: &amountV1.Money{
+				MinorUnits: 1000,
+				Currency:   "GBP",
+			},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+		}
+		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+		paymentAction := &paymentsV1.PaymentAction{
+			Amount:      1000,
+			PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+			PaymentId:   payment.Id,
+		}
+		require.NoError(t, testStore.CreatePaymentAction(context.Background(), paymentAction))
+
+		paymentAction.ResponseCode = "51"
+		paymentAction.ResponseCategory = paymentsV1.ResponseCategory_RESPONSE_CATEGORY_SOFT_DECLINE
+		require.NoError(t, testStore.UpdatePaymentAction(context.Background(), paymentAction, domain.UpdatePaymentActionFieldResponseCode))
+
+		p, err := testStore.ListPaymentActions(context.Background(),
+			&domain.ListPaymentActionFilters{PaymentIDs: []string{paymentAction.PaymentId}})
+		require.NoError(t, err)
+		require.Len(t, p, 1)
+		assert.Equal(t, paymentsV1.ResponseCategory_RESPONSE_CATEGORY_SOFT_DECLINE, p[0].ResponseCategory)
+		assert.Equal(t, "insufficient funds", p[0].DeclineReason)
 	})
 }
 
@@ -166,7 +269,7 @@ func TestStore_UpdatePayment(t *testing.T) {
 				Currency:   "GBP",
 			},
 			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
-			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{CardNumber: "4000000000000119"}},
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
 		}
 		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
 
@@ -180,3 +283,240 @@ func TestStore_UpdatePayment(t *testing.T) {
 		assert.Equal(t, paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED, p.PaymentStatus)
 	})
 }
+
+func TestStore_ListPayments(t *testing.T) {
+	t.Parallel()
+
+	// a currency unique to the test keeps payments created by other tests out of the results
+	currency := strings.ToUpper(uuid.NewV4().String()[:3])
+	var created []*paymentsV1.Payment
+	for _, status := range []paymentsV1.PaymentStatus{
+		paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+		paymentsV1.PaymentStatus_PAYMENT_STATUS_DECLINED,
+		paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+	} {
+		payment := &paymentsV1.Payment{
+			Amount: &amountV1.Money{
+				MinorUnits: 1000,
+				Currency:   currency,
+			},
+			PaymentStatus: status,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+		}
+		require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+		created = append(created, payment)
+	}
+
+	t.Run("should filter payments", func(t *testing.T) {
+		payments, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+			Statuses:     []domain.PaymentStatus{domain.PaymentStatusAuthorized},
+			Currencies:   []string{currency},
+			MinAmount:    1000,
+			MaxAmount:    1000,
+			CardLastFour: "0119",
+		})
+		require.NoError(t, err)
+		require.Len(t, payments, 2)
+		assert.Equal(t, created[2].Id, payments[0].Id)
+		assert.Equal(t, created[0].Id, payments[1].Id)
+	})
+	t.Run("should paginate payments newest first", func(t *testing.T) {
+		firstPage, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+			Currencies: []string{currency},
+			Limit:      2,
+		})
+		require.NoError(t, err)
+		require.Len(t, firstPage, 2)
+		assert.Equal(t, created[2].Id, firstPage[0].Id)
+		assert.Equal(t, created[1].Id, firstPage[1].Id)
+
+		last := firstPage[1]
+		secondPage, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+			Currencies: []string{currency},
+			Limit:      2,
+			Cursor:     &domain.PaymentCursor{ID: last.Id},
+		})
+		require.NoError(t, err)
+		require.Len(t, secondPage, 1)
+		assert.Equal(t, created[0].Id, secondPage[0].Id)
+	})
+	t.Run("should return no payments given no matches", func(t *testing.T) {
+		payments, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+			Currencies:   []string{currency},
+			CardLastFour: "9999",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, payments)
+	})
+	t.Run("should page payments in the order they were inserted given a transaction started before them", func(t *testing.T) {
+		currency := strings.ToUpper(uuid.NewV4().String()[:3])
+		newPayment := func() *paymentsV1.Payment {
+			return &paymentsV1.Payment{
+				Amount:        &amountV1.Money{MinorUnits: 1000, Currency: currency},
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+				PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+			}
+		}
+		first, second, late := newPayment(), newPayment(), newPayment()
+		require.NoError(t, testStore.CreatePayment(context.Background(), first))
+		require.NoError(t, testStore.ExecInTransaction(context.Background(), func(ctx context.Context) error {
+			// created outside the transaction so it commits first whilst late is created when the transaction started
+			if err := testStore.CreatePayment(context.Background(), second); err != nil {
+				return err
+			}
+			return testStore.CreatePayment(ctx, late)
+		}))
+		require.True(t, late.CreatedAt.AsTime().Before(second.CreatedAt.AsTime()))
+
+		firstPage, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+			Currencies: []string{currency},
+			Limit:      1,
+		})
+		require.NoError(t, err)
+		require.Len(t, firstPage, 1)
+		assert.Equal(t, late.Id, firstPage[0].Id)
+
+		secondPage, err := testStore.ListPayments(context.Background(), &domain.ListPaymentFilters{
+			Currencies: []string{currency},
+			Cursor:     &domain.PaymentCursor{ID: firstPage[0].Id},
+		})
+		require.NoError(t, err)
+		require.Len(t, secondPage, 2)
+		assert.Equal(t, second.Id, secondPage[0].Id)
+		assert.Equal(t, first.Id, secondPage[1].Id)
+	})
+}
+
+func TestStore_ListPaymentActions_Filters(t *testing.T) {
+	t.Parallel()
+
+	payment := &paymentsV1.Payment{
+		Amount: &amountV1.Money{
+			MinorUnits: 1000,
+			Currency:   "GBP",
+		},
+		PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+		PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+	}
+	require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+
+	processed := &paymentsV1.PaymentAction{
+		Amount:       1000,
+		PaymentType:  paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+		PaymentId:    payment.Id,
+		ResponseCode: "00",
+	}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), processed))
+	require.NoError(t, testStore.UpdatePaymentAction(context.Background(), processed, domain.UpdatePaymentActionFieldResponseCode))
+
+	pending := &paymentsV1.PaymentAction{
+		Amount:      500,
+		PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
+		PaymentId:   payment.Id,
+	}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), pending))
+
+	t.Run("should only return unprocessed actions of the payment type", func(t *testing.T) {
+		actions, err := testStore.ListPaymentActions(context.Background(), &domain.ListPaymentActionFilters{
+			PaymentIDs:   []string{payment.Id},
+			PaymentTypes: []domain.PaymentType{domain.PaymentTypeCapture},
+			Unprocessed:  true,
+		})
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, pending.Id, actions[0].Id)
+	})
+	t.Run("should return no actions created before the given time", func(t *testing.T) {
+		actions, err := testStore.ListPaymentActions(context.Background(), &domain.ListPaymentActionFilters{
+			PaymentIDs:    []string{payment.Id},
+			CreatedBefore: processed.CreatedAt.AsTime(),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+}
+
+func TestStore_GetPaymentActionForUpdate(t *testing.T) {
+	t.Parallel()
+
+	payment := &paymentsV1.Payment{
+		Amount: &amountV1.Money{
+			MinorUnits: 1000,
+			Currency:   "GBP",
+		},
+		PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+		PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+	}
+	require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+	action := &paymentsV1.PaymentAction{
+		Amount:      1000,
+		PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+		PaymentId:   payment.Id,
+	}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), action))
+
+	t.Run("should skip an action locked by another transaction", func(t *testing.T) {
+		require.NoError(t, testStore.ExecInTransaction(context.Background(), func(ctx context.Context) error {
+			locked, err := testStore.GetPaymentActionForUpdate(ctx, action.Id)
+			require.NoError(t, err)
+			assert.Equal(t, action.Id, locked.Id)
+
+			return testStore.ExecInTransaction(context.Background(), func(ctx context.Context) error {
+				_, err := testStore.GetPaymentActionForUpdate(ctx, action.Id)
+				assert.Equal(t, domain.ErrNoPaymentAction, err)
+				return nil
+			})
+		}))
+	})
+	t.Run("should return error for unknown payment action", func(t *testing.T) {
+		require.NoError(t, testStore.ExecInTransaction(context.Background(), func(ctx context.Context) error {
+			_, err := testStore.GetPaymentActionForUpdate(ctx, uuid.NewV4().String())
+			assert.Equal(t, domain.ErrNoPaymentAction, err)
+			return nil
+		}))
+	})
+}
+
+func TestStore_ClaimPaymentAction(t *testing.T) {
+	t.Parallel()
+
+	payment := &paymentsV1.Payment{
+		Amount: &amountV1.Money{
+			MinorUnits: 1000,
+			Currency:   "GBP",
+		},
+		PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+		PaymentMethod: &paymentsV1.Payment_Card{Card: &paymentsV1.PaymentMethodCard{Bin: "400000", LastFour: "0119"}},
+	}
+	require.NoError(t, testStore.CreatePayment(context.Background(), payment))
+	action := &paymentsV1.PaymentAction{
+		Amount:      1000,
+		PaymentType: paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+		PaymentId:   payment.Id,
+	}
+	require.NoError(t, testStore.CreatePaymentAction(context.Background(), action))
+
+	unclaimed := func() []*paymentsV1.PaymentAction {
+		actions, err := testStore.ListPaymentActions(context.Background(), &domain.ListPaymentActionFilters{
+			PaymentIDs: []string{payment.Id},
+			Unclaimed:  true,
+		})
+		require.NoError(t, err)
+		return actions
+	}
+	require.Len(t, unclaimed(), 1)
+
+	// claimed some time after it was created
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, testStore.ClaimPaymentAction(context.Background(), action.Id))
+	assert.Empty(t, unclaimed())
+	assert.Equal(t, domain.ErrPaymentActionClaimed, testStore.ClaimPaymentAction(context.Background(), action.Id))
+
+	// a claimed action is sent when claimed rather than when created
+	actions, err := testStore.ListPaymentActions(context.Background(), &domain.ListPaymentActionFilters{
+		PaymentIDs:    []string{payment.Id},
+		CreatedBefore: action.CreatedAt.AsTime().Add(time.Millisecond),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, actions)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"github.com/jacktantram/payments-api/pkg/driver/v1/postgres"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
 	"github.com/jmoiron/sqlx"
+	uuid "github.com/kevinburke/go.uuid"
 )
 
 type Store struct {
@@ -13,7 +15,7 @@ type Store struct {
 
 type conn interface {
 	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
-	Queryx(query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
 	NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error)
 	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
@@ -46,6 +48,16 @@ func (r Store) ExecInTransaction(ctx context.Context, fn func(ctx context.Contex
 	return nil
 }
 
+// merchantFromContext returns the merchant queries are scoped to. It is null for the gateway's own calls, which see
+// every merchant's data.
+func merchantFromContext(ctx context.Context) uuid.NullUUID {
+	merchantID, ok := domain.MerchantFromContext(ctx)
+	if !ok {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: uuid.FromStringOrNil(merchantID), Valid: true}
+}
+
 type connKey struct{}
 
 func (r Store) connFromContext(ctx context.Context) conn {
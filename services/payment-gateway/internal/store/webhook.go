@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jmoiron/sqlx"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// CreateWebhookEndpoint registers the endpoint for the merchant the context is scoped to.
+func (r Store) CreateWebhookEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	merchantID := merchantFromContext(ctx)
+	if !merchantID.Valid {
+		return domain.ErrNoMerchant
+	}
+	endpoint.MerchantID = merchantID.UUID
+	rows, err := r.connFromContext(ctx).NamedQueryContext(ctx, `
+		INSERT INTO webhook_endpoint (merchant_id, url, secret)
+		VALUES(:merchant_id,:url,:secret)
+		RETURNING id, created_at
+		`, endpoint)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return errors.New("row unaffected")
+	}
+	if err = rows.Scan(&endpoint.ID, &endpoint.CreatedAt); err != nil {
+		return errors.Wrap(err, "unable to scan row")
+	}
+	return nil
+}
+
+func (r Store) GetWebhookEndpoint(ctx context.Context, id string) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx,
+		"SELECT * FROM webhook_endpoint WHERE id=$1 AND ($2::uuid IS NULL OR merchant_id=$2)",
+		uuid.FromStringOrNil(id), merchantFromContext(ctx)).StructScan(&endpoint); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoWebhookEndpoint
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// ListWebhookEndpoints returns the enabled endpoints oldest first.
+func (r Store) ListWebhookEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	rows, err := r.connFromContext(ctx).QueryxContext(ctx, `
+		SELECT * FROM webhook_endpoint WHERE disabled_at IS NULL AND ($1::uuid IS NULL OR merchant_id=$1)
+		ORDER BY created_at`, merchantFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	endpoints := make([]*domain.WebhookEndpoint, 0)
+	for rows.Next() {
+		var endpoint domain.WebhookEndpoint
+		if err := rows.StructScan(&endpoint); err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, &endpoint)
+	}
+	return endpoints, rows.Err()
+}
+
+// DisableWebhookEndpoint stops notifications being sent to the endpoint, its deliveries are kept.
+func (r Store) DisableWebhookEndpoint(ctx context.Context, id string) error {
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `
+		UPDATE webhook_endpoint SET disabled_at=now()
+		WHERE id=$1 AND disabled_at IS NULL AND ($2::uuid IS NULL OR merchant_id=$2)`,
+		uuid.FromStringOrNil(id), merchantFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	affected, err := execContext.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNoWebhookEndpoint
+	}
+	return nil
+}
+
+// CreateWebhookDeliveries queues the payload to every enabled endpoint of the merchant owning the event's payment.
+// An event already queued to an endpoint is not queued again, it returns how many deliveries were queued.
+func (r Store) CreateWebhookDeliveries(ctx context.Context, event *domain.OutboxEvent, payload []byte) (int64, error) {
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `
+		INSERT INTO webhook_delivery (endpoint_id, event_id, payment_id, event_type, payload)
+		SELECT e.id, $1, p.id, $3, $4 FROM payment p
+		JOIN webhook_endpoint e ON e.merchant_id = p.merchant_id AND e.disabled_at IS NULL
+		WHERE p.id = $2
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+		event.ID, event.PaymentID, event.EventType, payload)
+	if err != nil {
+		return 0, err
+	}
+	return execContext.RowsAffected()
+}
+
+// ListWebhookDeliveries returns the deliveries matching the filters newest first.
+func (r Store) ListWebhookDeliveries(ctx context.Context, filters *domain.ListWebhookDeliveryFilters) ([]*domain.WebhookDelivery, error) {
+	var (
+		conditions []string
+		arg        = map[string]interface{}{}
+	)
+	if merchantID := merchantFromContext(ctx); merchantID.Valid {
+		conditions = append(conditions, "endpoint_id IN (SELECT id FROM webhook_endpoint WHERE merchant_id = :merchant_id)")
+		arg["merchant_id"] = merchantID
+	}
+	if filters.EndpointID != "" {
+		conditions = append(conditions, "endpoint_id = :endpoint_id")
+		arg["endpoint_id"] = uuid.FromStringOrNil(filters.EndpointID)
+	}
+	if filters.PaymentID != "" {
+		conditions = append(conditions, "payment_id = :payment_id")
+		arg["payment_id"] = uuid.FromStringOrNil(filters.PaymentID)
+	}
+
+	query := "SELECT * FROM webhook_delivery"
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC"
+	if filters.Limit != 0 {
+		query += " LIMIT :limit"
+		arg["limit"] = filters.Limit
+	}
+
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return r.queryWebhookDeliveries(ctx, r.db.DB.Rebind(query), args...)
+}
+
+// ListDueWebhookDeliveries returns the oldest pending deliveries whose next attempt is due.
+func (r Store) ListDueWebhookDeliveries(ctx context.Context, limit uint64) ([]*domain.WebhookDelivery, error) {
+	return r.queryWebhookDeliveries(ctx, `
+		SELECT * FROM webhook_delivery WHERE status = 'PENDING' AND next_attempt_at <= now()
+		ORDER BY next_attempt_at LIMIT $1`, limit)
+}
+
+func (r Store) queryWebhookDeliveries(ctx context.Context, query string, args ...interface{}) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.connFromContext(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.StructScan(&delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// GetWebhookDeliveryForUpdate returns the delivery locking it until the transaction ends. If the delivery is already
+// locked by another transaction domain.ErrNoWebhookDelivery is returned rather than waiting. It must be called within
+// ExecInTransaction.
+func (r Store) GetWebhookDeliveryForUpdate(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := r.connFromContext(ctx).QueryRowxContext(ctx, `
+		SELECT * FROM webhook_delivery WHERE id=$1
+		AND ($2::uuid IS NULL OR endpoint_id IN (SELECT id FROM webhook_endpoint WHERE merchant_id=$2))
+		FOR UPDATE SKIP LOCKED`, uuid.FromStringOrNil(id), merchantFromContext(ctx)).StructScan(&delivery); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNoWebhookDelivery
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateWebhookDelivery records the outcome of an attempt to deliver.
+func (r Store) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	execContext, err := r.connFromContext(ctx).ExecContext(ctx, `
+		UPDATE webhook_delivery SET status=$1, attempts=$2, next_attempt_at=$3, last_response_status=$4,
+		last_error=$5, delivered_at=$6
+		WHERE id=$7 AND ($8::uuid IS NULL OR endpoint_id IN (SELECT id FROM webhook_endpoint WHERE merchant_id=$8))`,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastResponseStatus, delivery.LastError,
+		delivery.DeliveredAt, delivery.ID, merchantFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	affected, err := execContext.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrNoWebhookDelivery
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:generate mockgen -source=auth.go -destination=mocks/mock_auth.go -package=mocks
+package transportgrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, apiKey string) (string, error)
+}
+
+// AuthInterceptor authenticates the merchant by the api key sent as a bearer token in the authorization metadata,
+// scoping the call to them.
+func AuthInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		const prefix = "bearer "
+		var apiKey string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) != 0 && strings.HasPrefix(strings.ToLower(values[0]), prefix) {
+				apiKey = strings.TrimSpace(values[0][len(prefix):])
+			}
+		}
+		if apiKey == "" {
+			return nil, problemStatus(domain.ProblemOf(domain.ErrNoAPIKey, ""))
+		}
+		merchantID, err := authenticator.Authenticate(ctx, apiKey)
+		if err != nil {
+			if errors.Is(err, domain.ErrNoAPIKey) {
+				return nil, problemStatus(domain.ProblemOf(domain.ErrNoAPIKey, ""))
+			}
+			log.WithError(err).WithField("method", info.FullMethod).Error("failed to authenticate merchant")
+			return nil, problemStatus(domain.ProblemInternal)
+		}
+		return handler(domain.ContextWithMerchant(ctx, merchantID), req)
+	}
+}
@@ -0,0 +1,79 @@
+package transportgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transportgrpc"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transportgrpc/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description   string
+		authorization string
+		fn            func(m *mocks.MockAuthenticator)
+		expCode       codes.Code
+	}{
+		{
+			description:   "should scope the call to the authenticated merchant",
+			authorization: "Bearer sk_valid",
+			fn: func(m *mocks.MockAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "sk_valid").Return("merchant-id", nil)
+			},
+			expCode: codes.OK,
+		},
+		{
+			description: "should return unauthenticated given no api key",
+			expCode:     codes.Unauthenticated,
+		},
+		{
+			description:   "should return unauthenticated given an unknown api key",
+			authorization: "Bearer sk_unknown",
+			fn: func(m *mocks.MockAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "sk_unknown").Return("", domain.ErrNoAPIKey)
+			},
+			expCode: codes.Unauthenticated,
+		},
+		{
+			description:   "should return internal given authentication fails",
+			authorization: "Bearer sk_valid",
+			fn: func(m *mocks.MockAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "sk_valid").Return("", errors.New("boom"))
+			},
+			expCode: codes.Internal,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			authenticator := mocks.NewMockAuthenticator(gomock.NewController(t))
+			if tc.fn != nil {
+				tc.fn(authenticator)
+			}
+			ctx := context.Background()
+			if tc.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tc.authorization))
+			}
+
+			_, err := transportgrpc.AuthInterceptor(authenticator)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					merchantID, ok := domain.MerchantFromContext(ctx)
+					require.True(t, ok)
+					assert.Equal(t, "merchant-id", merchantID)
+					return nil, nil
+				})
+			assert.Equal(t, tc.expCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,242 @@
+//go:generate mockgen -source=server.go -destination=mocks/mock_gateway.go -package=mocks
+package transportgrpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	gatewayV1 "github.com/jacktantram/payments-api/build/go/services/paymentgateway/v1"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/validation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+)
+
+type Gateway interface {
+	CreatePayment(ctx context.Context, amount *amountV1.Money, settlementCurrency string, method domain.PaymentMethod) (*paymentsV1.Payment, error)
+	Capture(ctx context.Context, paymentID string, amount uint64, final bool) (*paymentsV1.Payment, error)
+	Refund(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	Void(ctx context.Context, paymentID string) (*paymentsV1.Payment, error)
+	IncrementAuthorization(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	Reverse(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	GetPayment(ctx context.Context, paymentID string) (*paymentsV1.PaymentDetails, error)
+}
+
+// Server serves the PaymentGatewayService over gRPC.
+type Server struct {
+	gatewayV1.UnimplementedPaymentGatewayServiceServer
+	gateway Gateway
+}
+
+func NewServer(gateway Gateway) (*Server, error) {
+	if gateway == nil {
+		return nil, errors.New("gateway client is nil")
+	}
+	return &Server{gateway: gateway}, nil
+}
+
+func (s *Server) Authorize(ctx context.Context, req *gatewayV1.AuthorizeRequest) (*gatewayV1.AuthorizeResponse, error) {
+	if err := validateAuthorize(req); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	payment, err := s.gateway.CreatePayment(ctx, req.Amount, req.SettlementCurrency, domain.PaymentMethod{Card: req.Card})
+	if err != nil {
+		return nil, toStatus(err, "authorization", log.Fields{
+			"amount.minor_units": req.Amount.MinorUnits,
+			"amount.currency":    req.Amount.Currency,
+			"amount":             currencyV1.String(req.Amount),
+			"method":             "Authorize",
+		})
+	}
+	return &gatewayV1.AuthorizeResponse{Payment: payment}, nil
+}
+
+func (s *Server) Capture(ctx context.Context, req *gatewayV1.CaptureRequest) (*gatewayV1.CaptureResponse, error) {
+	if err := validatePaymentAmount(req.PaymentId, req.Amount); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	payment, err := s.gateway.Capture(ctx, req.PaymentId, req.Amount, req.Final)
+	if err != nil {
+		return nil, toStatus(err, "capture", log.Fields{
+			"payment.id": req.PaymentId,
+			"amount":     req.Amount,
+			"method":     "Capture",
+		})
+	}
+	return &gatewayV1.CaptureResponse{Payment: payment}, nil
+}
+
+func (s *Server) Refund(ctx context.Context, req *gatewayV1.RefundRequest) (*gatewayV1.RefundResponse, error) {
+	if err := validatePaymentAmount(req.PaymentId, req.Amount); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	payment, err := s.gateway.Refund(ctx, req.PaymentId, req.Amount)
+	if err != nil {
+		return nil, toStatus(err, "refund", log.Fields{
+			"payment.id": req.PaymentId,
+			"amount":     req.Amount,
+			"method":     "Refund",
+		})
+	}
+	return &gatewayV1.RefundResponse{Payment: payment}, nil
+}
+
+func (s *Server) Void(ctx context.Context, req *gatewayV1.VoidRequest) (*gatewayV1.VoidResponse, error) {
+	if err := validation.Validate(paymentIDField(req.PaymentId)); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	payment, err := s.gateway.Void(ctx, req.PaymentId)
+	if err != nil {
+		return nil, toStatus(err, "void", log.Fields{
+			"payment.id": req.PaymentId,
+			"method":     "Void",
+		})
+	}
+	return &gatewayV1.VoidResponse{Payment: payment}, nil
+}
+
+func (s *Server) IncrementAuthorization(ctx context.Context, req *gatewayV1.IncrementAuthorizationRequest) (*gatewayV1.IncrementAuthorizationResponse, error) {
+	if err := validatePaymentAmount(req.PaymentId, req.Amount); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	payment, err := s.gateway.IncrementAuthorization(ctx, req.PaymentId, req.Amount)
+	if err != nil {
+		return nil, toStatus(err, "incremental authorization", log.Fields{
+			"payment.id": req.PaymentId,
+			"amount":     req.Amount,
+			"method":     "IncrementAuthorization",
+		})
+	}
+	return &gatewayV1.IncrementAuthorizationResponse{Payment: payment}, nil
+}
+
+func (s *Server) Reverse(ctx context.Context, req *gatewayV1.ReverseRequest) (*gatewayV1.ReverseResponse, error) {
+	if err := validation.Validate(paymentIDField(req.PaymentId)); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	payment, err := s.gateway.Reverse(ctx, req.PaymentId, req.Amount)
+	if err != nil {
+		return nil, toStatus(err, "reversal", log.Fields{
+			"payment.id": req.PaymentId,
+			"amount":     req.Amount,
+			"method":     "Reverse",
+		})
+	}
+	return &gatewayV1.ReverseResponse{Payment: payment}, nil
+}
+
+func (s *Server) GetPayment(ctx context.Context, req *gatewayV1.GetPaymentRequest) (*gatewayV1.GetPaymentResponse, error) {
+	if err := validation.Validate(paymentIDField(req.PaymentId)); err != nil {
+		return nil, problemStatus(domain.ProblemOf(err, ""))
+	}
+
+	details, err := s.gateway.GetPayment(ctx, req.PaymentId)
+	if err != nil {
+		return nil, toStatus(err, "get payment", log.Fields{
+			"payment.id": req.PaymentId,
+			"method":     "GetPayment",
+		})
+	}
+	return &gatewayV1.GetPaymentResponse{PaymentDetails: details}, nil
+}
+
+func validateAuthorize(req *gatewayV1.AuthorizeRequest) error {
+	fields := []validation.FieldRules{
+		validation.Field("amount", validation.Check(req.Amount != nil, "cannot be missing")),
+		validation.Field("amount.minor_units", validation.NotZero(req.Amount.GetMinorUnits())).When(req.Amount != nil),
+		validation.Field("amount.currency", validation.Currency(req.Amount.GetCurrency())).When(req.Amount != nil),
+		validation.Field("settlement_currency", validation.Currency(req.SettlementCurrency)).When(req.SettlementCurrency != ""),
+	}
+	return validation.Validate(append(fields, validation.Card("card", req.Card, time.Now())...)...)
+}
+
+func validatePaymentAmount(paymentID string, amount uint64) error {
+	return validation.Validate(paymentIDField(paymentID), validation.Field("amount", validation.NotZero(amount)))
+}
+
+func paymentIDField(paymentID string) validation.FieldRules {
+	return validation.Field("payment_id", validation.Check(paymentID != "", "cannot be empty"), validation.UUID(paymentID))
+}
+
+// ErrorDomain is the domain of the ErrorInfo detail attached to every error status.
+const ErrorDomain = "payments-api"
+
+// statusCode returns the gRPC code an error code is reported with.
+func statusCode(code domain.ErrorCode) codes.Code {
+	switch code {
+	case domain.ErrorCodeInvalidRequest, domain.ErrorCodeValidationFailed, domain.ErrorCodeUnknownCardToken,
+		domain.ErrorCodeCardBrandNotAccepted, domain.ErrorCodeFXRateUnavailable, domain.ErrorCodeIdempotencyKeyMismatch:
+		return codes.InvalidArgument
+	case domain.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+	case domain.ErrorCodePaymentNotFound, domain.ErrorCodeMerchantNotFound, domain.ErrorCodeWebhookEndpointNotFound,
+		domain.ErrorCodeWebhookDeliveryNotFound:
+		return codes.NotFound
+	case domain.ErrorCodeOperationNotPermitted, domain.ErrorCodeAuthorizationExpired, domain.ErrorCodePaymentDeclined:
+		return codes.FailedPrecondition
+	case domain.ErrorCodeIssuerUnavailable:
+		return codes.Unavailable
+	case domain.ErrorCodeIdempotencyKeyInUse:
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+// problemStatus returns the status reporting the problem, its code and any field or decline reason are attached
+// as an ErrorInfo detail and every field failing validation as a BadRequest detail.
+func problemStatus(problem domain.Problem) error {
+	// the card is carried at the top level of the gRPC requests rather than within a payment method
+	problem.Field = strings.TrimPrefix(problem.Field, "payment_method.")
+	problem.Message = strings.Replace(problem.Message, "payment_method.", "", 1)
+
+	metadata := map[string]string{}
+	if problem.Field != "" {
+		metadata["field"] = problem.Field
+	}
+	if problem.DeclineReason != "" {
+		metadata["decline_reason"] = problem.DeclineReason
+	}
+	details := []protoiface.MessageV1{&errdetails.ErrorInfo{Reason: string(problem.Code), Domain: ErrorDomain, Metadata: metadata}}
+	if len(problem.Errors) != 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, validationErr := range problem.Errors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       validationErr.Field,
+				Description: validationErr.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	st := status.New(statusCode(problem.Code), problem.Message)
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// toStatus maps the error returned by the gateway to a gRPC status, unexpected errors are logged
+// and hidden from the caller.
+func toStatus(err error, operation string, logFields log.Fields) error {
+	problem := domain.ProblemOf(err, operation)
+	if problem.Code == domain.ErrorCodeInternal {
+		logFields["error"] = err
+		log.WithFields(logFields).Errorf("failed to process %s request", operation)
+	}
+	return problemStatus(problem)
+}
@@ -0,0 +1,445 @@
+package transportgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gatewayV1 "github.com/jacktantram/payments-api/build/go/services/paymentgateway/v1"
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transportgrpc"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transportgrpc/mocks"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPaymentID = "a6921fc3-a7e3-4661-909b-b3c6c77837ce"
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+	_, err := transportgrpc.NewServer(nil)
+	require.Error(t, err)
+}
+
+func TestServer_Authorize(t *testing.T) {
+	t.Parallel()
+	var (
+		card = &paymentsV1.PaymentMethodCard{
+			CardNumber: "4000000000000119",
+			Expiry: &paymentsV1.PaymentMethodCard_ExpiryDate{
+				Month: validation.ExpiryMonthMax,
+				Year:  uint32(time.Now().Year() + 1),
+			},
+			Cvv: "123",
+		}
+		amount  = &amountV1.Money{MinorUnits: 3020, Currency: "GBP"}
+		payment = &paymentsV1.Payment{Id: testPaymentID, Amount: amount}
+	)
+
+	for _, tc := range []struct {
+		description string
+		request     *gatewayV1.AuthorizeRequest
+		expCode     codes.Code
+		fn          func(m *mocks.MockGateway)
+	}{
+		{
+			description: "should return invalid argument given the amount is missing",
+			request:     &gatewayV1.AuthorizeRequest{Card: card},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return invalid argument given the currency is not a valid length",
+			request:     &gatewayV1.AuthorizeRequest{Card: card, Amount: &amountV1.Money{MinorUnits: 10, Currency: "GB"}},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return invalid argument given the currency is not an ISO 4217 currency",
+			request:     &gatewayV1.AuthorizeRequest{Card: card, Amount: &amountV1.Money{MinorUnits: 10, Currency: "XYZ"}},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return invalid argument given the card number is invalid",
+			request: &gatewayV1.AuthorizeRequest{Amount: amount, Card: &paymentsV1.PaymentMethodCard{
+				CardNumber: "4000000000000118",
+				Expiry:     card.Expiry,
+				Cvv:        card.Cvv,
+			}},
+			expCode: codes.InvalidArgument,
+		},
+		{
+			description: "should return invalid argument given the token is unknown",
+			request:     &gatewayV1.AuthorizeRequest{Amount: amount, Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}},
+			expCode:     codes.InvalidArgument,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().CreatePayment(gomock.Any(), amount, "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}}).
+					Return(nil, domain.ErrNoCardToken)
+			},
+		},
+		{
+			description: "should return invalid argument given the settlement currency is not an ISO 4217 currency",
+			request:     &gatewayV1.AuthorizeRequest{Amount: amount, Card: card, SettlementCurrency: "XYZ"},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return invalid argument given there is no fx rate to the settlement currency",
+			request:     &gatewayV1.AuthorizeRequest{Amount: amount, Card: card, SettlementCurrency: "JPY"},
+			expCode:     codes.InvalidArgument,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().CreatePayment(gomock.Any(), amount, "JPY", domain.PaymentMethod{Card: card}).
+					Return(nil, domain.ErrNoFXRate)
+			},
+		},
+		{
+			description: "should return internal given the gateway fails",
+			request:     &gatewayV1.AuthorizeRequest{Amount: amount, Card: card},
+			expCode:     codes.Internal,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().CreatePayment(gomock.Any(), amount, "", domain.PaymentMethod{Card: card}).
+					Return(nil, errors.New("boom"))
+			},
+		},
+		{
+			description: "should return the payment given the authorization succeeds",
+			request:     &gatewayV1.AuthorizeRequest{Amount: amount, Card: card},
+			expCode:     codes.OK,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().CreatePayment(gomock.Any(), amount, "", domain.PaymentMethod{Card: card}).
+					Return(payment, nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			if tc.fn != nil {
+				tc.fn(m)
+			}
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			resp, err := s.Authorize(context.Background(), tc.request)
+			assert.Equal(t, tc.expCode, status.Code(err))
+			if tc.expCode == codes.OK {
+				assert.Equal(t, payment, resp.Payment)
+			}
+		})
+	}
+}
+
+func TestServer_Capture(t *testing.T) {
+	t.Parallel()
+	payment := &paymentsV1.Payment{Id: testPaymentID}
+
+	for _, tc := range []struct {
+		description string
+		request     *gatewayV1.CaptureRequest
+		expCode     codes.Code
+		fn          func(m *mocks.MockGateway)
+	}{
+		{
+			description: "should return invalid argument given the payment id is empty",
+			request:     &gatewayV1.CaptureRequest{Amount: 10},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return invalid argument given the amount is zero",
+			request:     &gatewayV1.CaptureRequest{PaymentId: testPaymentID},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return not found given the payment does not exist",
+			request:     &gatewayV1.CaptureRequest{PaymentId: testPaymentID, Amount: 10},
+			expCode:     codes.NotFound,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().Capture(gomock.Any(), testPaymentID, uint64(10), false).Return(nil, domain.ErrNoPayment)
+			},
+		},
+		{
+			description: "should return failed precondition given the capture is not permitted",
+			request:     &gatewayV1.CaptureRequest{PaymentId: testPaymentID, Amount: 10},
+			expCode:     codes.FailedPrecondition,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().Capture(gomock.Any(), testPaymentID, uint64(10), false).Return(nil, domain.TransitionError{
+					PaymentType: domain.PaymentTypeCapture,
+					From:        domain.PaymentStatusVoided,
+				})
+			},
+		},
+		{
+			description: "should return failed precondition given the authorization has expired",
+			request:     &gatewayV1.CaptureRequest{PaymentId: testPaymentID, Amount: 10},
+			expCode:     codes.FailedPrecondition,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().Capture(gomock.Any(), testPaymentID, uint64(10), false).Return(nil, domain.ErrAuthorizationExpired)
+			},
+		},
+		{
+			description: "should return the payment given the capture succeeds",
+			request:     &gatewayV1.CaptureRequest{PaymentId: testPaymentID, Amount: 10},
+			expCode:     codes.OK,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().Capture(gomock.Any(), testPaymentID, uint64(10), false).Return(payment, nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			if tc.fn != nil {
+				tc.fn(m)
+			}
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			resp, err := s.Capture(context.Background(), tc.request)
+			assert.Equal(t, tc.expCode, status.Code(err))
+			if tc.expCode == codes.OK {
+				assert.Equal(t, payment, resp.Payment)
+			}
+		})
+	}
+}
+
+func TestServer_Refund(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		err         error
+		expCode     codes.Code
+	}{
+		{description: "should return not found given the payment does not exist", err: domain.ErrNoPayment, expCode: codes.NotFound},
+		{description: "should return failed precondition given the refund is not permitted", err: domain.ErrNotPermitted, expCode: codes.FailedPrecondition},
+		{description: "should return unavailable given the issuer is unavailable", err: domain.ErrIssuerUnavailable, expCode: codes.Unavailable},
+		{description: "should return internal given an unexpected error", err: errors.New("boom"), expCode: codes.Internal},
+		{description: "should succeed given the refund succeeds", expCode: codes.OK},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			m.EXPECT().Refund(gomock.Any(), testPaymentID, uint64(10)).Return(&paymentsV1.Payment{Id: testPaymentID}, tc.err)
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			_, err = s.Refund(context.Background(), &gatewayV1.RefundRequest{PaymentId: testPaymentID, Amount: 10})
+			assert.Equal(t, tc.expCode, status.Code(err))
+		})
+	}
+}
+
+func TestServer_IncrementAuthorization(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		err         error
+		expCode     codes.Code
+	}{
+		{description: "should return not found given the payment does not exist", err: domain.ErrNoPayment, expCode: codes.NotFound},
+		{description: "should return failed precondition given the authorization has expired", err: domain.ErrAuthorizationExpired, expCode: codes.FailedPrecondition},
+		{description: "should return failed precondition given the increment is not permitted", err: domain.ErrNotPermitted, expCode: codes.FailedPrecondition},
+		{description: "should return the payment given the increment succeeds", expCode: codes.OK},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			m.EXPECT().IncrementAuthorization(gomock.Any(), testPaymentID, uint64(10)).Return(&paymentsV1.Payment{Id: testPaymentID}, tc.err)
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			_, err = s.IncrementAuthorization(context.Background(), &gatewayV1.IncrementAuthorizationRequest{PaymentId: testPaymentID, Amount: 10})
+			assert.Equal(t, tc.expCode, status.Code(err))
+		})
+	}
+}
+
+func TestServer_Reverse(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		err         error
+		expCode     codes.Code
+	}{
+		{description: "should return not found given the payment does not exist", err: domain.ErrNoPayment, expCode: codes.NotFound},
+		{description: "should return failed precondition given the reversal is not permitted", err: domain.ErrNotPermitted, expCode: codes.FailedPrecondition},
+		{description: "should return the payment given the reversal succeeds", expCode: codes.OK},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			// a zero amount reverses all that is left uncaptured
+			m.EXPECT().Reverse(gomock.Any(), testPaymentID, uint64(0)).Return(&paymentsV1.Payment{Id: testPaymentID}, tc.err)
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			_, err = s.Reverse(context.Background(), &gatewayV1.ReverseRequest{PaymentId: testPaymentID})
+			assert.Equal(t, tc.expCode, status.Code(err))
+		})
+	}
+	t.Run("should return invalid argument given the payment id is empty", func(t *testing.T) {
+		t.Parallel()
+		s, err := transportgrpc.NewServer(mocks.NewMockGateway(gomock.NewController(t)))
+		require.NoError(t, err)
+
+		_, err = s.Reverse(context.Background(), &gatewayV1.ReverseRequest{Amount: 10})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_Void(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description string
+		request     *gatewayV1.VoidRequest
+		expCode     codes.Code
+		fn          func(m *mocks.MockGateway)
+	}{
+		{
+			description: "should return invalid argument given the payment id is empty",
+			request:     &gatewayV1.VoidRequest{},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return failed precondition given the void is not permitted",
+			request:     &gatewayV1.VoidRequest{PaymentId: testPaymentID},
+			expCode:     codes.FailedPrecondition,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().Void(gomock.Any(), testPaymentID).Return(nil, domain.ErrNotPermitted)
+			},
+		},
+		{
+			description: "should succeed given the void succeeds",
+			request:     &gatewayV1.VoidRequest{PaymentId: testPaymentID},
+			expCode:     codes.OK,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().Void(gomock.Any(), testPaymentID).Return(&paymentsV1.Payment{Id: testPaymentID}, nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			if tc.fn != nil {
+				tc.fn(m)
+			}
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			_, err = s.Void(context.Background(), tc.request)
+			assert.Equal(t, tc.expCode, status.Code(err))
+		})
+	}
+}
+
+func TestServer_ErrorInfo(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mocks.NewMockGateway(ctrl)
+	m.EXPECT().Void(gomock.Any(), testPaymentID).
+		Return(nil, domain.DeclinedError{Err: domain.ErrNotPermitted, Reason: "insufficient funds"})
+	s, err := transportgrpc.NewServer(m)
+	require.NoError(t, err)
+
+	_, err = s.Void(context.Background(), &gatewayV1.VoidRequest{PaymentId: testPaymentID})
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, "void not allowed: payment declined", st.Message())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, string(domain.ErrorCodePaymentDeclined), info.Reason)
+	assert.Equal(t, transportgrpc.ErrorDomain, info.Domain)
+	assert.Equal(t, map[string]string{"decline_reason": "insufficient funds"}, info.Metadata)
+
+	_, err = s.Authorize(context.Background(), &gatewayV1.AuthorizeRequest{})
+	st = status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 2)
+	info, ok = st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, string(domain.ErrorCodeValidationFailed), info.Reason)
+	badRequest, ok := st.Details()[1].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 2)
+	assert.Equal(t, "amount", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "card", badRequest.FieldViolations[1].Field)
+	assert.Equal(t, "missing card: cannot be empty", badRequest.FieldViolations[1].Description)
+}
+
+func TestServer_GetPayment(t *testing.T) {
+	t.Parallel()
+	details := &paymentsV1.PaymentDetails{Payment: &paymentsV1.Payment{Id: testPaymentID}}
+
+	for _, tc := range []struct {
+		description string
+		request     *gatewayV1.GetPaymentRequest
+		expCode     codes.Code
+		fn          func(m *mocks.MockGateway)
+	}{
+		{
+			description: "should return invalid argument given the payment id is empty",
+			request:     &gatewayV1.GetPaymentRequest{},
+			expCode:     codes.InvalidArgument,
+		},
+		{
+			description: "should return not found given the payment does not exist",
+			request:     &gatewayV1.GetPaymentRequest{PaymentId: testPaymentID},
+			expCode:     codes.NotFound,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().GetPayment(gomock.Any(), testPaymentID).Return(nil, domain.ErrNoPayment)
+			},
+		},
+		{
+			description: "should return the payment details given the payment exists",
+			request:     &gatewayV1.GetPaymentRequest{PaymentId: testPaymentID},
+			expCode:     codes.OK,
+			fn: func(m *mocks.MockGateway) {
+				m.EXPECT().GetPayment(gomock.Any(), testPaymentID).Return(details, nil)
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mocks.NewMockGateway(ctrl)
+			if tc.fn != nil {
+				tc.fn(m)
+			}
+			s, err := transportgrpc.NewServer(m)
+			require.NoError(t, err)
+
+			resp, err := s.GetPayment(context.Background(), tc.request)
+			assert.Equal(t, tc.expCode, status.Code(err))
+			if tc.expCode == codes.OK {
+				assert.Equal(t, details, resp.PaymentDetails)
+			}
+		})
+	}
+}
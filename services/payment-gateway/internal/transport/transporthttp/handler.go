@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
 	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
 
 	"github.com/gorilla/mux"
@@ -13,30 +14,55 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/encoding/protojson"
 	"net/http"
-	"time"
 )
 
-const (
-	CVVLen       = 3
-	CurrencyLen  = 3
-	ExpiryMonLen = 12
-)
+const LastFourLen = 4
 
-func HandleRoutes(h Handler) *mux.Router {
+func HandleRoutes(h Handler, m MerchantHandler, wh WebhookHandler, idempotencyStore IdempotencyStore) *mux.Router {
 	r := mux.NewRouter()
-	r.HandleFunc("/authorize", h.AuthorizeHandler).Methods(http.MethodPost)
-	r.HandleFunc("/capture", h.CaptureHandler).Methods(http.MethodPost)
-	r.HandleFunc("/refund", h.RefundHandler).Methods(http.MethodPost)
-	r.HandleFunc("/void", h.VoidHandler).Methods(http.MethodPost)
+	r.Use(RequestIDMiddleware)
+
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.Use(m.AdminMiddleware)
+	admin.HandleFunc("/merchants", m.CreateMerchantHandler).Methods(http.MethodPost)
+	admin.HandleFunc("/merchants/{id}/api-keys", m.RotateAPIKeyHandler).Methods(http.MethodPost)
+	admin.HandleFunc("/merchants/{id}/card-brands", m.AcceptedCardBrandsHandler).Methods(http.MethodPut)
+
+	// every other request is made by a merchant and scoped to them
+	api := r.NewRoute().Subrouter()
+	api.Use(m.AuthMiddleware)
+
+	post := api.Methods(http.MethodPost).Subrouter()
+	post.Use(IdempotencyMiddleware(idempotencyStore))
+	post.HandleFunc("/authorize", h.AuthorizeHandler)
+	post.HandleFunc("/capture", h.CaptureHandler)
+	post.HandleFunc("/refund", h.RefundHandler)
+	post.HandleFunc("/void", h.VoidHandler)
+	post.HandleFunc("/increment", h.IncrementAuthorizationHandler)
+	post.HandleFunc("/reverse", h.ReverseHandler)
+	post.HandleFunc("/webhooks/endpoints", wh.CreateEndpointHandler)
+	post.HandleFunc("/webhooks/deliveries/{id}/replay", wh.ReplayDeliveryHandler)
+
+	api.HandleFunc("/payments", h.ListPaymentsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/payments/{id}", h.GetPaymentHandler).Methods(http.MethodGet)
+	api.HandleFunc("/payments/{id}/actions", h.ListPaymentActionsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/webhooks/endpoints", wh.ListEndpointsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/webhooks/endpoints/{id}", wh.DeleteEndpointHandler).Methods(http.MethodDelete)
+	api.HandleFunc("/webhooks/deliveries", wh.ListDeliveriesHandler).Methods(http.MethodGet)
 
 	return r
 }
 
 type Gateway interface {
-	CreatePayment(ctx context.Context, amount *amountV1.Money, method domain.PaymentMethod) (*paymentsV1.Payment, error)
-	Capture(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	CreatePayment(ctx context.Context, amount *amountV1.Money, settlementCurrency string, method domain.PaymentMethod) (*paymentsV1.Payment, error)
+	Capture(ctx context.Context, paymentID string, amount uint64, final bool) (*paymentsV1.Payment, error)
 	Refund(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
 	Void(ctx context.Context, paymentID string) (*paymentsV1.Payment, error)
+	IncrementAuthorization(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	Reverse(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	GetPayment(ctx context.Context, paymentID string) (*paymentsV1.PaymentDetails, error)
+	ListPayments(ctx context.Context, filters domain.ListPaymentFilters) (*paymentsV1.PaymentList, error)
+	ListPaymentActions(ctx context.Context, paymentID string) ([]*paymentsV1.PaymentAction, error)
 }
 
 type Handler struct {
@@ -52,73 +78,50 @@ func NewHandler(processorClient Gateway) (Handler, error) {
 
 func (h Handler) AuthorizeHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Body == http.NoBody {
-		http.Error(w, "no body supplied", http.StatusBadRequest)
+		invalidRequest(w, r, "no body supplied")
 		return
 	}
 	defer r.Body.Close()
 
 	var authorizationRequest CreateAuthorizationRequest
 	if err := json.NewDecoder(r.Body).Decode(&authorizationRequest); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		invalidRequest(w, r, "invalid payload")
 		return
 	}
 
-	validateRequest := func() error {
-		if authorizationRequest.Amount == nil {
-			return errors.New("invalid amount: cannot be missing")
-		}
-		if authorizationRequest.Amount.MinorUnits == 0 {
-			return errors.New("invalid amount.minor_units: cannot be zero")
-		}
-		if len(authorizationRequest.Amount.Currency) != CurrencyLen {
-			return errors.Errorf("invalid amount.currency: must be length of %d", CurrencyLen)
-		}
-		if authorizationRequest.Card == nil {
-			return errors.New("missing payment method: cannot be empty")
-		}
-		if !domain.ValidCardNumber(authorizationRequest.Card.CardNumber) {
-			return errors.New("invalid payment_method.card.card_number: invalid card number")
-		}
-		if len(authorizationRequest.Card.Cvv) != CVVLen {
-			return errors.Errorf("invalid payment_method.card.cvv: length not equal to %d", 3)
-		}
-		if authorizationRequest.Card.Expiry == nil {
-			return errors.New("missing payment_method.card.expiry: cannot be empty")
-		}
-		if authorizationRequest.Card.Expiry.Month > 12 {
-			return errors.Errorf("invalid payment_method.card.expiry.month: expiry month cannot exceed %d", ExpiryMonLen)
-		}
-		if int(authorizationRequest.Card.Expiry.Year) < time.Now().Year() {
-			return errors.New("missing payment_method.card.expiry.year: cannot be in the past")
-		}
-		return nil
-	}
-	if err := validateRequest(); err != nil {
-		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	if err := authorizationRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
 		return
 	}
 
 	logFields := log.Fields{
 		"amount.minor_units": authorizationRequest.Amount.MinorUnits,
 		"amount.currency":    authorizationRequest.Amount.Currency,
-		// Ideally a card PaymentID/token would be better
-		"card.first_six": authorizationRequest.Card.CardNumber[:6],
-		"card.last_four": authorizationRequest.Card.CardNumber[len(authorizationRequest.Card.CardNumber)-3:],
+		"amount":             currencyV1.String(authorizationRequest.Amount),
 		// could probably be injected via a middleware
 		"url": "/authorize",
 	}
+	if authorizationRequest.Card.Token != "" {
+		logFields["card.token"] = authorizationRequest.Card.Token
+	}
 
 	fn := func() error {
-		paymentResponse, err := h.gateway.CreatePayment(r.Context(), authorizationRequest.Amount, domain.PaymentMethod{Card: authorizationRequest.Card})
+		paymentResponse, err := h.gateway.CreatePayment(r.Context(), authorizationRequest.Amount, authorizationRequest.SettlementCurrency,
+			domain.PaymentMethod{Card: authorizationRequest.Card})
 		if err != nil {
 			return err
 		}
+		logFields["card.token"] = paymentResponse.GetCard().GetToken()
 		paymentBytes, err := protojson.Marshal(paymentResponse)
 		if err != nil {
 			logFields["payment.id"] = paymentResponse.Id
 			return err
 		}
 		w.Header().Set("Content-Type", "application/json")
+		// the authorization is processed asynchronously, the outcome is retrieved via GET /payments/{id}
+		if paymentResponse.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING {
+			w.WriteHeader(http.StatusAccepted)
+		}
 		_, err = w.Write(paymentBytes)
 		if err != nil {
 			return err
@@ -127,37 +130,26 @@ func (h Handler) AuthorizeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := fn(); err != nil {
-		logFields["error"] = err
-		log.WithFields(logFields).Error("failed to process authorization request")
-		http.Error(w, "Oops something went wrong", http.StatusInternalServerError)
+		writeError(w, r, err, "authorization", logFields)
 		return
 	}
 }
 
 func (h Handler) CaptureHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Body == http.NoBody {
-		http.Error(w, "no body supplied", http.StatusBadRequest)
+		invalidRequest(w, r, "no body supplied")
 		return
 	}
 	defer r.Body.Close()
 
 	var captureRequest CreateCaptureRequest
 	if err := json.NewDecoder(r.Body).Decode(&captureRequest); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		invalidRequest(w, r, "invalid payload")
 		return
 	}
 
-	validateRequest := func() error {
-		if captureRequest.PaymentID == "" {
-			return errors.New("invalid payment_id: cannot be empty")
-		}
-		if captureRequest.Amount == 0 {
-			return errors.New("invalid amount: cannot be zero")
-		}
-		return nil
-	}
-	if err := validateRequest(); err != nil {
-		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	if err := captureRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
 		return
 	}
 	logFields := log.Fields{
@@ -167,7 +159,7 @@ func (h Handler) CaptureHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	fn := func() error {
-		captureResponse, err := h.gateway.Capture(r.Context(), captureRequest.PaymentID, captureRequest.Amount)
+		captureResponse, err := h.gateway.Capture(r.Context(), captureRequest.PaymentID, captureRequest.Amount, captureRequest.Final)
 		if err != nil {
 			return err
 		}
@@ -185,45 +177,120 @@ func (h Handler) CaptureHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := fn(); err != nil {
-		if errors.Is(err, domain.ErrNoPayment) {
-			http.Error(w, "payment not found", http.StatusNotFound)
-			return
+		writeError(w, r, err, "capture", logFields)
+		return
+	}
+}
+
+func (h Handler) IncrementAuthorizationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		invalidRequest(w, r, "no body supplied")
+		return
+	}
+	defer r.Body.Close()
+
+	var incrementRequest CreateIncrementRequest
+	if err := json.NewDecoder(r.Body).Decode(&incrementRequest); err != nil {
+		invalidRequest(w, r, "invalid payload")
+		return
+	}
+
+	if err := incrementRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
+		return
+	}
+	logFields := log.Fields{
+		"payment.id": incrementRequest.PaymentID,
+		"amount":     incrementRequest.Amount,
+		"url":        "/increment",
+	}
+
+	fn := func() error {
+		incrementResponse, err := h.gateway.IncrementAuthorization(r.Context(), incrementRequest.PaymentID, incrementRequest.Amount)
+		if err != nil {
+			return err
 		}
-		if errors.Is(err, domain.ErrNotPermitted) {
-			http.Error(w, "capture not allowed", http.StatusForbidden)
-			return
+
+		paymentBytes, err := protojson.Marshal(incrementResponse)
+		if err != nil {
+			return err
 		}
-		logFields["error"] = err
-		log.WithFields(logFields).Error("failed to process capture request")
-		http.Error(w, "Oops something went wrong", http.StatusInternalServerError)
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(paymentBytes)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		writeError(w, r, err, "incremental authorization", logFields)
 		return
 	}
 }
 
-func (h Handler) RefundHandler(w http.ResponseWriter, r *http.Request) {
+func (h Handler) ReverseHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Body == http.NoBody {
-		http.Error(w, "no body supplied", http.StatusBadRequest)
+		invalidRequest(w, r, "no body supplied")
 		return
 	}
 	defer r.Body.Close()
 
-	var refundRequest CreateRefundRequest
-	if err := json.NewDecoder(r.Body).Decode(&refundRequest); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+	var reverseRequest CreateReverseRequest
+	if err := json.NewDecoder(r.Body).Decode(&reverseRequest); err != nil {
+		invalidRequest(w, r, "invalid payload")
 		return
 	}
 
-	validateRequest := func() error {
-		if refundRequest.PaymentID == "" {
-			return errors.New("invalid payment_id: cannot be empty")
+	if err := reverseRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
+		return
+	}
+	logFields := log.Fields{
+		"payment.id": reverseRequest.PaymentID,
+		"amount":     reverseRequest.Amount,
+		"url":        "/reverse",
+	}
+
+	fn := func() error {
+		reverseResponse, err := h.gateway.Reverse(r.Context(), reverseRequest.PaymentID, reverseRequest.Amount)
+		if err != nil {
+			return err
+		}
+
+		paymentBytes, err := protojson.Marshal(reverseResponse)
+		if err != nil {
+			return err
 		}
-		if refundRequest.Amount == 0 {
-			return errors.New("invalid amount: cannot be zero")
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(paymentBytes)
+		if err != nil {
+			return err
 		}
 		return nil
 	}
-	if err := validateRequest(); err != nil {
-		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+	if err := fn(); err != nil {
+		writeError(w, r, err, "reversal", logFields)
+		return
+	}
+}
+
+func (h Handler) RefundHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		invalidRequest(w, r, "no body supplied")
+		return
+	}
+	defer r.Body.Close()
+
+	var refundRequest CreateRefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&refundRequest); err != nil {
+		invalidRequest(w, r, "invalid payload")
+		return
+	}
+
+	if err := refundRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
 		return
 	}
 	logFields := log.Fields{
@@ -250,42 +317,26 @@ func (h Handler) RefundHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := fn(); err != nil {
-		if errors.Is(err, domain.ErrNoPayment) {
-			http.Error(w, "payment not found", http.StatusNotFound)
-			return
-		}
-		if errors.Is(err, domain.ErrNotPermitted) {
-			http.Error(w, "refund not allowed", http.StatusForbidden)
-			return
-		}
-		logFields["error"] = err
-		log.WithFields(logFields).Error("failed to process refund request")
-		http.Error(w, "Oops something went wrong", http.StatusInternalServerError)
+		writeError(w, r, err, "refund", logFields)
 		return
 	}
 }
 
 func (h Handler) VoidHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Body == http.NoBody {
-		http.Error(w, "no body supplied", http.StatusBadRequest)
+		invalidRequest(w, r, "no body supplied")
 		return
 	}
 	defer r.Body.Close()
 
 	var voidRequest CreateVoidRequest
 	if err := json.NewDecoder(r.Body).Decode(&voidRequest); err != nil {
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		invalidRequest(w, r, "invalid payload")
 		return
 	}
 
-	validateRequest := func() error {
-		if voidRequest.PaymentID == "" {
-			return errors.New("invalid payment_id: cannot be empty")
-		}
-		return nil
-	}
-	if err := validateRequest(); err != nil {
-		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	if err := voidRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
 		return
 	}
 	logFields := log.Fields{
@@ -311,17 +362,112 @@ func (h Handler) VoidHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := fn(); err != nil {
-		if errors.Is(err, domain.ErrNoPayment) {
-			http.Error(w, "payment not found", http.StatusNotFound)
-			return
+		writeError(w, r, err, "void", logFields)
+		return
+	}
+}
+
+func (h Handler) GetPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	paymentID := mux.Vars(r)["id"]
+	logFields := log.Fields{
+		"payment.id": paymentID,
+		"url":        "/payments/{id}",
+	}
+
+	fn := func() error {
+		paymentResponse, err := h.gateway.GetPayment(r.Context(), paymentID)
+		if err != nil {
+			return err
+		}
+		paymentBytes, err := protojson.Marshal(paymentResponse)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(paymentBytes)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		writeError(w, r, err, "get payment", logFields)
+		return
+	}
+}
+
+func (h Handler) ListPaymentActionsHandler(w http.ResponseWriter, r *http.Request) {
+	paymentID := mux.Vars(r)["id"]
+	logFields := log.Fields{
+		"payment.id": paymentID,
+		"url":        "/payments/{id}/actions",
+	}
+
+	fn := func() error {
+		actions, err := h.gateway.ListPaymentActions(r.Context(), paymentID)
+		if err != nil {
+			return err
+		}
+		actionBytes, err := protojson.Marshal(&paymentsV1.PaymentActions{PaymentActions: actions})
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(actionBytes)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		writeError(w, r, err, "list payment actions", logFields)
+		return
+	}
+}
+
+func (h Handler) ListPaymentsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	listRequest := ListPaymentsRequest{
+		Statuses:      query["status"],
+		Currencies:    query["currency"],
+		MinAmount:     query.Get("min_amount"),
+		MaxAmount:     query.Get("max_amount"),
+		CreatedAfter:  query.Get("created_after"),
+		CreatedBefore: query.Get("created_before"),
+		CardLastFour:  query.Get("card_last_four"),
+		Limit:         query.Get("limit"),
+		Cursor:        query.Get("cursor"),
+	}
+	if err := listRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
+		return
+	}
+	filters := listRequest.Filters()
+	logFields := log.Fields{
+		"url": "/payments",
+	}
+
+	fn := func() error {
+		listResponse, err := h.gateway.ListPayments(r.Context(), filters)
+		if err != nil {
+			return err
 		}
-		if errors.Is(err, domain.ErrNotPermitted) {
-			http.Error(w, "void not allowed", http.StatusForbidden)
-			return
+		listBytes, err := protojson.Marshal(listResponse)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write(listBytes)
+		if err != nil {
+			return err
 		}
-		logFields["error"] = err
-		log.WithFields(logFields).Error("failed to process void request")
-		http.Error(w, "Oops something went wrong", http.StatusInternalServerError)
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		writeError(w, r, err, "list payments", logFields)
 		return
 	}
 }
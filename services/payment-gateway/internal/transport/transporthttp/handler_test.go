@@ -14,6 +14,7 @@ import (
 
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
 	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp/mocks"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/validation"
 
 	"github.com/golang/mock/gomock"
 	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
@@ -32,7 +33,7 @@ func TestHandler_AuthorizeHandler_Error(t *testing.T) {
 			Card: &paymentsV1.PaymentMethodCard{
 				CardNumber: "4000000000000119",
 				Expiry: &paymentsV1.PaymentMethodCard_ExpiryDate{
-					Month: transporthttp.ExpiryMonLen,
+					Month: validation.ExpiryMonthMax,
 					Year:  uint32(time.Now().Year() + 1),
 				},
 				Cvv: "123",
@@ -80,7 +81,23 @@ func TestHandler_AuthorizeHandler_Error(t *testing.T) {
 					Currency:   "GB",
 				},
 			},
-			responseMessage: "invalid amount.currency: must be length of 3",
+			responseMessage: "invalid amount.currency: must be an ISO 4217 currency code",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description: "should return error given that the amount currency is not an ISO 4217 currency",
+			request: transporthttp.CreateAuthorizationRequest{
+				Card: &paymentsV1.PaymentMethodCard{
+					CardNumber: validRequest.Card.CardNumber,
+					Expiry:     validRequest.Card.Expiry,
+					Cvv:        validRequest.Card.Cvv,
+				},
+				Amount: &amountV1.Money{
+					MinorUnits: validRequest.Amount.MinorUnits,
+					Currency:   "XYZ",
+				},
+			},
+			responseMessage: "invalid amount.currency: must be an ISO 4217 currency code",
 			expStatusCode:   http.StatusUnprocessableEntity,
 		},
 		{
@@ -147,7 +164,7 @@ func TestHandler_AuthorizeHandler_Error(t *testing.T) {
 					Currency:   validRequest.Amount.Currency,
 				},
 			},
-			responseMessage: "invalid payment_method.card.expiry.month: expiry month cannot exceed 12",
+			responseMessage: "invalid payment_method.card.expiry.month: must be between 1 and 12",
 			expStatusCode:   http.StatusUnprocessableEntity,
 		},
 		{
@@ -166,7 +183,7 @@ func TestHandler_AuthorizeHandler_Error(t *testing.T) {
 					Currency:   validRequest.Amount.Currency,
 				},
 			},
-			responseMessage: "missing payment_method.card.expiry.year: cannot be in the past",
+			responseMessage: "invalid payment_method.card.expiry.year: cannot be in the past",
 			expStatusCode:   http.StatusUnprocessableEntity,
 		},
 		{
@@ -189,11 +206,88 @@ func TestHandler_AuthorizeHandler_Error(t *testing.T) {
 			fn: func(mocks *mocks.MockGateway) {
 				mocks.
 					EXPECT().
-					CreatePayment(gomock.Any(), gomock.Any(), gomock.Any()).
+					CreatePayment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("an error"))
 			},
 			expStatusCode: http.StatusInternalServerError,
 		},
+		{
+			description: "should return error given that a card number is supplied with a token",
+			request: transporthttp.CreateAuthorizationRequest{
+				Card: &paymentsV1.PaymentMethodCard{
+					Token:      "tok_abc",
+					CardNumber: validRequest.Card.CardNumber,
+				},
+				Amount: validRequest.Amount,
+			},
+			responseMessage: "invalid payment_method.card.card_number: cannot be supplied with a token",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description: "should return error given that the card number is empty",
+			request: transporthttp.CreateAuthorizationRequest{
+				Card: &paymentsV1.PaymentMethodCard{
+					Expiry: validRequest.Card.Expiry,
+					Cvv:    validRequest.Card.Cvv,
+				},
+				Amount: validRequest.Amount,
+			},
+			responseMessage: "missing payment_method.card.card_number: cannot be empty",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description: "should return error given that the token is unknown",
+			request: transporthttp.CreateAuthorizationRequest{
+				Card:   &paymentsV1.PaymentMethodCard{Token: "tok_abc"},
+				Amount: validRequest.Amount,
+			},
+			responseMessage: "invalid payment_method.card.token: unknown token",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					CreatePayment(gomock.Any(), gomock.Any(), "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}}).
+					Return(nil, domain.ErrNoCardToken)
+			},
+			expStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description: "should return error given that the settlement currency is not an ISO 4217 currency",
+			request: transporthttp.CreateAuthorizationRequest{
+				Card:               validRequest.Card,
+				Amount:             validRequest.Amount,
+				SettlementCurrency: "XYZ",
+			},
+			responseMessage: "invalid settlement_currency: must be an ISO 4217 currency code",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description: "should return error given that there is no fx rate to the settlement currency",
+			request: transporthttp.CreateAuthorizationRequest{
+				Card:               validRequest.Card,
+				Amount:             validRequest.Amount,
+				SettlementCurrency: "JPY",
+			},
+			responseMessage: "invalid settlement_currency: no fx rate from amount.currency",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					CreatePayment(gomock.Any(), gomock.Any(), "JPY", gomock.Any()).
+					Return(nil, domain.ErrNoFXRate)
+			},
+			expStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given that the issuer is unavailable",
+			request:         validRequest,
+			responseMessage: "issuer unavailable, try again later",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					CreatePayment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrIssuerUnavailable)
+			},
+			expStatusCode: http.StatusServiceUnavailable,
+		},
 	} {
 		tc := tc
 		t.Run(tc.description, func(t *testing.T) {
@@ -250,7 +344,7 @@ func TestHandler_AuthorizeHandler_Success(t *testing.T) {
 	mockGateway.EXPECT().CreatePayment(gomock.Any(), &amountV1.Money{
 		MinorUnits: 2212,
 		Currency:   "GBP",
-	},
+	}, "",
 		domain.PaymentMethod{
 			Card: &paymentsV1.PaymentMethodCard{
 				CardNumber: "4000 0000 0000 0119",
@@ -278,6 +372,47 @@ func TestHandler_AuthorizeHandler_Success(t *testing.T) {
 	assert.Equal(t, expPayment.String(), paymentResponse.String())
 }
 
+func TestHandler_AuthorizeHandler_Token(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl        = gomock.NewController(t)
+		mockGateway = mocks.NewMockGateway(ctrl)
+
+		card       = &paymentsV1.PaymentMethodCard{Token: "tok_abc", Bin: "400000", LastFour: "0119"}
+		expPayment = &paymentsV1.Payment{
+			Id: uuid.NewV4().String(),
+			Amount: &amountV1.Money{
+				MinorUnits: 1000,
+				Currency:   "GBP",
+			},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+			PaymentMethod: &paymentsV1.Payment_Card{Card: card},
+			CreatedAt:     timestamppb.Now(),
+		}
+	)
+
+	mockGateway.EXPECT().CreatePayment(gomock.Any(), &amountV1.Money{
+		MinorUnits: 1000,
+		Currency:   "GBP",
+	}, "", domain.PaymentMethod{Card: &paymentsV1.PaymentMethodCard{Token: "tok_abc"}}).
+		Return(expPayment, nil)
+
+	h, err := transporthttp.NewHandler(mockGateway)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+
+	h.AuthorizeHandler(recorder, httptest.NewRequest(http.MethodPost, "/authorize",
+		bytes.NewReader([]byte(`{"card":{"token":"tok_abc"},"amount":{"minor_units":1000,"currency":"GBP"}}`))))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	respBody, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	var paymentResponse paymentsV1.Payment
+	require.NoError(t, protojson.Unmarshal(respBody, &paymentResponse))
+	assert.Equal(t, expPayment.String(), paymentResponse.String())
+	assert.Empty(t, paymentResponse.GetCard().GetCardNumber())
+}
+
 func TestHandler_CaptureHandler_Error(t *testing.T) {
 	t.Parallel()
 	var (
@@ -320,7 +455,7 @@ func TestHandler_CaptureHandler_Error(t *testing.T) {
 			fn: func(mocks *mocks.MockGateway) {
 				mocks.
 					EXPECT().
-					Capture(gomock.Any(), gomock.Any(), gomock.Any()).
+					Capture(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("an error"))
 			},
 			expStatusCode: http.StatusInternalServerError,
@@ -332,7 +467,7 @@ func TestHandler_CaptureHandler_Error(t *testing.T) {
 			fn: func(mocks *mocks.MockGateway) {
 				mocks.
 					EXPECT().
-					Capture(gomock.Any(), gomock.Any(), gomock.Any()).
+					Capture(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, domain.ErrNoPayment)
 			},
 			expStatusCode: http.StatusNotFound,
@@ -344,11 +479,35 @@ func TestHandler_CaptureHandler_Error(t *testing.T) {
 			fn: func(mocks *mocks.MockGateway) {
 				mocks.
 					EXPECT().
-					Capture(gomock.Any(), gomock.Any(), gomock.Any()).
+					Capture(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(nil, domain.ErrNotPermitted)
 			},
 			expStatusCode: http.StatusForbidden,
 		},
+		{
+			description:     "should return error if the authorization has expired",
+			request:         validRequest,
+			responseMessage: "capture not allowed: authorization expired",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					Capture(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrAuthorizationExpired)
+			},
+			expStatusCode: http.StatusForbidden,
+		},
+		{
+			description:     "should return error if the issuer is unavailable",
+			request:         validRequest,
+			responseMessage: "issuer unavailable, try again later",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					Capture(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrIssuerUnavailable)
+			},
+			expStatusCode: http.StatusServiceUnavailable,
+		},
 	} {
 		tc := tc
 		t.Run(tc.description, func(t *testing.T) {
@@ -401,7 +560,7 @@ func TestHandler_CaptureHandler_Success(t *testing.T) {
 		}
 	)
 
-	mockGateway.EXPECT().Capture(gomock.Any(), "a6921fc3-a7e3-4661-909b-b3c6c77837ce", uint64(2212)).
+	mockGateway.EXPECT().Capture(gomock.Any(), "a6921fc3-a7e3-4661-909b-b3c6c77837ce", uint64(2212), false).
 		Return(expPayment, nil)
 
 	h, err := transporthttp.NewHandler(mockGateway)
@@ -691,3 +850,549 @@ func TestHandler_VoidHandler_Success(t *testing.T) {
 	// easier to compare when test fails. proto.Equal also works but not as readable
 	assert.Equal(t, expPayment.String(), paymentResponse.String())
 }
+
+func TestHandler_GetPaymentHandler(t *testing.T) {
+	t.Parallel()
+	var (
+		paymentID  = uuid.NewV4().String()
+		expDetails = &paymentsV1.PaymentDetails{
+			Payment: &paymentsV1.Payment{
+				Id: paymentID,
+				Amount: &amountV1.Money{
+					MinorUnits: 1000,
+					Currency:   "GBP",
+				},
+				PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED,
+				CreatedAt:     timestamppb.Now(),
+			},
+			Balance: &paymentsV1.PaymentBalance{
+				Captured:  400,
+				Remaining: 600,
+			},
+			PaymentActions: []*paymentsV1.PaymentAction{
+				{
+					Id:           uuid.NewV4().String(),
+					Amount:       400,
+					PaymentType:  paymentsV1.PaymentType_PAYMENT_TYPE_CAPTURE,
+					ResponseCode: "00",
+					PaymentId:    paymentID,
+					CreatedAt:    timestamppb.Now(),
+					ProcessedAt:  timestamppb.Now(),
+				},
+			},
+		}
+	)
+
+	for _, tc := range []struct {
+		description     string
+		expStatusCode   int
+		responseMessage string
+		fn              func(mocks *mocks.MockGateway)
+	}{
+		{
+			description:     "should return error given payment not found",
+			responseMessage: "payment not found",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					GetPayment(gomock.Any(), paymentID).
+					Return(nil, domain.ErrNoPayment)
+			},
+			expStatusCode: http.StatusNotFound,
+		},
+		{
+			description:     "should return error if unable to get payment",
+			responseMessage: "Oops something went wrong",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					GetPayment(gomock.Any(), paymentID).
+					Return(nil, errors.New("an error"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+		},
+		{
+			description:     "should return the payment details",
+			responseMessage: `"remaining":"600"`,
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					GetPayment(gomock.Any(), paymentID).
+					Return(expDetails, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl        = gomock.NewController(t)
+				mockGateway = mocks.NewMockGateway(ctrl)
+			)
+			if tc.fn != nil {
+				tc.fn(mockGateway)
+			}
+
+			h, err := transporthttp.NewHandler(mockGateway)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			merchantRoutes(t, h).ServeHTTP(recorder, withAPIKey(httptest.NewRequest(http.MethodGet, "/payments/"+paymentID, nil)))
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), tc.responseMessage)
+
+			if tc.expStatusCode == http.StatusOK {
+				var detailsResponse paymentsV1.PaymentDetails
+				require.NoError(t, protojson.Unmarshal(respBody, &detailsResponse))
+				assert.Equal(t, expDetails.String(), detailsResponse.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ListPaymentActionsHandler(t *testing.T) {
+	t.Parallel()
+	var (
+		paymentID  = uuid.NewV4().String()
+		expActions = []*paymentsV1.PaymentAction{
+			{
+				Id:           uuid.NewV4().String(),
+				Amount:       1000,
+				PaymentType:  paymentsV1.PaymentType_PAYMENT_TYPE_AUTHORIZATION,
+				ResponseCode: "00",
+				PaymentId:    paymentID,
+				CreatedAt:    timestamppb.Now(),
+				ProcessedAt:  timestamppb.Now(),
+			},
+		}
+	)
+
+	for _, tc := range []struct {
+		description     string
+		expStatusCode   int
+		responseMessage string
+		fn              func(mocks *mocks.MockGateway)
+	}{
+		{
+			description:     "should return error given payment not found",
+			responseMessage: "payment not found",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), paymentID).
+					Return(nil, domain.ErrNoPayment)
+			},
+			expStatusCode: http.StatusNotFound,
+		},
+		{
+			description:     "should return error if unable to list payment actions",
+			responseMessage: "Oops something went wrong",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), paymentID).
+					Return(nil, errors.New("an error"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+		},
+		{
+			description:     "should return the payment actions",
+			responseMessage: `"responseCode":"00"`,
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					ListPaymentActions(gomock.Any(), paymentID).
+					Return(expActions, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl        = gomock.NewController(t)
+				mockGateway = mocks.NewMockGateway(ctrl)
+			)
+			if tc.fn != nil {
+				tc.fn(mockGateway)
+			}
+
+			h, err := transporthttp.NewHandler(mockGateway)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			merchantRoutes(t, h).ServeHTTP(recorder, withAPIKey(httptest.NewRequest(http.MethodGet, "/payments/"+paymentID+"/actions", nil)))
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), tc.responseMessage)
+		})
+	}
+}
+
+func TestHandler_ListPaymentsHandler(t *testing.T) {
+	t.Parallel()
+	var (
+		expList = &paymentsV1.PaymentList{
+			Payments: []*paymentsV1.Payment{
+				{
+					Id: uuid.NewV4().String(),
+					Amount: &amountV1.Money{
+						MinorUnits: 1000,
+						Currency:   "GBP",
+					},
+					PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+					CreatedAt:     timestamppb.Now(),
+				},
+			},
+			NextCursor: "next",
+		}
+		cursor = domain.PaymentCursor{ID: uuid.NewV4().String()}
+	)
+
+	for _, tc := range []struct {
+		description     string
+		query           string
+		expStatusCode   int
+		responseMessage string
+		fn              func(mocks *mocks.MockGateway)
+	}{
+		{
+			description:     "should return error given an unknown status",
+			query:           "status=unknown",
+			responseMessage: "invalid status: unknown status unknown",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given an invalid currency",
+			query:           "currency=XYZ",
+			responseMessage: "invalid currency: must be an ISO 4217 currency code",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given an invalid min amount",
+			query:           "min_amount=-1",
+			responseMessage: "invalid min_amount: must be a positive integer",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given min amount exceeds max amount",
+			query:           "min_amount=100&max_amount=10",
+			responseMessage: "invalid min_amount: cannot exceed max_amount",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given an invalid created after",
+			query:           "created_after=yesterday",
+			responseMessage: "invalid created_after: must be an RFC3339 timestamp",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given an invalid card last four",
+			query:           "card_last_four=12a4",
+			responseMessage: "invalid card_last_four: must be 4 digits",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given the limit exceeds the maximum",
+			query:           "limit=101",
+			responseMessage: "invalid limit: must be between 1 and 100",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given an invalid cursor",
+			query:           "cursor=abc",
+			responseMessage: "invalid cursor",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error if unable to list payments",
+			responseMessage: "Oops something went wrong",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					ListPayments(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("an error"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+		},
+		{
+			description: "should list payments matching the filters",
+			query: "status=authorized&status=CAPTURED&currency=GBP&min_amount=100&max_amount=2000" +
+				"&created_after=2021-11-01T00:00:00Z&created_before=2021-12-01T00:00:00Z&card_last_four=0119" +
+				"&limit=10&cursor=" + cursor.Encode(),
+			responseMessage: `"nextCursor":"next"`,
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.
+					EXPECT().
+					ListPayments(gomock.Any(), domain.ListPaymentFilters{
+						Statuses:      []domain.PaymentStatus{domain.PaymentStatusAuthorized, domain.PaymentStatusCaptured},
+						Currencies:    []string{"GBP"},
+						MinAmount:     100,
+						MaxAmount:     2000,
+						CreatedAfter:  time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+						CreatedBefore: time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+						CardLastFour:  "0119",
+						Cursor:        &cursor,
+						Limit:         10,
+					}).
+					Return(expList, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl        = gomock.NewController(t)
+				mockGateway = mocks.NewMockGateway(ctrl)
+			)
+			if tc.fn != nil {
+				tc.fn(mockGateway)
+			}
+
+			h, err := transporthttp.NewHandler(mockGateway)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			h.ListPaymentsHandler(recorder, httptest.NewRequest(http.MethodGet, "/payments?"+tc.query, nil))
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), tc.responseMessage)
+		})
+	}
+}
+
+func TestHandler_AuthorizeHandler_Pending(t *testing.T) {
+	t.Parallel()
+	var (
+		ctrl        = gomock.NewController(t)
+		mockGateway = mocks.NewMockGateway(ctrl)
+
+		expPayment = &paymentsV1.Payment{
+			Id: uuid.NewV4().String(),
+			Amount: &amountV1.Money{
+				MinorUnits: 2212,
+				Currency:   "GBP",
+			},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_PENDING,
+			CreatedAt:     timestamppb.Now(),
+		}
+	)
+
+	mockGateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(expPayment, nil)
+
+	h, err := transporthttp.NewHandler(mockGateway)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+
+	h.AuthorizeHandler(recorder, httptest.NewRequest(http.MethodPost, "/authorize", bytes.NewReader(validAuthorizationRequest)))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	respBody, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	var paymentResponse paymentsV1.Payment
+	require.NoError(t, protojson.Unmarshal(respBody, &paymentResponse))
+	assert.Equal(t, expPayment.String(), paymentResponse.String())
+}
+
+func TestHandler_IncrementAuthorizationHandler(t *testing.T) {
+	t.Parallel()
+	var (
+		validRequest = transporthttp.CreateIncrementRequest{
+			PaymentID: uuid.NewV4().String(),
+			Amount:    2500,
+		}
+		expPayment = &paymentsV1.Payment{
+			Id:            validRequest.PaymentID,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+		}
+	)
+
+	for _, tc := range []struct {
+		description     string
+		request         transporthttp.CreateIncrementRequest
+		expStatusCode   int
+		responseMessage string
+		fn              func(mocks *mocks.MockGateway)
+	}{
+		{
+			description:     "should return error given that the payment id is empty",
+			request:         transporthttp.CreateIncrementRequest{Amount: validRequest.Amount},
+			responseMessage: "invalid payment_id: cannot be empty",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error given that the amount is zero",
+			request:         transporthttp.CreateIncrementRequest{PaymentID: validRequest.PaymentID},
+			responseMessage: "invalid amount: cannot be zero",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error if the payment is not found",
+			request:         validRequest,
+			responseMessage: "payment not found",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().IncrementAuthorization(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrNoPayment)
+			},
+			expStatusCode: http.StatusNotFound,
+		},
+		{
+			description:     "should return error if the authorization has expired",
+			request:         validRequest,
+			responseMessage: "incremental authorization not allowed: authorization expired",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().IncrementAuthorization(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrAuthorizationExpired)
+			},
+			expStatusCode: http.StatusForbidden,
+		},
+		{
+			description:     "should return error if the increment is not allowed",
+			request:         validRequest,
+			responseMessage: "incremental authorization not allowed",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().IncrementAuthorization(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.TransitionError{
+					PaymentType: domain.PaymentTypeIncrementalAuthorization,
+					From:        domain.PaymentStatusCaptured,
+				})
+			},
+			expStatusCode: http.StatusForbidden,
+		},
+		{
+			description:     "should return the incremented payment",
+			request:         validRequest,
+			responseMessage: validRequest.PaymentID,
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().IncrementAuthorization(gomock.Any(), validRequest.PaymentID, uint64(2500)).Return(expPayment, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl        = gomock.NewController(t)
+				mockGateway = mocks.NewMockGateway(ctrl)
+			)
+			if tc.fn != nil {
+				tc.fn(mockGateway)
+			}
+
+			h, err := transporthttp.NewHandler(mockGateway)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(&tc.request)
+			require.NoError(t, err)
+
+			h.IncrementAuthorizationHandler(recorder, httptest.NewRequest(http.MethodPost, "/increment", bytes.NewReader(b)))
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), tc.responseMessage)
+		})
+	}
+}
+
+func TestHandler_ReverseHandler(t *testing.T) {
+	t.Parallel()
+	var (
+		validRequest = transporthttp.CreateReverseRequest{
+			PaymentID: uuid.NewV4().String(),
+			Amount:    300,
+		}
+		expPayment = &paymentsV1.Payment{
+			Id:            validRequest.PaymentID,
+			Amount:        &amountV1.Money{MinorUnits: 1000, Currency: "GBP"},
+			PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
+		}
+	)
+
+	for _, tc := range []struct {
+		description     string
+		request         transporthttp.CreateReverseRequest
+		expStatusCode   int
+		responseMessage string
+		fn              func(mocks *mocks.MockGateway)
+	}{
+		{
+			description:     "should return error given that the payment id is empty",
+			request:         transporthttp.CreateReverseRequest{Amount: validRequest.Amount},
+			responseMessage: "invalid payment_id: cannot be empty",
+			expStatusCode:   http.StatusUnprocessableEntity,
+		},
+		{
+			description:     "should return error if the payment is not found",
+			request:         validRequest,
+			responseMessage: "payment not found",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().Reverse(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.ErrNoPayment)
+			},
+			expStatusCode: http.StatusNotFound,
+		},
+		{
+			description:     "should return error if the reversal is not allowed",
+			request:         validRequest,
+			responseMessage: "reversal not allowed",
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().Reverse(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, domain.TransitionError{
+					PaymentType: domain.PaymentTypeReversal,
+					From:        domain.PaymentStatusCaptured,
+				})
+			},
+			expStatusCode: http.StatusForbidden,
+		},
+		{
+			description:     "should reverse all that is left uncaptured given the amount is zero",
+			request:         transporthttp.CreateReverseRequest{PaymentID: validRequest.PaymentID},
+			responseMessage: validRequest.PaymentID,
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().Reverse(gomock.Any(), validRequest.PaymentID, uint64(0)).Return(expPayment, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			description:     "should return the reversed payment",
+			request:         validRequest,
+			responseMessage: validRequest.PaymentID,
+			fn: func(mocks *mocks.MockGateway) {
+				mocks.EXPECT().Reverse(gomock.Any(), validRequest.PaymentID, uint64(300)).Return(expPayment, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl        = gomock.NewController(t)
+				mockGateway = mocks.NewMockGateway(ctrl)
+			)
+			if tc.fn != nil {
+				tc.fn(mockGateway)
+			}
+
+			h, err := transporthttp.NewHandler(mockGateway)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(&tc.request)
+			require.NoError(t, err)
+
+			h.ReverseHandler(recorder, httptest.NewRequest(http.MethodPost, "/reverse", bytes.NewReader(b)))
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), tc.responseMessage)
+		})
+	}
+}
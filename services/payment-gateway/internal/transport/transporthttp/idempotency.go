@@ -0,0 +1,205 @@
+//go:generate mockgen -source=idempotency.go -destination=mocks/mock_idempotency.go -package=mocks
+package transporthttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	IdempotencyKeyMaxLen     = 255
+	// IdempotencyKeyInProgressTTL is how long a key stays reserved for a request that never completed, such as when
+	// the instance processing it stopped, before a retry may reclaim it. It matches the default recovery threshold so
+	// that any action left without an outcome is resolved before the request can be retried.
+	IdempotencyKeyInProgressTTL = 5 * time.Minute
+	// idempotencyStoreTimeout bounds storing or releasing a key once the request has been handled.
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+type IdempotencyStore interface {
+	GetIdempotencyKey(ctx context.Context, key string) (*domain.IdempotencyKey, error)
+	CreateIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey) error
+	CompleteIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey) error
+	ReclaimIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey, staleBefore time.Time) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header safe to retry.
+// The first request reserves the key along with a fingerprint of the request, successful
+// responses are then persisted and replayed for any retry. Client errors, which are raised
+// before anything is changed, release the key, as do server errors whose problem is known to
+// be free of side effects, such as the issuer being unavailable whilst its circuit breaker is
+// open. Any other server error is persisted as the request may have reached the issuer before
+// failing, a timed out authorization for one. Reusing a key with a different
+// request is rejected, as is a retry made whilst the original request is still in progress.
+// Requests without the header are passed straight through.
+func IdempotencyMiddleware(store IdempotencyStore) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > IdempotencyKeyMaxLen {
+				writeProblem(w, r, domain.Problem{
+					Code:    domain.ErrorCodeInvalidRequest,
+					Message: "invalid Idempotency-Key: cannot exceed 255 characters",
+					Field:   IdempotencyKeyHeader,
+				})
+				return
+			}
+
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				invalidRequest(w, r, "invalid payload")
+				return
+			}
+			r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			logFields := log.Fields{
+				"idempotency_key": key,
+				"url":             r.URL.Path,
+			}
+
+			idempotencyKey := &domain.IdempotencyKey{
+				Key:         key,
+				RequestPath: r.URL.Path,
+				Fingerprint: fingerprint(r, body),
+			}
+			if err = store.CreateIdempotencyKey(r.Context(), idempotencyKey); err != nil {
+				if !errors.Is(err, domain.ErrIdempotencyKeyExists) {
+					logFields["error"] = err
+					log.WithFields(logFields).Error("failed to create idempotency key")
+					writeProblem(w, r, domain.ProblemInternal)
+					return
+				}
+				if !replayIdempotentResponse(w, r, store, idempotencyKey, logFields) {
+					return
+				}
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// the client may have gone away, the key is still stored or released without it.
+			ctx, cancel := detachedContext(r.Context())
+			defer cancel()
+			if recorder.sideEffectFree || recorder.status >= http.StatusBadRequest && recorder.status < http.StatusInternalServerError {
+				if err = store.DeleteIdempotencyKey(ctx, key); err != nil {
+					logFields["error"] = err
+					log.WithFields(logFields).Error("failed to release idempotency key")
+				}
+				return
+			}
+			idempotencyKey.ResponseStatus = sql.NullInt32{Int32: int32(recorder.status), Valid: true}
+			idempotencyKey.ResponseBody = recorder.body.Bytes()
+			if err = store.CompleteIdempotencyKey(ctx, idempotencyKey); err != nil {
+				logFields["error"] = err
+				log.WithFields(logFields).Error("failed to store idempotent response")
+			}
+		})
+	}
+}
+
+// detachedContext returns a context scoped to the same merchant as the request's that is not cancelled along with it.
+func detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.Background()
+	if merchantID, ok := domain.MerchantFromContext(ctx); ok {
+		detached = domain.ContextWithMerchant(detached, merchantID)
+	}
+	return context.WithTimeout(detached, idempotencyStoreTimeout)
+}
+
+// replayIdempotentResponse responds to a retry of the request the key was reserved for, returning true when the key
+// was reclaimed from a request that never completed and the retry should be handled in its place.
+func replayIdempotentResponse(w http.ResponseWriter, r *http.Request, store IdempotencyStore, requested *domain.IdempotencyKey, logFields log.Fields) bool {
+	existing, err := store.GetIdempotencyKey(r.Context(), requested.Key)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoIdempotencyKey) {
+			// the original request failed and released the key in between
+			writeProblem(w, r, domain.ProblemOf(err, ""))
+			return false
+		}
+		logFields["error"] = err
+		log.WithFields(logFields).Error("failed to get idempotency key")
+		writeProblem(w, r, domain.ProblemInternal)
+		return false
+	}
+	if existing.Fingerprint != requested.Fingerprint {
+		writeProblem(w, r, domain.ProblemOf(domain.ErrIdempotencyKeyMismatch, ""))
+		return false
+	}
+	if !existing.CompletedAt.Valid {
+		if time.Since(existing.CreatedAt) < IdempotencyKeyInProgressTTL {
+			writeProblem(w, r, domain.ProblemOf(domain.ErrIdempotencyKeyExists, ""))
+			return false
+		}
+		if err = store.ReclaimIdempotencyKey(r.Context(), requested, time.Now().Add(-IdempotencyKeyInProgressTTL)); err != nil {
+			if errors.Is(err, domain.ErrIdempotencyKeyExists) {
+				writeProblem(w, r, domain.ProblemOf(err, ""))
+				return false
+			}
+			logFields["error"] = err
+			log.WithFields(logFields).Error("failed to reclaim idempotency key")
+			writeProblem(w, r, domain.ProblemInternal)
+			return false
+		}
+		return true
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(int(existing.ResponseStatus.Int32))
+	if _, err = w.Write(existing.ResponseBody); err != nil {
+		logFields["error"] = err
+		log.WithFields(logFields).Error("failed to replay idempotent response")
+	}
+	return false
+}
+
+// fingerprint identifies a request by its method, path and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte(r.URL.Path))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder captures the response written by a handler whilst passing it through.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+	// sideEffectFree is set when the problem written left nothing to be replayed.
+	sideEffectFree bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
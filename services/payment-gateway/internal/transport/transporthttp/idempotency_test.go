@@ -0,0 +1,312 @@
+package transporthttp_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const (
+		key          = "a6921fc3-a7e3-4661-909b-b3c6c77837ce"
+		responseBody = `{"id":"a6921fc3-a7e3-4661-909b-b3c6c77837ce"}`
+	)
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/capture", bytes.NewReader([]byte(body)))
+		req.Header.Set(transporthttp.IdempotencyKeyHeader, key)
+		return req
+	}
+
+	// mirrors how the middleware fingerprints a request to /capture.
+	fingerprintOf := func(body string) string {
+		sum := sha256.Sum256([]byte(http.MethodPost + "/capture" + body))
+		return hex.EncodeToString(sum[:])
+	}
+
+	for _, tc := range []struct {
+		description   string
+		request       func() *http.Request
+		fn            func(store *mocks.MockIdempotencyStore)
+		handlerStatus int
+		expCalled     bool
+		expStatusCode int
+		expBody       string
+		expReplayed   bool
+	}{
+		{
+			description: "should pass through requests without an idempotency key",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/capture", bytes.NewReader([]byte(`{}`)))
+			},
+			handlerStatus: http.StatusOK,
+			expCalled:     true,
+			expStatusCode: http.StatusOK,
+			expBody:       responseBody,
+		},
+		{
+			description: "should reject an idempotency key that is too long",
+			request: func() *http.Request {
+				req := newRequest(`{}`)
+				req.Header.Set(transporthttp.IdempotencyKeyHeader, string(make([]byte, transporthttp.IdempotencyKeyMaxLen+1)))
+				return req
+			},
+			expStatusCode: http.StatusBadRequest,
+			expBody:       "invalid Idempotency-Key",
+		},
+		{
+			description: "should store the response of a successful request",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CompleteIdempotencyKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ interface{}, k *domain.IdempotencyKey) error {
+						assert.Equal(t, key, k.Key)
+						assert.Equal(t, "/capture", k.RequestPath)
+						assert.Equal(t, int32(http.StatusOK), k.ResponseStatus.Int32)
+						assert.Equal(t, responseBody, string(k.ResponseBody))
+						return nil
+					})
+			},
+			handlerStatus: http.StatusOK,
+			expCalled:     true,
+			expStatusCode: http.StatusOK,
+			expBody:       responseBody,
+		},
+		{
+			description: "should release the key given the request was unsuccessful",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().DeleteIdempotencyKey(gomock.Any(), key).Return(nil)
+			},
+			handlerStatus: http.StatusForbidden,
+			expCalled:     true,
+			expStatusCode: http.StatusForbidden,
+		},
+		{
+			description: "should store the response given a server error as the request may have reached the issuer",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CompleteIdempotencyKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ interface{}, k *domain.IdempotencyKey) error {
+						assert.Equal(t, int32(http.StatusServiceUnavailable), k.ResponseStatus.Int32)
+						return nil
+					})
+			},
+			handlerStatus: http.StatusServiceUnavailable,
+			expCalled:     true,
+			expStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			description: "should release the key given the client went away",
+			request: func() *http.Request {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return newRequest(`{"amount":10}`).WithContext(ctx)
+			},
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().DeleteIdempotencyKey(gomock.Any(), key).
+					DoAndReturn(func(ctx context.Context, _ string) error {
+						assert.NoError(t, ctx.Err())
+						return nil
+					})
+			},
+			handlerStatus: http.StatusNotFound,
+			expCalled:     true,
+			expStatusCode: http.StatusNotFound,
+		},
+		{
+			description: "should return error given unable to create key",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+			expBody:       "Oops something went wrong",
+		},
+		{
+			description: "should replay the stored response given a retry",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), key).Return(&domain.IdempotencyKey{
+					Key:            key,
+					Fingerprint:    fingerprintOf(`{"amount":10}`),
+					ResponseStatus: sql.NullInt32{Int32: http.StatusOK, Valid: true},
+					ResponseBody:   []byte(responseBody),
+					CompletedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+			},
+			expStatusCode: http.StatusOK,
+			expBody:       responseBody,
+			expReplayed:   true,
+		},
+		{
+			description: "should reject a reused key given a different request",
+			request:     func() *http.Request { return newRequest(`{"amount":20}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), key).Return(&domain.IdempotencyKey{
+					Key:         key,
+					Fingerprint: fingerprintOf(`{"amount":10}`),
+					CompletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+			},
+			expStatusCode: http.StatusUnprocessableEntity,
+			expBody:       domain.ErrIdempotencyKeyMismatch.Error(),
+		},
+		{
+			description: "should return conflict given the original request is in progress",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), key).Return(&domain.IdempotencyKey{
+					Key:         key,
+					Fingerprint: fingerprintOf(`{"amount":10}`),
+					CreatedAt:   time.Now(),
+				}, nil)
+			},
+			expStatusCode: http.StatusConflict,
+			expBody:       "in progress",
+		},
+		{
+			description: "should handle the retry given the original request never completed",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), key).Return(&domain.IdempotencyKey{
+					Key:         key,
+					Fingerprint: fingerprintOf(`{"amount":10}`),
+					CreatedAt:   time.Now().Add(-transporthttp.IdempotencyKeyInProgressTTL - time.Second),
+				}, nil)
+				store.EXPECT().ReclaimIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CompleteIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			handlerStatus: http.StatusOK,
+			expCalled:     true,
+			expStatusCode: http.StatusOK,
+			expBody:       responseBody,
+		},
+		{
+			description: "should return conflict given another retry reclaimed the key first",
+			request:     func() *http.Request { return newRequest(`{"amount":10}`) },
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyExists)
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), key).Return(&domain.IdempotencyKey{
+					Key:         key,
+					Fingerprint: fingerprintOf(`{"amount":10}`),
+					CreatedAt:   time.Now().Add(-transporthttp.IdempotencyKeyInProgressTTL - time.Second),
+				}, nil)
+				store.EXPECT().ReclaimIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.ErrIdempotencyKeyExists)
+			},
+			expStatusCode: http.StatusConflict,
+			expBody:       "in progress",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl      = gomock.NewController(t)
+				mockStore = mocks.NewMockIdempotencyStore(ctrl)
+				called    bool
+			)
+			if tc.fn != nil {
+				tc.fn(mockStore)
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(tc.handlerStatus)
+				_, _ = w.Write([]byte(responseBody))
+			})
+
+			recorder := httptest.NewRecorder()
+			transporthttp.IdempotencyMiddleware(mockStore)(next).ServeHTTP(recorder, tc.request())
+
+			assert.Equal(t, tc.expCalled, called)
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), tc.expBody)
+			assert.Equal(t, tc.expReplayed, recorder.Header().Get(transporthttp.IdempotentReplayedHeader) == "true")
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_IssuerUnavailable(t *testing.T) {
+	t.Parallel()
+
+	const key = "a6921fc3-a7e3-4661-909b-b3c6c77837ce"
+
+	for _, tc := range []struct {
+		description string
+		err         error
+		fn          func(store *mocks.MockIdempotencyStore)
+	}{
+		{
+			description: "should release the key given the issuer's circuit breaker was open as nothing was sent",
+			err:         domain.ErrCircuitOpen,
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().DeleteIdempotencyKey(gomock.Any(), key).Return(nil)
+			},
+		},
+		{
+			description: "should store the response given the issuer timed out as the request may have reached it",
+			err:         domain.ErrIssuerUnavailable,
+			fn: func(store *mocks.MockIdempotencyStore) {
+				store.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+				store.EXPECT().CompleteIdempotencyKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ interface{}, k *domain.IdempotencyKey) error {
+						assert.Equal(t, int32(http.StatusServiceUnavailable), k.ResponseStatus.Int32)
+						return nil
+					})
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl        = gomock.NewController(t)
+				mockStore   = mocks.NewMockIdempotencyStore(ctrl)
+				mockGateway = mocks.NewMockGateway(ctrl)
+			)
+			tc.fn(mockStore)
+			mockGateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tc.err)
+
+			h, err := transporthttp.NewHandler(mockGateway)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/authorize", bytes.NewReader(validAuthorizationRequest))
+			req.Header.Set(transporthttp.IdempotencyKeyHeader, key)
+
+			recorder := httptest.NewRecorder()
+			transporthttp.IdempotencyMiddleware(mockStore)(http.HandlerFunc(h.AuthorizeHandler)).ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			respBody, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(respBody), "issuer unavailable, try again later")
+		})
+	}
+}
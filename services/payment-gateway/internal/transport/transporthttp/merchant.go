@@ -0,0 +1,198 @@
+//go:generate mockgen -source=merchant.go -destination=mocks/mock_merchant.go -package=mocks
+package transporthttp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const MerchantNameMaxLen = 255
+
+type Merchants interface {
+	CreateMerchant(ctx context.Context, name string) (*domain.Merchant, string, error)
+	RotateAPIKey(ctx context.Context, merchantID string) (string, error)
+	SetAcceptedCardBrands(ctx context.Context, merchantID string, brands []domain.CardBrand) (*domain.Merchant, error)
+	Authenticate(ctx context.Context, apiKey string) (string, error)
+}
+
+// MerchantHandler authenticates merchants by their api keys and serves the admin endpoints managing them.
+type MerchantHandler struct {
+	merchants Merchants
+	adminKey  string
+}
+
+// NewMerchantHandler returns the handler, the admin endpoints are disabled when adminKey is empty.
+func NewMerchantHandler(merchants Merchants, adminKey string) (MerchantHandler, error) {
+	if merchants == nil {
+		return MerchantHandler{}, errors.New("merchants is nil")
+	}
+	return MerchantHandler{merchants: merchants, adminKey: adminKey}, nil
+}
+
+// bearerToken returns the token of the request's Authorization header.
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeProblem(w, r, domain.ProblemOf(domain.ErrNoAPIKey, ""))
+}
+
+// AuthMiddleware authenticates the merchant by the api key sent as a bearer token, scoping the request to them.
+func (h MerchantHandler) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey := bearerToken(r)
+		if apiKey == "" {
+			unauthorized(w, r)
+			return
+		}
+		merchantID, err := h.merchants.Authenticate(r.Context(), apiKey)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNoAPIKey) {
+				log.WithError(err).WithField("url", r.URL.Path).Error("failed to authenticate merchant")
+				writeProblem(w, r, domain.ProblemInternal)
+				return
+			}
+			unauthorized(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithMerchant(r.Context(), merchantID)))
+	})
+}
+
+// AdminMiddleware only lets through requests bearing the admin key.
+func (h MerchantHandler) AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.adminKey == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(bearerToken(r)), []byte(h.adminKey)) != 1 {
+			unauthorized(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h MerchantHandler) CreateMerchantHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		invalidRequest(w, r, "no body supplied")
+		return
+	}
+	defer r.Body.Close()
+
+	var merchantRequest CreateMerchantRequest
+	if err := json.NewDecoder(r.Body).Decode(&merchantRequest); err != nil {
+		invalidRequest(w, r, "invalid payload")
+		return
+	}
+	if err := merchantRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
+		return
+	}
+	merchantRequest.Name = strings.TrimSpace(merchantRequest.Name)
+
+	merchant, apiKey, err := h.merchants.CreateMerchant(r.Context(), merchantRequest.Name)
+	if err != nil {
+		log.WithError(err).WithField("url", "/admin/merchants").Error("failed to create merchant")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(MerchantResponse{
+		ID:        merchant.ID.String(),
+		Name:      merchant.Name,
+		APIKey:    apiKey,
+		CreatedAt: merchant.CreatedAt,
+	}); err != nil {
+		log.WithError(err).Error("failed to write merchant response")
+	}
+}
+
+func (h MerchantHandler) RotateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	merchantID := mux.Vars(r)["id"]
+	logFields := log.Fields{
+		"merchant.id": merchantID,
+		"url":         "/admin/merchants/{id}/api-keys",
+	}
+
+	apiKey, err := h.merchants.RotateAPIKey(r.Context(), merchantID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoMerchant) {
+			writeProblem(w, r, domain.ProblemOf(err, ""))
+			return
+		}
+		logFields["error"] = err
+		log.WithFields(logFields).Error("failed to rotate api key")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(APIKeyResponse{MerchantID: merchantID, APIKey: apiKey}); err != nil {
+		log.WithError(err).Error("failed to write api key response")
+	}
+}
+
+func (h MerchantHandler) AcceptedCardBrandsHandler(w http.ResponseWriter, r *http.Request) {
+	merchantID := mux.Vars(r)["id"]
+	logFields := log.Fields{
+		"merchant.id": merchantID,
+		"url":         "/admin/merchants/{id}/card-brands",
+	}
+	if r.Body == http.NoBody {
+		invalidRequest(w, r, "no body supplied")
+		return
+	}
+	defer r.Body.Close()
+
+	var brandsRequest AcceptedCardBrandsRequest
+	if err := json.NewDecoder(r.Body).Decode(&brandsRequest); err != nil {
+		invalidRequest(w, r, "invalid payload")
+		return
+	}
+	if err := brandsRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
+		return
+	}
+	brands := make([]domain.CardBrand, 0, len(brandsRequest.AcceptedCardBrands))
+	for _, brand := range brandsRequest.AcceptedCardBrands {
+		brands = append(brands, domain.CardBrand(brand))
+	}
+
+	merchant, err := h.merchants.SetAcceptedCardBrands(r.Context(), merchantID, brands)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoMerchant) {
+			writeProblem(w, r, domain.ProblemOf(err, ""))
+			return
+		}
+		logFields["error"] = err
+		log.WithFields(logFields).Error("failed to set accepted card brands")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	response := AcceptedCardBrandsResponse{MerchantID: merchantID, AcceptedCardBrands: []string{}}
+	for _, brand := range merchant.AcceptedCardBrands {
+		response.AcceptedCardBrands = append(response.AcceptedCardBrands, string(brand))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		log.WithError(err).Error("failed to write accepted card brands response")
+	}
+}
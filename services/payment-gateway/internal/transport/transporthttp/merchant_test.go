@@ -0,0 +1,333 @@
+package transporthttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey   = "sk_test"
+	testAdminKey = "admin"
+)
+
+var testMerchantID = uuid.NewV4().String()
+
+// merchantRoutes returns the routes with testAPIKey authenticating as testMerchantID.
+func merchantRoutes(t *testing.T, h transporthttp.Handler) *mux.Router {
+	return merchantWebhookRoutes(t, h, transporthttp.WebhookHandler{})
+}
+
+func merchantWebhookRoutes(t *testing.T, h transporthttp.Handler, wh transporthttp.WebhookHandler) *mux.Router {
+	merchants := mocks.NewMockMerchants(gomock.NewController(t))
+	merchants.EXPECT().Authenticate(gomock.Any(), testAPIKey).Return(testMerchantID, nil).AnyTimes()
+	m, err := transporthttp.NewMerchantHandler(merchants, testAdminKey)
+	require.NoError(t, err)
+	return transporthttp.HandleRoutes(h, m, wh, nil)
+}
+
+func withAPIKey(r *http.Request) *http.Request {
+	r.Header.Set("Authorization", "Bearer "+testAPIKey)
+	return r
+}
+
+func TestMerchantHandler_AuthMiddleware(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description   string
+		authorization string
+		fn            func(merchants *mocks.MockMerchants)
+		expStatusCode int
+	}{
+		{
+			description:   "should scope the request to the authenticated merchant",
+			authorization: "Bearer sk_valid",
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().Authenticate(gomock.Any(), "sk_valid").Return(testMerchantID, nil)
+			},
+			expStatusCode: http.StatusOK,
+		},
+		{
+			description:   "should return unauthorized given no api key",
+			expStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:   "should return unauthorized given the api key is not a bearer token",
+			authorization: "Basic sk_valid",
+			expStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:   "should return unauthorized given an unknown or revoked api key",
+			authorization: "Bearer sk_revoked",
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().Authenticate(gomock.Any(), "sk_revoked").Return("", domain.ErrNoAPIKey)
+			},
+			expStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:   "should return error given authentication fails",
+			authorization: "Bearer sk_valid",
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().Authenticate(gomock.Any(), "sk_valid").Return("", errors.New("boom"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			merchants := mocks.NewMockMerchants(gomock.NewController(t))
+			if tc.fn != nil {
+				tc.fn(merchants)
+			}
+			m, err := transporthttp.NewMerchantHandler(merchants, testAdminKey)
+			require.NoError(t, err)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				merchantID, ok := domain.MerchantFromContext(r.Context())
+				require.True(t, ok)
+				assert.Equal(t, testMerchantID, merchantID)
+			})
+			request := httptest.NewRequest(http.MethodGet, "/payments", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			m.AuthMiddleware(next).ServeHTTP(recorder, request)
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestMerchantHandler_CreateMerchantHandler(t *testing.T) {
+	t.Parallel()
+	merchant := &domain.Merchant{ID: uuid.NewV4(), Name: "merchant", CreatedAt: time.Now().UTC()}
+
+	for _, tc := range []struct {
+		description     string
+		adminKey        string
+		authorization   string
+		body            string
+		fn              func(merchants *mocks.MockMerchants)
+		expStatusCode   int
+		responseMessage string
+	}{
+		{
+			description:   "should create the merchant returning its api key",
+			adminKey:      testAdminKey,
+			authorization: "Bearer " + testAdminKey,
+			body:          `{"name":"merchant"}`,
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().CreateMerchant(gomock.Any(), "merchant").Return(merchant, "sk_new", nil)
+			},
+			expStatusCode:   http.StatusCreated,
+			responseMessage: `"api_key":"sk_new"`,
+		},
+		{
+			description:   "should return unauthorized given a merchant's api key",
+			adminKey:      testAdminKey,
+			authorization: "Bearer " + testAPIKey,
+			body:          `{"name":"merchant"}`,
+			expStatusCode: http.StatusUnauthorized,
+		},
+		{
+			description:   "should return not found given no admin key is configured",
+			authorization: "Bearer ",
+			body:          `{"name":"merchant"}`,
+			expStatusCode: http.StatusNotFound,
+		},
+		{
+			description:     "should return error given no name",
+			adminKey:        testAdminKey,
+			authorization:   "Bearer " + testAdminKey,
+			body:            `{"name":" "}`,
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: "invalid name: cannot be empty",
+		},
+		{
+			description:   "should return error given the merchant cannot be created",
+			adminKey:      testAdminKey,
+			authorization: "Bearer " + testAdminKey,
+			body:          `{"name":"merchant"}`,
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().CreateMerchant(gomock.Any(), "merchant").Return(nil, "", errors.New("boom"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			merchants := mocks.NewMockMerchants(gomock.NewController(t))
+			if tc.fn != nil {
+				tc.fn(merchants)
+			}
+			m, err := transporthttp.NewMerchantHandler(merchants, tc.adminKey)
+			require.NoError(t, err)
+			h, err := transporthttp.NewHandler(mocks.NewMockGateway(gomock.NewController(t)))
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/admin/merchants", strings.NewReader(tc.body))
+			request.Header.Set("Authorization", tc.authorization)
+			recorder := httptest.NewRecorder()
+			transporthttp.HandleRoutes(h, m, transporthttp.WebhookHandler{}, nil).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.responseMessage)
+			if tc.expStatusCode == http.StatusCreated {
+				var response transporthttp.MerchantResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+				assert.Equal(t, merchant.ID.String(), response.ID)
+				assert.Equal(t, merchant.Name, response.Name)
+			}
+		})
+	}
+}
+
+func TestMerchantHandler_RotateAPIKeyHandler(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description     string
+		fn              func(merchants *mocks.MockMerchants)
+		expStatusCode   int
+		responseMessage string
+	}{
+		{
+			description: "should return the new api key",
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().RotateAPIKey(gomock.Any(), testMerchantID).Return("sk_rotated", nil)
+			},
+			expStatusCode:   http.StatusCreated,
+			responseMessage: `"api_key":"sk_rotated"`,
+		},
+		{
+			description: "should return not found given the merchant does not exist",
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().RotateAPIKey(gomock.Any(), testMerchantID).Return("", domain.ErrNoMerchant)
+			},
+			expStatusCode:   http.StatusNotFound,
+			responseMessage: "merchant not found",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			merchants := mocks.NewMockMerchants(gomock.NewController(t))
+			tc.fn(merchants)
+			m, err := transporthttp.NewMerchantHandler(merchants, testAdminKey)
+			require.NoError(t, err)
+			h, err := transporthttp.NewHandler(mocks.NewMockGateway(gomock.NewController(t)))
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPost, "/admin/merchants/"+testMerchantID+"/api-keys", nil)
+			request.Header.Set("Authorization", "Bearer "+testAdminKey)
+			recorder := httptest.NewRecorder()
+			transporthttp.HandleRoutes(h, m, transporthttp.WebhookHandler{}, nil).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.responseMessage)
+		})
+	}
+}
+
+func TestMerchantHandler_AcceptedCardBrandsHandler(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		description     string
+		body            string
+		fn              func(merchants *mocks.MockMerchants)
+		expStatusCode   int
+		responseMessage string
+	}{
+		{
+			description: "should set the brands the merchant accepts",
+			body:        `{"accepted_card_brands":["visa","amex"]}`,
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().SetAcceptedCardBrands(gomock.Any(), testMerchantID,
+					[]domain.CardBrand{domain.CardBrandVisa, domain.CardBrandAmex}).
+					Return(&domain.Merchant{AcceptedCardBrands: []domain.CardBrand{domain.CardBrandVisa, domain.CardBrandAmex}}, nil)
+			},
+			expStatusCode:   http.StatusOK,
+			responseMessage: `"accepted_card_brands":["visa","amex"]`,
+		},
+		{
+			description: "should accept every brand given none",
+			body:        `{"accepted_card_brands":[]}`,
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().SetAcceptedCardBrands(gomock.Any(), testMerchantID, []domain.CardBrand{}).
+					Return(&domain.Merchant{}, nil)
+			},
+			expStatusCode:   http.StatusOK,
+			responseMessage: `"accepted_card_brands":[]`,
+		},
+		{
+			description:     "should return error given an unknown brand",
+			body:            `{"accepted_card_brands":["visa","carte"]}`,
+			fn:              func(merchants *mocks.MockMerchants) {},
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: `invalid accepted_card_brands[1]: unknown card brand \"carte\"`,
+		},
+		{
+			description:     "should return error given no body",
+			fn:              func(merchants *mocks.MockMerchants) {},
+			expStatusCode:   http.StatusBadRequest,
+			responseMessage: "no body supplied",
+		},
+		{
+			description: "should return not found given the merchant does not exist",
+			body:        `{"accepted_card_brands":["visa"]}`,
+			fn: func(merchants *mocks.MockMerchants) {
+				merchants.EXPECT().SetAcceptedCardBrands(gomock.Any(), testMerchantID, gomock.Any()).
+					Return(nil, domain.ErrNoMerchant)
+			},
+			expStatusCode:   http.StatusNotFound,
+			responseMessage: "merchant not found",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			merchants := mocks.NewMockMerchants(gomock.NewController(t))
+			tc.fn(merchants)
+			m, err := transporthttp.NewMerchantHandler(merchants, testAdminKey)
+			require.NoError(t, err)
+			h, err := transporthttp.NewHandler(mocks.NewMockGateway(gomock.NewController(t)))
+			require.NoError(t, err)
+
+			request := httptest.NewRequest(http.MethodPut, "/admin/merchants/"+testMerchantID+"/card-brands", nil)
+			if tc.body != "" {
+				request = httptest.NewRequest(http.MethodPut, "/admin/merchants/"+testMerchantID+"/card-brands", strings.NewReader(tc.body))
+			}
+			request.Header.Set("Authorization", "Bearer "+testAdminKey)
+			recorder := httptest.NewRecorder()
+			transporthttp.HandleRoutes(h, m, transporthttp.WebhookHandler{}, nil).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.responseMessage)
+		})
+	}
+}
+
+func TestHandleRoutes_Unauthenticated(t *testing.T) {
+	t.Parallel()
+	h, err := transporthttp.NewHandler(mocks.NewMockGateway(gomock.NewController(t)))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	merchantRoutes(t, h).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/payments/abc", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+}
@@ -38,33 +38,93 @@ func (m *MockGateway) EXPECT() *MockGatewayMockRecorder {
 }
 
 // Capture mocks base method.
-func (m *MockGateway) Capture(ctx context.Context, paymentID string, amount uint64) (*v10.Payment, error) {
+func (m *MockGateway) Capture(ctx context.Context, paymentID string, amount uint64, final bool) (*v10.Payment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Capture", ctx, paymentID, amount)
+	ret := m.ctrl.Call(m, "Capture", ctx, paymentID, amount, final)
 	ret0, _ := ret[0].(*v10.Payment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Capture indicates an expected call of Capture.
-func (mr *MockGatewayMockRecorder) Capture(ctx, paymentID, amount interface{}) *gomock.Call {
+func (mr *MockGatewayMockRecorder) Capture(ctx, paymentID, amount, final interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockGateway)(nil).Capture), ctx, paymentID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockGateway)(nil).Capture), ctx, paymentID, amount, final)
 }
 
 // CreatePayment mocks base method.
-func (m *MockGateway) CreatePayment(ctx context.Context, amount *v1.Money, method domain.PaymentMethod) (*v10.Payment, error) {
+func (m *MockGateway) CreatePayment(ctx context.Context, amount *v1.Money, settlementCurrency string, method domain.PaymentMethod) (*v10.Payment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePayment", ctx, amount, method)
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, amount, settlementCurrency, method)
 	ret0, _ := ret[0].(*v10.Payment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreatePayment indicates an expected call of CreatePayment.
-func (mr *MockGatewayMockRecorder) CreatePayment(ctx, amount, method interface{}) *gomock.Call {
+func (mr *MockGatewayMockRecorder) CreatePayment(ctx, amount, settlementCurrency, method interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockGateway)(nil).CreatePayment), ctx, amount, method)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockGateway)(nil).CreatePayment), ctx, amount, settlementCurrency, method)
+}
+
+// GetPayment mocks base method.
+func (m *MockGateway) GetPayment(ctx context.Context, paymentID string) (*v10.PaymentDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayment", ctx, paymentID)
+	ret0, _ := ret[0].(*v10.PaymentDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayment indicates an expected call of GetPayment.
+func (mr *MockGatewayMockRecorder) GetPayment(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockGateway)(nil).GetPayment), ctx, paymentID)
+}
+
+// IncrementAuthorization mocks base method.
+func (m *MockGateway) IncrementAuthorization(ctx context.Context, paymentID string, amount uint64) (*v10.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAuthorization", ctx, paymentID, amount)
+	ret0, _ := ret[0].(*v10.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementAuthorization indicates an expected call of IncrementAuthorization.
+func (mr *MockGatewayMockRecorder) IncrementAuthorization(ctx, paymentID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAuthorization", reflect.TypeOf((*MockGateway)(nil).IncrementAuthorization), ctx, paymentID, amount)
+}
+
+// ListPaymentActions mocks base method.
+func (m *MockGateway) ListPaymentActions(ctx context.Context, paymentID string) ([]*v10.PaymentAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentActions", ctx, paymentID)
+	ret0, _ := ret[0].([]*v10.PaymentAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentActions indicates an expected call of ListPaymentActions.
+func (mr *MockGatewayMockRecorder) ListPaymentActions(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentActions", reflect.TypeOf((*MockGateway)(nil).ListPaymentActions), ctx, paymentID)
+}
+
+// ListPayments mocks base method.
+func (m *MockGateway) ListPayments(ctx context.Context, filters domain.ListPaymentFilters) (*v10.PaymentList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayments", ctx, filters)
+	ret0, _ := ret[0].(*v10.PaymentList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayments indicates an expected call of ListPayments.
+func (mr *MockGatewayMockRecorder) ListPayments(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayments", reflect.TypeOf((*MockGateway)(nil).ListPayments), ctx, filters)
 }
 
 // Refund mocks base method.
@@ -82,6 +142,21 @@ func (mr *MockGatewayMockRecorder) Refund(ctx, paymentID, amount interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockGateway)(nil).Refund), ctx, paymentID, amount)
 }
 
+// Reverse mocks base method.
+func (m *MockGateway) Reverse(ctx context.Context, paymentID string, amount uint64) (*v10.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, paymentID, amount)
+	ret0, _ := ret[0].(*v10.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockGatewayMockRecorder) Reverse(ctx, paymentID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockGateway)(nil).Reverse), ctx, paymentID, amount)
+}
+
 // Void mocks base method.
 func (m *MockGateway) Void(ctx context.Context, paymentID string) (*v10.Payment, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: merchant.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+)
+
+// MockMerchants is a mock of Merchants interface.
+type MockMerchants struct {
+	ctrl     *gomock.Controller
+	recorder *MockMerchantsMockRecorder
+}
+
+// MockMerchantsMockRecorder is the mock recorder for MockMerchants.
+type MockMerchantsMockRecorder struct {
+	mock *MockMerchants
+}
+
+// NewMockMerchants creates a new mock instance.
+func NewMockMerchants(ctrl *gomock.Controller) *MockMerchants {
+	mock := &MockMerchants{ctrl: ctrl}
+	mock.recorder = &MockMerchantsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMerchants) EXPECT() *MockMerchantsMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockMerchants) Authenticate(ctx context.Context, apiKey string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, apiKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockMerchantsMockRecorder) Authenticate(ctx, apiKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockMerchants)(nil).Authenticate), ctx, apiKey)
+}
+
+// CreateMerchant mocks base method.
+func (m *MockMerchants) CreateMerchant(ctx context.Context, name string) (*domain.Merchant, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMerchant", ctx, name)
+	ret0, _ := ret[0].(*domain.Merchant)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateMerchant indicates an expected call of CreateMerchant.
+func (mr *MockMerchantsMockRecorder) CreateMerchant(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMerchant", reflect.TypeOf((*MockMerchants)(nil).CreateMerchant), ctx, name)
+}
+
+// RotateAPIKey mocks base method.
+func (m *MockMerchants) RotateAPIKey(ctx context.Context, merchantID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateAPIKey", ctx, merchantID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateAPIKey indicates an expected call of RotateAPIKey.
+func (mr *MockMerchantsMockRecorder) RotateAPIKey(ctx, merchantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateAPIKey", reflect.TypeOf((*MockMerchants)(nil).RotateAPIKey), ctx, merchantID)
+}
+
+// SetAcceptedCardBrands mocks base method.
+func (m *MockMerchants) SetAcceptedCardBrands(ctx context.Context, merchantID string, brands []domain.CardBrand) (*domain.Merchant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAcceptedCardBrands", ctx, merchantID, brands)
+	ret0, _ := ret[0].(*domain.Merchant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAcceptedCardBrands indicates an expected call of SetAcceptedCardBrands.
+func (mr *MockMerchantsMockRecorder) SetAcceptedCardBrands(ctx, merchantID, brands interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAcceptedCardBrands", reflect.TypeOf((*MockMerchants)(nil).SetAcceptedCardBrands), ctx, merchantID, brands)
+}
@@ -1,6 +1,8 @@
 package transporthttp
 
 import (
+	"time"
+
 	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
 	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
 )
@@ -9,12 +11,29 @@ import (
 type CreateAuthorizationRequest struct {
 	Card   *paymentsV1.PaymentMethodCard
 	Amount *amountV1.Money
+	// SettlementCurrency is the currency the merchant settles in, the amount is converted to it if it differs.
+	SettlementCurrency string `json:"settlement_currency,omitempty"`
 }
 
 // CreateCaptureRequest  is the request used to perform a capture towards a payment.
 type CreateCaptureRequest struct {
 	PaymentID string `json:"payment_id"`
 	Amount    uint64 `json:"amount"`
+	// Final marks the last capture, the remainder of the authorized amount is reversed once it succeeds.
+	Final bool `json:"final,omitempty"`
+}
+
+// CreateIncrementRequest is the request used to increment the authorized amount of a payment.
+type CreateIncrementRequest struct {
+	PaymentID string `json:"payment_id"`
+	Amount    uint64 `json:"amount"`
+}
+
+// CreateReverseRequest is the request used to reverse the uncaptured amount of a payment, a zero amount reversing
+// all of it.
+type CreateReverseRequest struct {
+	PaymentID string `json:"payment_id"`
+	Amount    uint64 `json:"amount"`
 }
 
 // CreateRefundRequest is the request used to create a refund towards a payment
@@ -27,3 +46,98 @@ type CreateRefundRequest struct {
 type CreateVoidRequest struct {
 	PaymentID string `json:"payment_id"`
 }
+
+// CreateMerchantRequest is the request used to create a merchant.
+type CreateMerchantRequest struct {
+	Name string `json:"name"`
+}
+
+// MerchantResponse is returned when a merchant is created, it is the only time its api key is returned.
+type MerchantResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	APIKey    string    `json:"api_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKeyResponse is returned when a merchant's api key is rotated.
+type APIKeyResponse struct {
+	MerchantID string `json:"merchant_id"`
+	APIKey     string `json:"api_key"`
+}
+
+// AcceptedCardBrandsRequest is the request used to set the card brands a merchant accepts, every brand is accepted
+// when empty.
+type AcceptedCardBrandsRequest struct {
+	AcceptedCardBrands []string `json:"accepted_card_brands"`
+}
+
+// AcceptedCardBrandsResponse is the card brands a merchant accepts.
+type AcceptedCardBrandsResponse struct {
+	MerchantID         string   `json:"merchant_id"`
+	AcceptedCardBrands []string `json:"accepted_card_brands"`
+}
+
+// ListPaymentsRequest is the query used to list payments, its values are as given until parsed into filters.
+type ListPaymentsRequest struct {
+	Statuses      []string
+	Currencies    []string
+	MinAmount     string
+	MaxAmount     string
+	CreatedAfter  string
+	CreatedBefore string
+	CardLastFour  string
+	Limit         string
+	Cursor        string
+}
+
+// CreateWebhookEndpointRequest is the request used to register a webhook endpoint.
+type CreateWebhookEndpointRequest struct {
+	URL string `json:"url"`
+}
+
+// WebhookEndpointResponse represents a webhook endpoint, its secret is only returned when registered.
+type WebhookEndpointResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeliveryResponse represents the delivery of an event to a webhook endpoint.
+type WebhookDeliveryResponse struct {
+	ID                 string     `json:"id"`
+	EndpointID         string     `json:"endpoint_id"`
+	EventID            string     `json:"event_id"`
+	PaymentID          string     `json:"payment_id"`
+	EventType          string     `json:"event_type"`
+	Status             string     `json:"status"`
+	Attempts           int        `json:"attempts"`
+	NextAttemptAt      time.Time  `json:"next_attempt_at"`
+	LastResponseStatus *int32     `json:"last_response_status,omitempty"`
+	LastError          string     `json:"last_error,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	DeliveredAt        *time.Time `json:"delivered_at,omitempty"`
+}
+
+// ProblemResponse is returned for every error, it is an RFC 7807 problem details object extended with the error's
+// stable code.
+type ProblemResponse struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	Field         string `json:"field,omitempty"`
+	DeclineReason string `json:"decline_reason,omitempty"`
+	RequestID     string `json:"request_id,omitempty"`
+	Instance      string `json:"instance"`
+	// Errors are every field failing validation.
+	Errors []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+// FieldErrorResponse is a field of a request failing validation.
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
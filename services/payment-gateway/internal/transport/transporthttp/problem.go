@@ -0,0 +1,110 @@
+package transporthttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RequestIDHeader    = "X-Request-Id"
+	ProblemContentType = "application/problem+json"
+	// ProblemTypePrefix prefixes the error code to form the problem's type.
+	ProblemTypePrefix = "urn:payments-api:error:"
+)
+
+type requestIDKey struct{}
+
+// RequestIDMiddleware tags every request with an id, taken from the X-Request-Id header when supplied. The id is
+// echoed in the response and any problem returned so that errors can be traced.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewV4().String()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, requestID)))
+	})
+}
+
+// RequestIDFromContext returns the id the request was tagged with, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// problemStatus returns the HTTP status an error code is reported with.
+func problemStatus(code domain.ErrorCode) int {
+	switch code {
+	case domain.ErrorCodeInvalidRequest:
+		return http.StatusBadRequest
+	case domain.ErrorCodeValidationFailed, domain.ErrorCodeUnknownCardToken, domain.ErrorCodeCardBrandNotAccepted,
+		domain.ErrorCodeFXRateUnavailable, domain.ErrorCodeIdempotencyKeyMismatch:
+		return http.StatusUnprocessableEntity
+	case domain.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrorCodePaymentNotFound, domain.ErrorCodeMerchantNotFound, domain.ErrorCodeWebhookEndpointNotFound,
+		domain.ErrorCodeWebhookDeliveryNotFound:
+		return http.StatusNotFound
+	case domain.ErrorCodeOperationNotPermitted, domain.ErrorCodeAuthorizationExpired, domain.ErrorCodePaymentDeclined:
+		return http.StatusForbidden
+	case domain.ErrorCodeIssuerUnavailable:
+		return http.StatusServiceUnavailable
+	case domain.ErrorCodeIdempotencyKeyInUse:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, problem domain.Problem) {
+	status := problemStatus(problem.Code)
+	if recorder, ok := w.(*responseRecorder); ok && problem.SideEffectFree {
+		recorder.sideEffectFree = true
+	}
+	var fieldErrors []FieldErrorResponse
+	for _, err := range problem.Errors {
+		fieldErrors = append(fieldErrors, FieldErrorResponse{Field: err.Field, Message: err.Message})
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(ProblemResponse{
+		Type:          ProblemTypePrefix + string(problem.Code),
+		Title:         http.StatusText(status),
+		Status:        status,
+		Code:          string(problem.Code),
+		Message:       problem.Message,
+		Field:         problem.Field,
+		DeclineReason: problem.DeclineReason,
+		RequestID:     RequestIDFromContext(r.Context()),
+		Instance:      r.URL.Path,
+		Errors:        fieldErrors,
+	}); err != nil {
+		log.WithError(err).Error("failed to write problem response")
+	}
+}
+
+// invalidRequest writes the problem for a request that could not be read.
+func invalidRequest(w http.ResponseWriter, r *http.Request, message string) {
+	writeProblem(w, r, domain.Problem{Code: domain.ErrorCodeInvalidRequest, Message: message})
+}
+
+// writeError writes the problem the error maps to. Internal errors are logged as their details are hidden from the
+// client.
+func writeError(w http.ResponseWriter, r *http.Request, err error, operation string, logFields log.Fields) {
+	problem := domain.ProblemOf(err, operation)
+	if problem.Code == domain.ErrorCodeInternal {
+		logFields["error"] = err
+		if requestID := RequestIDFromContext(r.Context()); requestID != "" {
+			logFields["request_id"] = requestID
+		}
+		log.WithFields(logFields).Errorf("failed to process %s request", operation)
+	}
+	writeProblem(w, r, problem)
+}
@@ -0,0 +1,107 @@
+package transporthttp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemResponse(t *testing.T) {
+	t.Parallel()
+	paymentID := uuid.NewV4().String()
+
+	for _, tc := range []struct {
+		description   string
+		body          string
+		requestID     string
+		fn            func(gateway *mocks.MockGateway)
+		expStatusCode int
+		exp           transporthttp.ProblemResponse
+	}{
+		{
+			description:   "should return every field failing validation",
+			body:          `{"payment_id":"abc"}`,
+			requestID:     "req_123",
+			expStatusCode: http.StatusUnprocessableEntity,
+			exp: transporthttp.ProblemResponse{
+				Type:      transporthttp.ProblemTypePrefix + "validation_failed",
+				Title:     "Unprocessable Entity",
+				Status:    http.StatusUnprocessableEntity,
+				Code:      "validation_failed",
+				Message:   "invalid payment_id: must be a UUID; invalid amount: cannot be zero",
+				RequestID: "req_123",
+				Instance:  "/capture",
+				Errors: []transporthttp.FieldErrorResponse{
+					{Field: "payment_id", Message: "invalid payment_id: must be a UUID"},
+					{Field: "amount", Message: "invalid amount: cannot be zero"},
+				},
+			},
+		},
+		{
+			description: "should return the reason the payment was declined",
+			body:        `{"payment_id":"` + paymentID + `","amount":100}`,
+			requestID:   "req_456",
+			fn: func(gateway *mocks.MockGateway) {
+				gateway.EXPECT().Capture(gomock.Any(), paymentID, uint64(100), false).
+					Return(nil, domain.DeclinedError{Err: domain.ErrNotPermitted, Reason: "insufficient funds"})
+			},
+			expStatusCode: http.StatusForbidden,
+			exp: transporthttp.ProblemResponse{
+				Type:          transporthttp.ProblemTypePrefix + "payment_declined",
+				Title:         "Forbidden",
+				Status:        http.StatusForbidden,
+				Code:          "payment_declined",
+				Message:       "capture not allowed: payment declined",
+				DeclineReason: "insufficient funds",
+				RequestID:     "req_456",
+				Instance:      "/capture",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			gateway := mocks.NewMockGateway(gomock.NewController(t))
+			if tc.fn != nil {
+				tc.fn(gateway)
+			}
+			h, err := transporthttp.NewHandler(gateway)
+			require.NoError(t, err)
+
+			request := withAPIKey(httptest.NewRequest(http.MethodPost, "/capture", bytes.NewBufferString(tc.body)))
+			request.Header.Set(transporthttp.RequestIDHeader, tc.requestID)
+			recorder := httptest.NewRecorder()
+			merchantRoutes(t, h).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			assert.Equal(t, transporthttp.ProblemContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, tc.requestID, recorder.Header().Get(transporthttp.RequestIDHeader))
+			var problem transporthttp.ProblemResponse
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&problem))
+			assert.Equal(t, tc.exp, problem)
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+	var requestID string
+	handler := transporthttp.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = transporthttp.RequestIDFromContext(r.Context())
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/payments", nil))
+	require.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, recorder.Header().Get(transporthttp.RequestIDHeader))
+}
@@ -0,0 +1,163 @@
+package transporthttp
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/validation"
+)
+
+func (r CreateAuthorizationRequest) Validate() error {
+	fields := []validation.FieldRules{
+		validation.Field("amount", validation.Check(r.Amount != nil, "cannot be missing")),
+		validation.Field("amount.minor_units", validation.NotZero(r.Amount.GetMinorUnits())).When(r.Amount != nil),
+		validation.Field("amount.currency", validation.Currency(r.Amount.GetCurrency())).When(r.Amount != nil),
+		validation.Field("settlement_currency", validation.Currency(r.SettlementCurrency)).When(r.SettlementCurrency != ""),
+	}
+	return validation.Validate(append(fields, validation.Card("payment_method.card", r.Card, time.Now())...)...)
+}
+
+func (r CreateCaptureRequest) Validate() error {
+	return validation.Validate(
+		paymentIDField(r.PaymentID),
+		validation.Field("amount", validation.NotZero(r.Amount)),
+	)
+}
+
+func (r CreateIncrementRequest) Validate() error {
+	return validation.Validate(
+		paymentIDField(r.PaymentID),
+		validation.Field("amount", validation.NotZero(r.Amount)),
+	)
+}
+
+// Validate allows a zero amount, reversing all that is left uncaptured.
+func (r CreateReverseRequest) Validate() error {
+	return validation.Validate(paymentIDField(r.PaymentID))
+}
+
+func (r CreateRefundRequest) Validate() error {
+	return validation.Validate(
+		paymentIDField(r.PaymentID),
+		validation.Field("amount", validation.NotZero(r.Amount)),
+	)
+}
+
+func (r CreateVoidRequest) Validate() error {
+	return validation.Validate(paymentIDField(r.PaymentID))
+}
+
+func (r CreateMerchantRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	return validation.Validate(validation.Field("name",
+		validation.Check(name != "", "cannot be empty"),
+		validation.Check(len(name) <= MerchantNameMaxLen, "cannot exceed %d characters", MerchantNameMaxLen),
+	))
+}
+
+func (r AcceptedCardBrandsRequest) Validate() error {
+	fields := make([]validation.FieldRules, 0, len(r.AcceptedCardBrands))
+	for i, brand := range r.AcceptedCardBrands {
+		fields = append(fields, validation.Field(fmt.Sprintf("accepted_card_brands[%d]", i),
+			validation.Check(domain.CardBrand(brand).Known(), "unknown card brand %q", brand)))
+	}
+	return validation.Validate(fields...)
+}
+
+func (r CreateWebhookEndpointRequest) Validate() error {
+	u, err := url.Parse(r.URL)
+	return validation.Validate(validation.Field("url",
+		validation.Check(r.URL != "", "cannot be empty"),
+		validation.Check(len(r.URL) <= WebhookURLMaxLen, "cannot exceed %d characters", WebhookURLMaxLen),
+		validation.Check(err == nil && u.Scheme == "https" && u.Host != "", "must be an absolute https url"),
+		validation.Check(err != nil || u.Host == "" || domain.PublicWebhookHost(u.Hostname()),
+			"cannot be a private, loopback, link-local or metadata host"),
+	))
+}
+
+func (r ListPaymentsRequest) Validate() error {
+	statuses := make([]validation.Rule, 0, len(r.Statuses))
+	for _, status := range r.Statuses {
+		statuses = append(statuses, validation.Check(paymentStatus(status).ToProto() != paymentsV1.PaymentStatus_PAYMENT_STATUS_UNSPECIFIED,
+			"unknown status %s", status))
+	}
+	currencies := make([]validation.Rule, 0, len(r.Currencies))
+	for _, currency := range r.Currencies {
+		currencies = append(currencies, validation.Currency(currency))
+	}
+	minAmount, minAmountErr := parseOptionalUint(r.MinAmount)
+	maxAmount, maxAmountErr := parseOptionalUint(r.MaxAmount)
+	_, createdAfterErr := parseOptionalTime(r.CreatedAfter)
+	_, createdBeforeErr := parseOptionalTime(r.CreatedBefore)
+	limit, limitErr := parseOptionalUint(r.Limit)
+	_, cursorErr := domain.DecodePaymentCursor(r.Cursor)
+
+	return validation.Validate(
+		validation.Field("status", statuses...),
+		validation.Field("currency", currencies...),
+		validation.Field("min_amount",
+			validation.Check(minAmountErr == nil, "must be a positive integer"),
+			validation.Check(maxAmountErr != nil || maxAmount == 0 || minAmount <= maxAmount, "cannot exceed max_amount"),
+		),
+		validation.Field("max_amount", validation.Check(maxAmountErr == nil, "must be a positive integer")),
+		validation.Field("created_after", validation.Check(createdAfterErr == nil, "must be an RFC3339 timestamp")),
+		validation.Field("created_before", validation.Check(createdBeforeErr == nil, "must be an RFC3339 timestamp")),
+		validation.Field("card_last_four",
+			validation.Check(len(r.CardLastFour) == LastFourLen && domain.IsDigits(r.CardLastFour), "must be %d digits", LastFourLen),
+		).When(r.CardLastFour != ""),
+		validation.Field("limit",
+			validation.Check(limitErr == nil && limit != 0 && limit <= domain.MaxListPaymentsLimit,
+				"must be between 1 and %d", domain.MaxListPaymentsLimit),
+		).When(r.Limit != ""),
+		validation.Field("cursor", validation.Check(cursorErr == nil, "%v", cursorErr)).When(r.Cursor != ""),
+	)
+}
+
+// Filters returns the filters the request lists payments by, the request must already be valid.
+func (r ListPaymentsRequest) Filters() domain.ListPaymentFilters {
+	filters := domain.ListPaymentFilters{
+		Currencies:   r.Currencies,
+		CardLastFour: r.CardLastFour,
+	}
+	for _, status := range r.Statuses {
+		filters.Statuses = append(filters.Statuses, paymentStatus(status))
+	}
+	filters.MinAmount, _ = parseOptionalUint(r.MinAmount)
+	filters.MaxAmount, _ = parseOptionalUint(r.MaxAmount)
+	filters.CreatedAfter, _ = parseOptionalTime(r.CreatedAfter)
+	filters.CreatedBefore, _ = parseOptionalTime(r.CreatedBefore)
+	filters.Limit, _ = parseOptionalUint(r.Limit)
+	if r.Cursor != "" {
+		filters.Cursor, _ = domain.DecodePaymentCursor(r.Cursor)
+	}
+	return filters
+}
+
+func paymentStatus(status string) domain.PaymentStatus {
+	return domain.PaymentStatus(strings.ToUpper(status))
+}
+
+// parseOptionalUint parses the value returning zero should it be empty.
+func parseOptionalUint(v string) (uint64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+// parseOptionalTime parses the RFC3339 timestamp returning the zero time should it be empty.
+func parseOptionalTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func paymentIDField(paymentID string) validation.FieldRules {
+	return validation.Field("payment_id", validation.Check(paymentID != "", "cannot be empty"), validation.UUID(paymentID))
+}
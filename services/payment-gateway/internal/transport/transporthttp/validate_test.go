@@ -0,0 +1,95 @@
+package transporthttp_test
+
+import (
+	"testing"
+	"time"
+
+	amountV1 "github.com/jacktantram/payments-api/build/go/shared/amount/v1"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAuthorizationRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should accept a valid request", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, transporthttp.CreateAuthorizationRequest{
+			Amount: &amountV1.Money{MinorUnits: 100, Currency: "GBP"},
+			Card: &paymentsV1.PaymentMethodCard{
+				CardNumber: "378282246310005",
+				Cvv:        "1234",
+				Expiry:     &paymentsV1.PaymentMethodCard_ExpiryDate{Month: 12, Year: uint32(time.Now().Year() + 1)},
+			},
+		}.Validate())
+	})
+
+	t.Run("should return every field failing validation", func(t *testing.T) {
+		t.Parallel()
+		err := transporthttp.CreateAuthorizationRequest{
+			Amount:             &amountV1.Money{Currency: "GB"},
+			SettlementCurrency: "EUR",
+			Card: &paymentsV1.PaymentMethodCard{
+				CardNumber: "378282246310005",
+				Cvv:        "123",
+				Expiry:     &paymentsV1.PaymentMethodCard_ExpiryDate{Month: 0, Year: uint32(time.Now().Year() + 1)},
+			},
+		}.Validate()
+		assert.Equal(t, domain.ValidationErrors{
+			{Field: "amount.minor_units", Message: "invalid amount.minor_units: cannot be zero"},
+			{Field: "amount.currency", Message: "invalid amount.currency: must be an ISO 4217 currency code"},
+			{Field: "payment_method.card.cvv", Message: "invalid payment_method.card.cvv: length not equal to 4"},
+			{Field: "payment_method.card.expiry.month", Message: "invalid payment_method.card.expiry.month: must be between 1 and 12"},
+		}, err)
+	})
+}
+
+func TestCreateCaptureRequest_Validate(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, transporthttp.CreateCaptureRequest{PaymentID: "a6921fc3-a7e3-4661-909b-b3c6c77837ce", Amount: 1}.Validate())
+	assert.Equal(t, domain.ValidationErrors{
+		{Field: "payment_id", Message: "invalid payment_id: must be a UUID"},
+		{Field: "amount", Message: "invalid amount: cannot be zero"},
+	}, transporthttp.CreateCaptureRequest{PaymentID: "abc"}.Validate())
+}
+
+func TestListPaymentsRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should accept a valid request", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, transporthttp.ListPaymentsRequest{
+			Statuses:     []string{"authorized"},
+			Currencies:   []string{"GBP"},
+			MinAmount:    "100",
+			MaxAmount:    "2000",
+			CreatedAfter: "2021-11-01T00:00:00Z",
+			CardLastFour: "0119",
+			Limit:        "10",
+		}.Validate())
+	})
+
+	t.Run("should return every field failing validation", func(t *testing.T) {
+		t.Parallel()
+		err := transporthttp.ListPaymentsRequest{
+			Statuses:      []string{"authorized", "unknown"},
+			Currencies:    []string{"XYZ"},
+			MinAmount:     "100",
+			MaxAmount:     "10",
+			CreatedBefore: "yesterday",
+			CardLastFour:  "12a4",
+			Limit:         "0",
+		}.Validate()
+		assert.Equal(t, domain.ValidationErrors{
+			{Field: "status", Message: "invalid status: unknown status unknown"},
+			{Field: "currency", Message: "invalid currency: must be an ISO 4217 currency code"},
+			{Field: "min_amount", Message: "invalid min_amount: cannot exceed max_amount"},
+			{Field: "created_before", Message: "invalid created_before: must be an RFC3339 timestamp"},
+			{Field: "card_last_four", Message: "invalid card_last_four: must be 4 digits"},
+			{Field: "limit", Message: "invalid limit: must be between 1 and 100"},
+		}, err)
+	})
+}
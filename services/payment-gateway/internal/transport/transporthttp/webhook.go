@@ -0,0 +1,180 @@
+//go:generate mockgen -source=webhook.go -destination=mocks/mock_webhook.go -package=mocks
+package transporthttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	WebhookURLMaxLen            = 2048
+	MaxListWebhookDeliveryLimit = 100
+	DefaultWebhookDeliveryLimit = 20
+)
+
+type Webhooks interface {
+	RegisterEndpoint(ctx context.Context, url string) (*domain.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error)
+	DisableEndpoint(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, filters domain.ListWebhookDeliveryFilters) ([]*domain.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+}
+
+// WebhookHandler serves the endpoints merchants manage their webhooks with.
+type WebhookHandler struct {
+	webhooks Webhooks
+}
+
+func NewWebhookHandler(webhooks Webhooks) (WebhookHandler, error) {
+	if webhooks == nil {
+		return WebhookHandler{}, errors.New("webhooks is nil")
+	}
+	return WebhookHandler{webhooks: webhooks}, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("failed to write response")
+	}
+}
+
+func webhookEndpointResponse(endpoint *domain.WebhookEndpoint) WebhookEndpointResponse {
+	return WebhookEndpointResponse{ID: endpoint.ID.String(), URL: endpoint.URL, CreatedAt: endpoint.CreatedAt}
+}
+
+func webhookDeliveryResponse(delivery *domain.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:            delivery.ID.String(),
+		EndpointID:    delivery.EndpointID.String(),
+		EventID:       delivery.EventID.String(),
+		PaymentID:     delivery.PaymentID.String(),
+		EventType:     delivery.EventType,
+		Status:        string(delivery.Status),
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastError:     delivery.LastError.String,
+		CreatedAt:     delivery.CreatedAt,
+	}
+	if delivery.LastResponseStatus.Valid {
+		response.LastResponseStatus = &delivery.LastResponseStatus.Int32
+	}
+	if delivery.DeliveredAt.Valid {
+		response.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	return response
+}
+
+func (h WebhookHandler) CreateEndpointHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Body == http.NoBody {
+		invalidRequest(w, r, "no body supplied")
+		return
+	}
+	defer r.Body.Close()
+
+	var endpointRequest CreateWebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&endpointRequest); err != nil {
+		invalidRequest(w, r, "invalid payload")
+		return
+	}
+	if err := endpointRequest.Validate(); err != nil {
+		writeProblem(w, r, domain.ProblemOf(err, ""))
+		return
+	}
+
+	endpoint, err := h.webhooks.RegisterEndpoint(r.Context(), endpointRequest.URL)
+	if err != nil {
+		log.WithError(err).WithField("url", "/webhooks/endpoints").Error("failed to register webhook endpoint")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	response := webhookEndpointResponse(endpoint)
+	response.Secret = endpoint.Secret
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (h WebhookHandler) ListEndpointsHandler(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := h.webhooks.ListEndpoints(r.Context())
+	if err != nil {
+		log.WithError(err).WithField("url", "/webhooks/endpoints").Error("failed to list webhook endpoints")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	response := make([]WebhookEndpointResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		response = append(response, webhookEndpointResponse(endpoint))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h WebhookHandler) DeleteEndpointHandler(w http.ResponseWriter, r *http.Request) {
+	endpointID := mux.Vars(r)["id"]
+	if err := h.webhooks.DisableEndpoint(r.Context(), endpointID); err != nil {
+		if errors.Is(err, domain.ErrNoWebhookEndpoint) {
+			writeProblem(w, r, domain.ProblemOf(err, ""))
+			return
+		}
+		log.WithError(err).WithFields(log.Fields{
+			"webhook_endpoint.id": endpointID,
+			"url":                 "/webhooks/endpoints/{id}",
+		}).Error("failed to disable webhook endpoint")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h WebhookHandler) ListDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := domain.ListWebhookDeliveryFilters{
+		EndpointID: query.Get("endpoint_id"),
+		PaymentID:  query.Get("payment_id"),
+		Limit:      DefaultWebhookDeliveryLimit,
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || limit == 0 || limit > MaxListWebhookDeliveryLimit {
+			writeProblem(w, r, domain.ProblemOf(domain.InvalidField("limit", "must be between 1 and %d", MaxListWebhookDeliveryLimit), ""))
+			return
+		}
+		filters.Limit = limit
+	}
+
+	deliveries, err := h.webhooks.ListDeliveries(r.Context(), filters)
+	if err != nil {
+		log.WithError(err).WithField("url", "/webhooks/deliveries").Error("failed to list webhook deliveries")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	response := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, webhookDeliveryResponse(delivery))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h WebhookHandler) ReplayDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	deliveryID := mux.Vars(r)["id"]
+	delivery, err := h.webhooks.ReplayDelivery(r.Context(), deliveryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoWebhookDelivery) {
+			writeProblem(w, r, domain.ProblemOf(err, ""))
+			return
+		}
+		log.WithError(err).WithFields(log.Fields{
+			"webhook_delivery.id": deliveryID,
+			"url":                 "/webhooks/deliveries/{id}/replay",
+		}).Error("failed to replay webhook delivery")
+		writeProblem(w, r, domain.ProblemInternal)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, webhookDeliveryResponse(delivery))
+}
@@ -0,0 +1,203 @@
+package transporthttp_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/transport/transporthttp/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookHandler(t *testing.T) {
+	t.Parallel()
+	_, err := transporthttp.NewWebhookHandler(nil)
+	require.Error(t, err)
+}
+
+func TestWebhookHandler(t *testing.T) {
+	t.Parallel()
+	var (
+		endpoint = &domain.WebhookEndpoint{
+			ID:        uuid.NewV4(),
+			URL:       "https://merchant.example/webhooks",
+			Secret:    "whsec_secret",
+			CreatedAt: time.Now().UTC(),
+		}
+		delivery = &domain.WebhookDelivery{
+			ID:                 uuid.NewV4(),
+			EndpointID:         endpoint.ID,
+			Status:             domain.WebhookDeliveryStatusFailed,
+			Attempts:           8,
+			LastResponseStatus: sql.NullInt32{Int32: http.StatusInternalServerError, Valid: true},
+		}
+	)
+
+	for _, tc := range []struct {
+		description     string
+		method          string
+		path            string
+		body            io.Reader
+		fn              func(m *mocks.MockWebhooks)
+		expStatusCode   int
+		responseMessage string
+	}{
+		{
+			description: "should register the endpoint returning its secret",
+			method:      http.MethodPost,
+			path:        "/webhooks/endpoints",
+			body:        strings.NewReader(`{"url":"https://merchant.example/webhooks"}`),
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().RegisterEndpoint(gomock.Any(), "https://merchant.example/webhooks").Return(endpoint, nil)
+			},
+			expStatusCode:   http.StatusCreated,
+			responseMessage: `"secret":"whsec_secret"`,
+		},
+		{
+			description:     "should return error given a relative url",
+			method:          http.MethodPost,
+			path:            "/webhooks/endpoints",
+			body:            strings.NewReader(`{"url":"/webhooks"}`),
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: "invalid url: must be an absolute https url",
+		},
+		{
+			description:     "should return error given a url that is not https",
+			method:          http.MethodPost,
+			path:            "/webhooks/endpoints",
+			body:            strings.NewReader(`{"url":"http://merchant.example"}`),
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: "invalid url: must be an absolute https url",
+		},
+		{
+			description:     "should return error given a url on a private network",
+			method:          http.MethodPost,
+			path:            "/webhooks/endpoints",
+			body:            strings.NewReader(`{"url":"https://10.0.0.1/webhooks"}`),
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: "invalid url: cannot be a private, loopback, link-local or metadata host",
+		},
+		{
+			description:     "should return error given a cloud metadata url",
+			method:          http.MethodPost,
+			path:            "/webhooks/endpoints",
+			body:            strings.NewReader(`{"url":"https://169.254.169.254/latest/meta-data"}`),
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: "invalid url: cannot be a private, loopback, link-local or metadata host",
+		},
+		{
+			description: "should list the endpoints without their secrets",
+			method:      http.MethodGet,
+			path:        "/webhooks/endpoints",
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().ListEndpoints(gomock.Any()).Return([]*domain.WebhookEndpoint{endpoint}, nil)
+			},
+			expStatusCode:   http.StatusOK,
+			responseMessage: `"url":"https://merchant.example/webhooks"`,
+		},
+		{
+			description: "should disable the endpoint",
+			method:      http.MethodDelete,
+			path:        "/webhooks/endpoints/" + endpoint.ID.String(),
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().DisableEndpoint(gomock.Any(), endpoint.ID.String()).Return(nil)
+			},
+			expStatusCode: http.StatusNoContent,
+		},
+		{
+			description: "should return not found given an unknown endpoint",
+			method:      http.MethodDelete,
+			path:        "/webhooks/endpoints/" + endpoint.ID.String(),
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().DisableEndpoint(gomock.Any(), endpoint.ID.String()).Return(domain.ErrNoWebhookEndpoint)
+			},
+			expStatusCode:   http.StatusNotFound,
+			responseMessage: "webhook endpoint not found",
+		},
+		{
+			description: "should list the deliveries by filters",
+			method:      http.MethodGet,
+			path:        "/webhooks/deliveries?payment_id=abc&limit=5",
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().ListDeliveries(gomock.Any(), domain.ListWebhookDeliveryFilters{PaymentID: "abc", Limit: 5}).
+					Return([]*domain.WebhookDelivery{delivery}, nil)
+			},
+			expStatusCode:   http.StatusOK,
+			responseMessage: `"last_response_status":500`,
+		},
+		{
+			description:     "should return error given an invalid limit",
+			method:          http.MethodGet,
+			path:            "/webhooks/deliveries?limit=101",
+			expStatusCode:   http.StatusUnprocessableEntity,
+			responseMessage: "invalid limit: must be between 1 and 100",
+		},
+		{
+			description: "should replay the delivery",
+			method:      http.MethodPost,
+			path:        "/webhooks/deliveries/" + delivery.ID.String() + "/replay",
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().ReplayDelivery(gomock.Any(), delivery.ID.String()).
+					Return(&domain.WebhookDelivery{ID: delivery.ID, Status: domain.WebhookDeliveryStatusPending}, nil)
+			},
+			expStatusCode:   http.StatusAccepted,
+			responseMessage: `"status":"PENDING"`,
+		},
+		{
+			description: "should return not found given an unknown delivery",
+			method:      http.MethodPost,
+			path:        "/webhooks/deliveries/" + delivery.ID.String() + "/replay",
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().ReplayDelivery(gomock.Any(), delivery.ID.String()).Return(nil, domain.ErrNoWebhookDelivery)
+			},
+			expStatusCode:   http.StatusNotFound,
+			responseMessage: "webhook delivery not found",
+		},
+		{
+			description: "should return error given the deliveries cannot be listed",
+			method:      http.MethodGet,
+			path:        "/webhooks/deliveries",
+			fn: func(m *mocks.MockWebhooks) {
+				m.EXPECT().ListDeliveries(gomock.Any(), domain.ListWebhookDeliveryFilters{Limit: transporthttp.DefaultWebhookDeliveryLimit}).
+					Return(nil, errors.New("boom"))
+			},
+			expStatusCode: http.StatusInternalServerError,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			webhooks := mocks.NewMockWebhooks(gomock.NewController(t))
+			if tc.fn != nil {
+				tc.fn(webhooks)
+			}
+			wh, err := transporthttp.NewWebhookHandler(webhooks)
+			require.NoError(t, err)
+			h, err := transporthttp.NewHandler(mocks.NewMockGateway(gomock.NewController(t)))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			merchantWebhookRoutes(t, h, wh).ServeHTTP(recorder, withAPIKey(httptest.NewRequest(tc.method, tc.path, tc.body)))
+			assert.Equal(t, tc.expStatusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.responseMessage)
+
+			if tc.path == "/webhooks/endpoints" && tc.method == http.MethodGet {
+				var endpoints []transporthttp.WebhookEndpointResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&endpoints))
+				require.Len(t, endpoints, 1)
+				assert.Empty(t, endpoints[0].Secret)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Package validation declares the rules the fields of a request must satisfy, reporting every field failing them at
+// once rather than stopping at the first.
+package validation
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	currencyV1 "github.com/jacktantram/payments-api/pkg/currency/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	uuid "github.com/kevinburke/go.uuid"
+)
+
+const ExpiryMonthMax = 12
+
+// Rule checks the value of the named field, returning the error it fails with or nil.
+type Rule func(field string) error
+
+// FieldRules are the rules a field must satisfy, checked in order until one fails.
+type FieldRules struct {
+	name  string
+	rules []Rule
+	skip  bool
+}
+
+// Field declares the rules of the named field.
+func Field(name string, rules ...Rule) FieldRules {
+	return FieldRules{name: name, rules: rules}
+}
+
+// When only checks the field's rules should the condition hold, such as when the field is only present in some
+// requests.
+func (f FieldRules) When(condition bool) FieldRules {
+	f.skip = f.skip || !condition
+	return f
+}
+
+// Validate checks every field, returning domain.ValidationErrors holding the first error of each field failing its
+// rules or nil should they all pass.
+func Validate(fields ...FieldRules) error {
+	var errs domain.ValidationErrors
+	for _, field := range fields {
+		if field.skip {
+			continue
+		}
+		for _, rule := range field.rules {
+			err := rule(field.name)
+			if err == nil {
+				continue
+			}
+			var validationErr domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				validationErr = domain.InvalidField(field.name, "%v", err)
+			}
+			errs = append(errs, validationErr)
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Check fails with the reason unless ok holds.
+func Check(ok bool, reason string, args ...interface{}) Rule {
+	return func(field string) error {
+		if ok {
+			return nil
+		}
+		return domain.InvalidField(field, reason, args...)
+	}
+}
+
+// Required fails unless the field is present.
+func Required(present bool) Rule {
+	return func(field string) error {
+		if present {
+			return nil
+		}
+		return domain.MissingField(field, "cannot be empty")
+	}
+}
+
+// NotZero fails given a zero amount.
+func NotZero(amount uint64) Rule {
+	return Check(amount != 0, "cannot be zero")
+}
+
+// UUID fails unless the value is a UUID.
+func UUID(value string) Rule {
+	_, err := uuid.FromString(value)
+	return Check(err == nil, "must be a UUID")
+}
+
+// Currency fails unless the value is an ISO 4217 currency code.
+func Currency(code string) Rule {
+	return Check(currencyV1.Valid(code), "must be an ISO 4217 currency code")
+}
+
+// CardNumber fails unless the card number passes the luhn check and is of a length its brand issues.
+func CardNumber(number string) Rule {
+	return func(field string) error {
+		if !domain.ValidCardNumber(number) {
+			return domain.InvalidField(field, "invalid card number")
+		}
+		if brand := domain.CardBrandFromBIN(number); !brand.ValidLen(len(strings.ReplaceAll(number, " ", ""))) {
+			return domain.InvalidField(field, "invalid length for %s cards", brand)
+		}
+		return nil
+	}
+}
+
+// CVV fails unless the security code is as long as those of the card's brand.
+func CVV(cardNumber, cvv string) Rule {
+	length := domain.CardBrandFromBIN(cardNumber).CVVLen()
+	return func(field string) error {
+		if len(cvv) != length {
+			return domain.InvalidField(field, "length not equal to %d", length)
+		}
+		if !domain.IsDigits(cvv) {
+			return domain.InvalidField(field, "must only contain digits")
+		}
+		return nil
+	}
+}
+
+// ExpiryMonth fails unless the month is a month of the year.
+func ExpiryMonth(month uint32) Rule {
+	return Check(month >= 1 && month <= ExpiryMonthMax, "must be between 1 and %d", ExpiryMonthMax)
+}
+
+// ExpiryYear fails given a year before now.
+func ExpiryYear(year uint32, now time.Time) Rule {
+	return Check(int(year) >= now.Year(), "cannot be in the past")
+}
+
+// NotExpired fails given a card whose expiry month has passed, a card is valid until the end of its expiry month.
+func NotExpired(month, year uint32, now time.Time) Rule {
+	return Check(!expired(month, year, now), "card has expired")
+}
+
+func expired(month, year uint32, now time.Time) bool {
+	return int(year) < now.Year() || (int(year) == now.Year() && int(month) < int(now.Month()))
+}
+
+// Card returns the rules of the card, its fields named under the prefix. A tokenized card is already held in the
+// vault, so only its token is needed.
+func Card(prefix string, card *paymentsV1.PaymentMethodCard, now time.Time) []FieldRules {
+	var (
+		unsaved = card != nil && card.GetToken() == ""
+		expiry  = card.GetExpiry()
+		month   = expiry.GetMonth()
+		year    = expiry.GetYear()
+	)
+	return []FieldRules{
+		Field(prefix, Required(card != nil)),
+		Field(prefix+".card_number", Check(card.GetCardNumber() == "", "cannot be supplied with a token")).
+			When(card.GetToken() != ""),
+		Field(prefix+".card_number", Required(card.GetCardNumber() != ""), CardNumber(card.GetCardNumber())).When(unsaved),
+		Field(prefix+".cvv", CVV(card.GetCardNumber(), card.GetCvv())).When(unsaved),
+		Field(prefix+".expiry", Required(expiry != nil)).When(unsaved),
+		Field(prefix+".expiry.month", ExpiryMonth(month)).When(unsaved && expiry != nil),
+		Field(prefix+".expiry.year", ExpiryYear(year, now)).When(unsaved && expiry != nil),
+		Field(prefix+".expiry", NotExpired(month, year, now)).
+			When(unsaved && expiry != nil && month >= 1 && month <= ExpiryMonthMax && int(year) >= now.Year()),
+	}
+}
@@ -0,0 +1,222 @@
+package validation_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return nil given every field is valid", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, validation.Validate(
+			validation.Field("payment_id", validation.UUID("a6921fc3-a7e3-4661-909b-b3c6c77837ce")),
+			validation.Field("amount", validation.NotZero(10)),
+		))
+	})
+
+	t.Run("should return the first error of every field failing validation", func(t *testing.T) {
+		t.Parallel()
+		err := validation.Validate(
+			validation.Field("payment_id", validation.Check(false, "cannot be empty"), validation.UUID("")),
+			validation.Field("amount", validation.NotZero(0)),
+			validation.Field("currency", validation.Currency("GBP")),
+		)
+		var errs domain.ValidationErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, domain.ValidationErrors{
+			{Field: "payment_id", Message: "invalid payment_id: cannot be empty"},
+			{Field: "amount", Message: "invalid amount: cannot be zero"},
+		}, errs)
+	})
+
+	t.Run("should skip fields whose condition does not hold", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, validation.Validate(validation.Field("amount", validation.NotZero(0)).When(false)))
+	})
+
+	t.Run("should report errors that are not validation errors against the field", func(t *testing.T) {
+		t.Parallel()
+		err := validation.Validate(validation.Field("cursor", func(string) error { return errors.New("malformed") }))
+		assert.EqualError(t, err, "invalid cursor: malformed")
+	})
+}
+
+func TestUUID(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, validation.UUID("a6921fc3-a7e3-4661-909b-b3c6c77837ce")("payment_id"))
+	assert.EqualError(t, validation.UUID("abc")("payment_id"), "invalid payment_id: must be a UUID")
+}
+
+func TestCardNumber(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		cardNumber  string
+		expErr      string
+	}{
+		{description: "should accept a 16 digit visa", cardNumber: "4000000000000119"},
+		{description: "should accept a 19 digit visa", cardNumber: "4000000000000000006"},
+		{description: "should accept a 15 digit amex", cardNumber: "378282246310005"},
+		{description: "should accept a 14 digit diners", cardNumber: "30569309025904"},
+		{description: "should accept spaces between digits", cardNumber: "4000 0000 0000 0119"},
+		{
+			description: "should reject a number failing the luhn check",
+			cardNumber:  "4000000000000118",
+			expErr:      "invalid card_number: invalid card number",
+		},
+		{
+			description: "should reject a 14 digit amex",
+			cardNumber:  "37828224631003",
+			expErr:      "invalid card_number: invalid length for amex cards",
+		},
+		{
+			description: "should reject a 17 digit mastercard",
+			cardNumber:  "55555555555544440",
+			expErr:      "invalid card_number: invalid length for mastercard cards",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			err := validation.CardNumber(tc.cardNumber)("card_number")
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestCVV(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		description string
+		cardNumber  string
+		cvv         string
+		expErr      string
+	}{
+		{description: "should accept 3 digits for visa", cardNumber: "4000000000000119", cvv: "123"},
+		{description: "should accept 4 digits for amex", cardNumber: "378282246310005", cvv: "1234"},
+		{
+			description: "should reject 3 digits for amex",
+			cardNumber:  "378282246310005",
+			cvv:         "123",
+			expErr:      "invalid cvv: length not equal to 4",
+		},
+		{
+			description: "should reject 4 digits for visa",
+			cardNumber:  "4000000000000119",
+			cvv:         "1234",
+			expErr:      "invalid cvv: length not equal to 3",
+		},
+		{
+			description: "should reject non digits",
+			cardNumber:  "4000000000000119",
+			cvv:         "12a",
+			expErr:      "invalid cvv: must only contain digits",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			err := validation.CVV(tc.cardNumber, tc.cvv)("cvv")
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestCard(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, time.June, 15, 0, 0, 0, 0, time.UTC)
+	newCard := func(month, year uint32) *paymentsV1.PaymentMethodCard {
+		return &paymentsV1.PaymentMethodCard{
+			CardNumber: "4000000000000119",
+			Cvv:        "123",
+			Expiry:     &paymentsV1.PaymentMethodCard_ExpiryDate{Month: month, Year: year},
+		}
+	}
+
+	for _, tc := range []struct {
+		description string
+		card        *paymentsV1.PaymentMethodCard
+		exp         domain.ValidationErrors
+	}{
+		{
+			description: "should accept a card expiring this month",
+			card:        newCard(6, 2026),
+		},
+		{
+			description: "should accept a tokenized card",
+			card:        &paymentsV1.PaymentMethodCard{Token: "tok_123"},
+		},
+		{
+			description: "should reject a missing card",
+			exp:         domain.ValidationErrors{{Field: "card", Message: "missing card: cannot be empty"}},
+		},
+		{
+			description: "should reject a card number supplied with a token",
+			card:        &paymentsV1.PaymentMethodCard{Token: "tok_123", CardNumber: "4000000000000119"},
+			exp: domain.ValidationErrors{
+				{Field: "card.card_number", Message: "invalid card.card_number: cannot be supplied with a token"},
+			},
+		},
+		{
+			description: "should reject a card that expired earlier this year",
+			card:        newCard(5, 2026),
+			exp:         domain.ValidationErrors{{Field: "card.expiry", Message: "invalid card.expiry: card has expired"}},
+		},
+		{
+			description: "should reject month zero",
+			card:        newCard(0, 2027),
+			exp: domain.ValidationErrors{
+				{Field: "card.expiry.month", Message: "invalid card.expiry.month: must be between 1 and 12"},
+			},
+		},
+		{
+			description: "should reject a year in the past",
+			card:        newCard(12, 2025),
+			exp: domain.ValidationErrors{
+				{Field: "card.expiry.year", Message: "invalid card.expiry.year: cannot be in the past"},
+			},
+		},
+		{
+			description: "should report every invalid field of the card",
+			card: &paymentsV1.PaymentMethodCard{
+				CardNumber: "4000000000000118",
+				Cvv:        "12",
+			},
+			exp: domain.ValidationErrors{
+				{Field: "card.card_number", Message: "invalid card.card_number: invalid card number"},
+				{Field: "card.cvv", Message: "invalid card.cvv: length not equal to 3"},
+				{Field: "card.expiry", Message: "missing card.expiry: cannot be empty"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			err := validation.Validate(validation.Card("card", tc.card, now)...)
+			if tc.exp == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tc.exp, err)
+		})
+	}
+}
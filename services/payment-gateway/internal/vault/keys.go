@@ -0,0 +1,73 @@
+package vault
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const keyLen = 32
+
+var ErrUnknownKey = errors.New("unknown master key")
+
+// KeyManager encrypts and decrypts data keys with a master key that never leaves it, standing in for a KMS.
+type KeyManager interface {
+	// KeyID identifies the master key data keys are currently encrypted with.
+	KeyID() string
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// FileKeyManager holds a single master key read from a local file.
+type FileKeyManager struct {
+	key   []byte
+	keyID string
+}
+
+// NewFileKeyManager reads the hex encoded master key from the file at path, generating the file if it does not exist.
+func NewFileKeyManager(path string) (*FileKeyManager, error) {
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		key := make([]byte, keyLen)
+		if _, err = rand.Read(key); err != nil {
+			return nil, err
+		}
+		b = []byte(hex.EncodeToString(key))
+		if err = ioutil.WriteFile(path, b, 0600); err != nil {
+			return nil, errors.Wrap(err, "unable to write master key")
+		}
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to read master key")
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, errors.Wrap(err, "master key is not hex encoded")
+	}
+	if len(key) != keyLen {
+		return nil, errors.Errorf("master key must be %d bytes", keyLen)
+	}
+	sum := sha256.Sum256(key)
+	return &FileKeyManager{key: key, keyID: hex.EncodeToString(sum[:8])}, nil
+}
+
+func (m *FileKeyManager) KeyID() string {
+	return m.keyID
+}
+
+func (m *FileKeyManager) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return seal(m.key, plaintext, []byte(m.keyID))
+}
+
+func (m *FileKeyManager) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	if keyID != m.keyID {
+		return nil, ErrUnknownKey
+	}
+	return open(m.key, ciphertext, []byte(m.keyID))
+}
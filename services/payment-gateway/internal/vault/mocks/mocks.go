@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: vault.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateCardToken mocks base method.
+func (m *MockStore) CreateCardToken(ctx context.Context, cardToken *domain.CardToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCardToken", ctx, cardToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCardToken indicates an expected call of CreateCardToken.
+func (mr *MockStoreMockRecorder) CreateCardToken(ctx, cardToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCardToken", reflect.TypeOf((*MockStore)(nil).CreateCardToken), ctx, cardToken)
+}
+
+// GetCardToken mocks base method.
+func (m *MockStore) GetCardToken(ctx context.Context, token string) (*domain.CardToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardToken", ctx, token)
+	ret0, _ := ret[0].(*domain.CardToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardToken indicates an expected call of GetCardToken.
+func (mr *MockStoreMockRecorder) GetCardToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardToken", reflect.TypeOf((*MockStore)(nil).GetCardToken), ctx, token)
+}
@@ -0,0 +1,150 @@
+//go:generate mockgen -source=vault.go -destination=mocks/mocks.go -package=mocks
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+const tokenPrefix = "tok_"
+
+var ErrCorruptCard = errors.New("unable to decrypt card")
+
+type Store interface {
+	CreateCardToken(ctx context.Context, cardToken *domain.CardToken) error
+	GetCardToken(ctx context.Context, token string) (*domain.CardToken, error)
+}
+
+// Vault tokenizes cards so that card numbers are only ever stored encrypted. Each card number is encrypted with its
+// own data key, the data key is encrypted by the KeyManager and stored alongside it (envelope encryption).
+type Vault struct {
+	store Store
+	keys  KeyManager
+}
+
+func NewVault(store Store, keys KeyManager) Vault {
+	return Vault{store: store, keys: keys}
+}
+
+// Tokenize stores the card returning it with its token, BIN and last four in place of the card number and CVV.
+func (v Vault) Tokenize(ctx context.Context, card *paymentsV1.PaymentMethodCard) (*paymentsV1.PaymentMethodCard, error) {
+	pan := strings.ReplaceAll(card.GetCardNumber(), " ", "")
+	if len(pan) < 10 {
+		return nil, errors.New("card number too short to tokenize")
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	dataKey := make([]byte, keyLen)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	// the token is bound to the ciphertext so that encrypted card numbers cannot be swapped between tokens.
+	encryptedPAN, err := seal(dataKey, []byte(pan), []byte(token))
+	if err != nil {
+		return nil, err
+	}
+	encryptedDataKey, err := v.keys.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encrypt data key")
+	}
+
+	cardToken := &domain.CardToken{
+		Token:            token,
+		EncryptedPAN:     encryptedPAN,
+		EncryptedDataKey: encryptedDataKey,
+		KeyID:            v.keys.KeyID(),
+		Bin:              pan[:6],
+		LastFour:         pan[len(pan)-4:],
+		ExpiryMonth:      int(card.GetExpiry().GetMonth()),
+		ExpiryYear:       int(card.GetExpiry().GetYear()),
+	}
+	if err = v.store.CreateCardToken(ctx, cardToken); err != nil {
+		return nil, err
+	}
+	return maskedCard(cardToken), nil
+}
+
+// Detokenize returns the card with its card number. The CVV is never stored and so is never returned.
+// domain.ErrNoCardToken is returned for an unknown token.
+func (v Vault) Detokenize(ctx context.Context, token string) (*paymentsV1.PaymentMethodCard, error) {
+	cardToken, err := v.store.GetCardToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := v.keys.Decrypt(ctx, cardToken.KeyID, cardToken.EncryptedDataKey)
+	if err != nil {
+		return nil, errors.Wrap(ErrCorruptCard, err.Error())
+	}
+	pan, err := open(dataKey, cardToken.EncryptedPAN, []byte(cardToken.Token))
+	if err != nil {
+		return nil, errors.Wrap(ErrCorruptCard, err.Error())
+	}
+
+	card := maskedCard(cardToken)
+	card.CardNumber = string(pan)
+	return card, nil
+}
+
+func maskedCard(cardToken *domain.CardToken) *paymentsV1.PaymentMethodCard {
+	return &paymentsV1.PaymentMethodCard{
+		Token:    cardToken.Token,
+		Bin:      cardToken.Bin,
+		LastFour: cardToken.LastFour,
+		Brand:    string(domain.CardBrandFromBIN(cardToken.Bin)),
+		Expiry: &paymentsV1.PaymentMethodCard_ExpiryDate{
+			Month: uint32(cardToken.ExpiryMonth),
+			Year:  uint32(cardToken.ExpiryYear),
+		},
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tokenPrefix + hex.EncodeToString(b), nil
+}
+
+// seal encrypts the plaintext with AES-GCM prefixing the nonce to the ciphertext.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
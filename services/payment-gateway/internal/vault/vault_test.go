@@ -0,0 +1,153 @@
+package vault_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/vault"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/vault/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyManager(t *testing.T) *vault.FileKeyManager {
+	keys, err := vault.NewFileKeyManager(filepath.Join(t.TempDir(), "vault.key"))
+	require.NoError(t, err)
+	return keys
+}
+
+func TestVault_TokenizeDetokenize(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		store  = mocks.NewMockStore(ctrl)
+		stored *domain.CardToken
+		card   = &paymentsV1.PaymentMethodCard{
+			CardNumber: "4242 4242 4242 4242",
+			Cvv:        "123",
+			Expiry:     &paymentsV1.PaymentMethodCard_ExpiryDate{Month: 12, Year: 2030},
+		}
+	)
+	store.EXPECT().CreateCardToken(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, cardToken *domain.CardToken) error {
+		stored = cardToken
+		return nil
+	})
+	v := vault.NewVault(store, newKeyManager(t))
+
+	masked, err := v.Tokenize(context.Background(), card)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(masked.Token, "tok_"))
+	assert.Equal(t, "424242", masked.Bin)
+	assert.Equal(t, "4242", masked.LastFour)
+	assert.Empty(t, masked.CardNumber)
+	assert.Empty(t, masked.Cvv)
+
+	require.NotNil(t, stored)
+	assert.False(t, bytes.Contains(stored.EncryptedPAN, []byte("4242424242424242")))
+	assert.Equal(t, masked.Token, stored.Token)
+
+	store.EXPECT().GetCardToken(gomock.Any(), masked.Token).Return(stored, nil)
+	detokenized, err := v.Detokenize(context.Background(), masked.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "4242424242424242", detokenized.CardNumber)
+	assert.Empty(t, detokenized.Cvv)
+	assert.Equal(t, card.Expiry.Month, detokenized.Expiry.Month)
+	assert.Equal(t, card.Expiry.Year, detokenized.Expiry.Year)
+}
+
+func TestVault_Detokenize_Error(t *testing.T) {
+	t.Parallel()
+	keys := newKeyManager(t)
+
+	newStoredToken := func(t *testing.T) *domain.CardToken {
+		ctrl := gomock.NewController(t)
+		store := mocks.NewMockStore(ctrl)
+		var stored *domain.CardToken
+		store.EXPECT().CreateCardToken(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, cardToken *domain.CardToken) error {
+			stored = cardToken
+			return nil
+		})
+		_, err := vault.NewVault(store, keys).Tokenize(context.Background(), &paymentsV1.PaymentMethodCard{CardNumber: "4242424242424242"})
+		require.NoError(t, err)
+		return stored
+	}
+
+	for _, tc := range []struct {
+		description string
+		fn          func(t *testing.T, store *mocks.MockStore)
+		err         error
+	}{
+		{
+			description: "should return error given the token does not exist",
+			fn: func(t *testing.T, store *mocks.MockStore) {
+				store.EXPECT().GetCardToken(gomock.Any(), "tok_abc").Return(nil, domain.ErrNoCardToken)
+			},
+			err: domain.ErrNoCardToken,
+		},
+		{
+			description: "should return error given the encrypted card number has been swapped from another token",
+			fn: func(t *testing.T, store *mocks.MockStore) {
+				first, second := newStoredToken(t), newStoredToken(t)
+				first.EncryptedPAN, first.EncryptedDataKey = second.EncryptedPAN, second.EncryptedDataKey
+				store.EXPECT().GetCardToken(gomock.Any(), "tok_abc").Return(first, nil)
+			},
+			err: vault.ErrCorruptCard,
+		},
+		{
+			description: "should return error given the data key was encrypted with another master key",
+			fn: func(t *testing.T, store *mocks.MockStore) {
+				stored := newStoredToken(t)
+				stored.KeyID = "other"
+				store.EXPECT().GetCardToken(gomock.Any(), "tok_abc").Return(stored, nil)
+			},
+			err: vault.ErrCorruptCard,
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mocks.NewMockStore(ctrl)
+			tc.fn(t, store)
+
+			_, err := vault.NewVault(store, keys).Detokenize(context.Background(), "tok_abc")
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestNewFileKeyManager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reuse the key generated on first use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vault.key")
+		first, err := vault.NewFileKeyManager(path)
+		require.NoError(t, err)
+		second, err := vault.NewFileKeyManager(path)
+		require.NoError(t, err)
+		assert.Equal(t, first.KeyID(), second.KeyID())
+
+		ciphertext, err := first.Encrypt(context.Background(), []byte("data key"))
+		require.NoError(t, err)
+		plaintext, err := second.Decrypt(context.Background(), first.KeyID(), ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), plaintext)
+	})
+	t.Run("should return error given the key is not 32 bytes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vault.key")
+		require.NoError(t, ioutil.WriteFile(path, []byte("abcd"), 0600))
+		_, err := vault.NewFileKeyManager(path)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultBatchSize   = 100
+	DefaultInterval    = time.Second
+	DefaultTimeout     = 10 * time.Second
+	DefaultMaxAttempts = 8
+	// DefaultBackoff is the wait before the first retry, doubling after each attempt up to MaxBackoff.
+	DefaultBackoff = 30 * time.Second
+	MaxBackoff     = time.Hour
+
+	maxErrorLen = 1024
+	// claimMargin is how long a delivery stays claimed beyond the timeout of its request, so that a claim never lapses
+	// whilst the request is still in flight.
+	claimMargin = 30 * time.Second
+)
+
+var errEndpointDisabled = errors.New("endpoint disabled")
+
+// Dispatcher sends due deliveries to their endpoints, retrying failures with exponential backoff until they succeed
+// or run out of attempts.
+type Dispatcher struct {
+	store           Store
+	client          *http.Client
+	timeout         time.Duration
+	privateNetworks bool
+	batchSize       uint64
+	interval        time.Duration
+	maxAttempts     int
+	backoff         time.Duration
+}
+
+type Option func(d *Dispatcher)
+
+// WithRetries makes up to maxAttempts attempts at each delivery, waiting backoff before the first retry.
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = maxAttempts
+		d.backoff = backoff
+	}
+}
+
+// WithClient sends notifications with the client rather than one timing out after DefaultTimeout that only connects
+// to public addresses.
+func WithClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithPrivateNetworks lets the default client connect to private and loopback addresses, for receivers running
+// alongside the gateway in development.
+func WithPrivateNetworks() Option {
+	return func(d *Dispatcher) {
+		d.privateNetworks = true
+	}
+}
+
+func NewDispatcher(store Store, interval time.Duration, opts ...Option) Dispatcher {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	d := Dispatcher{
+		store:       store,
+		timeout:     DefaultTimeout,
+		batchSize:   DefaultBatchSize,
+		interval:    interval,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if d.client == nil {
+		d.client = newClient(d.privateNetworks)
+	}
+	if d.client.Timeout != 0 {
+		d.timeout = d.client.Timeout
+	}
+	return d
+}
+
+func newClient(privateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: DefaultTimeout}
+	if !privateNetworks {
+		// checked once the host has been resolved so that a name cannot be pointed at an internal address after the
+		// endpoint was registered
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !domain.PublicIP(ip) {
+				return errors.Wrap(domain.ErrNonPublicAddress, host)
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would connect on the dispatcher's behalf, bypassing the check of the address
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   DefaultTimeout,
+		Transport: transport,
+		// a redirect is treated as a failure rather than followed
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// Run dispatches due deliveries every interval until the context is cancelled.
+func (d Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := d.DispatchBatch(ctx)
+				if err != nil {
+					log.WithError(err).Error("failed to list due webhook deliveries")
+					break
+				}
+				if uint64(n) < d.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// DispatchBatch attempts the oldest batch of due deliveries returning how many were due. A failure to record a
+// delivery's attempt is logged with the delivery retried on the next batch.
+func (d Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ListDueWebhookDeliveries(ctx, d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, delivery := range deliveries {
+		if err = d.Dispatch(ctx, delivery.ID.String()); err != nil {
+			log.WithError(err).WithField("webhook_delivery.id", delivery.ID).Error("failed to dispatch webhook delivery")
+		}
+	}
+	return len(deliveries), nil
+}
+
+// Dispatch attempts the delivery if it is still due, recording the outcome. The delivery is claimed in a short
+// transaction, pushing back its next attempt until after the request will have timed out, so that it is only sent by
+// one dispatcher at a time without holding a transaction open whilst waiting on the endpoint. Should the outcome fail
+// to be recorded the delivery is sent again once the claim lapses.
+func (d Dispatcher) Dispatch(ctx context.Context, id string) error {
+	var (
+		delivery *domain.WebhookDelivery
+		endpoint *domain.WebhookEndpoint
+	)
+	if err := d.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		delivery, endpoint, err = d.claim(ctx, id)
+		return err
+	}); err != nil || delivery == nil {
+		return err
+	}
+
+	statusCode, err := d.send(ctx, endpoint, delivery)
+	d.recordAttempt(delivery, statusCode, err)
+	return d.store.UpdateWebhookDelivery(ctx, delivery)
+}
+
+// claim returns the delivery to be sent along with its endpoint, or a nil delivery if it is no longer due. A delivery
+// to a disabled endpoint is failed rather than claimed. It must be called within ExecInTransaction.
+func (d Dispatcher) claim(ctx context.Context, id string) (*domain.WebhookDelivery, *domain.WebhookEndpoint, error) {
+	delivery, err := d.store.GetWebhookDeliveryForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoWebhookDelivery) {
+			// being claimed by another dispatcher
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if delivery.Status != domain.WebhookDeliveryStatusPending || delivery.NextAttemptAt.After(time.Now()) {
+		return nil, nil, nil
+	}
+	endpoint, err := d.store.GetWebhookEndpoint(ctx, delivery.EndpointID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	if endpoint.DisabledAt.Valid {
+		d.recordAttempt(delivery, 0, errEndpointDisabled)
+		return nil, nil, d.store.UpdateWebhookDelivery(ctx, delivery)
+	}
+
+	claimed := *delivery
+	claimed.NextAttemptAt = time.Now().Add(d.timeout + claimMargin)
+	if err = d.store.UpdateWebhookDelivery(ctx, &claimed); err != nil {
+		return nil, nil, err
+	}
+	return delivery, endpoint, nil
+}
+
+func (d Dispatcher) send(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, delivery.EventID.String())
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, time.Now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain the response so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d Dispatcher) recordAttempt(delivery *domain.WebhookDelivery, statusCode int, err error) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastResponseStatus = sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0}
+	if err == nil {
+		delivery.Status = domain.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt = sql.NullTime{Time: now, Valid: true}
+		delivery.LastError = sql.NullString{}
+		return
+	}
+
+	message := err.Error()
+	if len(message) > maxErrorLen {
+		message = message[:maxErrorLen]
+	}
+	delivery.LastError = sql.NullString{String: message, Valid: true}
+	if errors.Is(err, errEndpointDisabled) || delivery.Attempts >= d.maxAttempts {
+		delivery.Status = domain.WebhookDeliveryStatusFailed
+		return
+	}
+	backoff := d.backoff << (delivery.Attempts - 1)
+	if backoff > MaxBackoff || backoff <= 0 {
+		backoff = MaxBackoff
+	}
+	delivery.NextAttemptAt = now.Add(backoff)
+}
@@ -0,0 +1,221 @@
+package webhook_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/webhook"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/webhook/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execInTransaction(store *mocks.MockStore) {
+	store.EXPECT().ExecInTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+	payload := []byte(`{"id":"event"}`)
+
+	for _, tc := range []struct {
+		description string
+		// status is what the endpoint responds with
+		status     int
+		attempts   int
+		disabled   bool
+		expStatus  domain.WebhookDeliveryStatus
+		expRetry   bool
+		expCode    int32
+		expErrText string
+	}{
+		{
+			description: "should mark the delivery succeeded given the endpoint accepts it",
+			status:      http.StatusNoContent,
+			expStatus:   domain.WebhookDeliveryStatusSucceeded,
+			expCode:     http.StatusNoContent,
+		},
+		{
+			description: "should retry with backoff given the endpoint fails",
+			status:      http.StatusInternalServerError,
+			expStatus:   domain.WebhookDeliveryStatusPending,
+			expRetry:    true,
+			expCode:     http.StatusInternalServerError,
+			expErrText:  "endpoint responded with 500",
+		},
+		{
+			description: "should not follow redirects",
+			status:      http.StatusFound,
+			expStatus:   domain.WebhookDeliveryStatusPending,
+			expRetry:    true,
+			expCode:     http.StatusFound,
+			expErrText:  "endpoint responded with 302",
+		},
+		{
+			description: "should mark the delivery failed given its attempts are exhausted",
+			status:      http.StatusInternalServerError,
+			attempts:    2,
+			expStatus:   domain.WebhookDeliveryStatusFailed,
+			expCode:     http.StatusInternalServerError,
+			expErrText:  "endpoint responded with 500",
+		},
+		{
+			description: "should mark the delivery failed given the endpoint is disabled",
+			disabled:    true,
+			expStatus:   domain.WebhookDeliveryStatusFailed,
+			expErrText:  "endpoint disabled",
+		},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			var (
+				store    = mocks.NewMockStore(gomock.NewController(t))
+				received = make(chan *http.Request, 1)
+				server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, err := ioutil.ReadAll(r.Body)
+					require.NoError(t, err)
+					assert.Equal(t, payload, body)
+					received <- r
+					if tc.status == http.StatusFound {
+						w.Header().Set("Location", "/elsewhere")
+					}
+					w.WriteHeader(tc.status)
+				}))
+				endpoint = &domain.WebhookEndpoint{ID: uuid.NewV4(), URL: server.URL, Secret: "whsec_secret"}
+				delivery = &domain.WebhookDelivery{
+					ID:            uuid.NewV4(),
+					EndpointID:    endpoint.ID,
+					EventID:       uuid.NewV4(),
+					Payload:       payload,
+					Status:        domain.WebhookDeliveryStatusPending,
+					Attempts:      tc.attempts,
+					NextAttemptAt: time.Now().Add(-time.Second),
+				}
+				updates []domain.WebhookDelivery
+			)
+			defer server.Close()
+			if tc.disabled {
+				endpoint.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+			}
+
+			execInTransaction(store)
+			store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), delivery.ID.String()).Return(delivery, nil)
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), endpoint.ID.String()).Return(endpoint, nil)
+			store.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, d *domain.WebhookDelivery) error {
+				updates = append(updates, *d)
+				return nil
+			}).AnyTimes()
+
+			dispatcher := webhook.NewDispatcher(store, time.Second, webhook.WithRetries(3, time.Minute),
+				webhook.WithPrivateNetworks())
+			require.NoError(t, dispatcher.Dispatch(context.Background(), delivery.ID.String()))
+
+			if !tc.disabled {
+				r := <-received
+				assert.Equal(t, delivery.EventID.String(), r.Header.Get(webhook.IDHeader))
+				assert.NoError(t, webhook.Verify("whsec_secret", r.Header.Get(webhook.SignatureHeader), payload, webhook.DefaultTolerance, time.Now()))
+			}
+			if tc.disabled {
+				require.Len(t, updates, 1)
+			} else {
+				// claimed before being sent
+				require.Len(t, updates, 2)
+				assert.Equal(t, domain.WebhookDeliveryStatusPending, updates[0].Status)
+				assert.Equal(t, tc.attempts, updates[0].Attempts)
+				assert.True(t, updates[0].NextAttemptAt.After(time.Now().Add(webhook.DefaultTimeout)))
+			}
+			updated := updates[len(updates)-1]
+			assert.Equal(t, tc.expStatus, updated.Status)
+			assert.Equal(t, tc.attempts+1, updated.Attempts)
+			assert.Equal(t, tc.expCode, updated.LastResponseStatus.Int32)
+			assert.Equal(t, tc.expErrText, updated.LastError.String)
+			if tc.expRetry {
+				assert.WithinDuration(t, time.Now().Add(time.Minute), updated.NextAttemptAt, 5*time.Second)
+			}
+			if tc.expStatus == domain.WebhookDeliveryStatusSucceeded {
+				assert.True(t, updated.DeliveredAt.Valid)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Dispatch_PrivateNetwork(t *testing.T) {
+	t.Parallel()
+	var (
+		store    = mocks.NewMockStore(gomock.NewController(t))
+		received = false
+		server   = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = true
+		}))
+		endpoint = &domain.WebhookEndpoint{ID: uuid.NewV4(), URL: server.URL}
+		delivery = &domain.WebhookDelivery{
+			ID:            uuid.NewV4(),
+			EndpointID:    endpoint.ID,
+			Status:        domain.WebhookDeliveryStatusPending,
+			NextAttemptAt: time.Now().Add(-time.Second),
+		}
+		updated *domain.WebhookDelivery
+	)
+	defer server.Close()
+
+	execInTransaction(store)
+	store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), delivery.ID.String()).Return(delivery, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), endpoint.ID.String()).Return(endpoint, nil)
+	store.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, d *domain.WebhookDelivery) error {
+		updated = d
+		return nil
+	}).Times(2)
+
+	require.NoError(t, webhook.NewDispatcher(store, time.Second).Dispatch(context.Background(), delivery.ID.String()))
+	assert.False(t, received, "should not connect to a loopback address")
+	require.NotNil(t, updated)
+	assert.Equal(t, domain.WebhookDeliveryStatusPending, updated.Status)
+	assert.Contains(t, updated.LastError.String, domain.ErrNonPublicAddress.Error())
+}
+
+func TestDispatcher_Dispatch_Skips(t *testing.T) {
+	t.Parallel()
+	t.Run("should skip a delivery locked by another dispatcher", func(t *testing.T) {
+		store := mocks.NewMockStore(gomock.NewController(t))
+		execInTransaction(store)
+		store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), "id").Return(nil, domain.ErrNoWebhookDelivery)
+		assert.NoError(t, webhook.NewDispatcher(store, time.Second).Dispatch(context.Background(), "id"))
+	})
+	t.Run("should skip a delivery no longer due", func(t *testing.T) {
+		store := mocks.NewMockStore(gomock.NewController(t))
+		execInTransaction(store)
+		store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), "id").Return(&domain.WebhookDelivery{
+			Status:        domain.WebhookDeliveryStatusSucceeded,
+			NextAttemptAt: time.Now().Add(-time.Second),
+		}, nil)
+		assert.NoError(t, webhook.NewDispatcher(store, time.Second).Dispatch(context.Background(), "id"))
+	})
+}
+
+func TestDispatcher_DispatchBatch(t *testing.T) {
+	t.Parallel()
+	store := mocks.NewMockStore(gomock.NewController(t))
+	execInTransaction(store)
+	deliveries := []*domain.WebhookDelivery{{ID: uuid.NewV4()}, {ID: uuid.NewV4()}}
+	store.EXPECT().ListDueWebhookDeliveries(gomock.Any(), uint64(webhook.DefaultBatchSize)).Return(deliveries, nil)
+	// a failing delivery does not stop the rest of the batch
+	store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), deliveries[0].ID.String()).Return(nil, errors.New("boom"))
+	store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), deliveries[1].ID.String()).Return(nil, domain.ErrNoWebhookDelivery)
+
+	n, err := webhook.NewDispatcher(store, time.Second).DispatchBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
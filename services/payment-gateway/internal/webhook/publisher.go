@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/outbox"
+)
+
+// Notification is the JSON body POSTed to webhook endpoints.
+type Notification struct {
+	// ID is the id of the event, it is the same for every attempt to deliver it.
+	ID        string          `json:"id"`
+	PaymentID string          `json:"payment_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Publisher is an outbox.Publisher queuing a delivery of each event to the endpoints of the merchant owning the
+// payment. Deliveries are queued within the relay's transaction so that each event is queued exactly once.
+type Publisher struct {
+	store Store
+}
+
+func NewPublisher(store Store) Publisher {
+	return Publisher{store: store}
+}
+
+func (p Publisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	payload, err := outbox.DecodePayload(event)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(Notification{
+		ID:        event.ID.String(),
+		PaymentID: event.PaymentID.String(),
+		EventType: event.EventType,
+		Payload:   payload,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.store.CreateWebhookDeliveries(ctx, event, body)
+	return err
+}
@@ -0,0 +1,88 @@
+//go:generate mockgen -source=service.go -destination=mocks/mocks.go -package=mocks
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SecretPrefix starts every endpoint's signing secret so that they are recognisable.
+	SecretPrefix = "whsec_"
+	secretBytes  = 32
+)
+
+type Store interface {
+	ExecInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	CreateWebhookEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error
+	GetWebhookEndpoint(ctx context.Context, id string) (*domain.WebhookEndpoint, error)
+	ListWebhookEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error)
+	DisableWebhookEndpoint(ctx context.Context, id string) error
+
+	CreateWebhookDeliveries(ctx context.Context, event *domain.OutboxEvent, payload []byte) (int64, error)
+	ListWebhookDeliveries(ctx context.Context, filters *domain.ListWebhookDeliveryFilters) ([]*domain.WebhookDelivery, error)
+	ListDueWebhookDeliveries(ctx context.Context, limit uint64) ([]*domain.WebhookDelivery, error)
+	GetWebhookDeliveryForUpdate(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// Service manages the webhook endpoints of the merchant the context is scoped to along with their deliveries.
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) Service {
+	return Service{store: store}
+}
+
+// RegisterEndpoint registers the URL to be notified of changes to the merchant's payments. The endpoint's secret is
+// returned so that the merchant can verify notifications.
+func (s Service) RegisterEndpoint(ctx context.Context, url string) (*domain.WebhookEndpoint, error) {
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errors.Wrap(err, "unable to generate webhook secret")
+	}
+	endpoint := &domain.WebhookEndpoint{URL: url, Secret: SecretPrefix + hex.EncodeToString(secret)}
+	if err := s.store.CreateWebhookEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s Service) ListEndpoints(ctx context.Context) ([]*domain.WebhookEndpoint, error) {
+	return s.store.ListWebhookEndpoints(ctx)
+}
+
+// DisableEndpoint stops the endpoint being notified, pending deliveries to it fail on their next attempt.
+func (s Service) DisableEndpoint(ctx context.Context, id string) error {
+	return s.store.DisableWebhookEndpoint(ctx, id)
+}
+
+func (s Service) ListDeliveries(ctx context.Context, filters domain.ListWebhookDeliveryFilters) ([]*domain.WebhookDelivery, error) {
+	return s.store.ListWebhookDeliveries(ctx, &filters)
+}
+
+// ReplayDelivery queues the delivery to be sent again straight away with a fresh set of attempts, regardless of
+// whether it succeeded or failed before.
+func (s Service) ReplayDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	var delivery *domain.WebhookDelivery
+	if err := s.store.ExecInTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if delivery, err = s.store.GetWebhookDeliveryForUpdate(ctx, id); err != nil {
+			return err
+		}
+		delivery.Status = domain.WebhookDeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now()
+		return s.store.UpdateWebhookDelivery(ctx, delivery)
+	}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,71 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/webhook"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/webhook/mocks"
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_RegisterEndpoint(t *testing.T) {
+	t.Parallel()
+	store := mocks.NewMockStore(gomock.NewController(t))
+	store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Return(nil)
+
+	endpoint, err := webhook.NewService(store).RegisterEndpoint(context.Background(), "https://merchant.example/webhooks")
+	require.NoError(t, err)
+	assert.Equal(t, "https://merchant.example/webhooks", endpoint.URL)
+	assert.True(t, strings.HasPrefix(endpoint.Secret, webhook.SecretPrefix))
+}
+
+func TestService_ReplayDelivery(t *testing.T) {
+	t.Parallel()
+	store := mocks.NewMockStore(gomock.NewController(t))
+	execInTransaction(store)
+	store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), "id").Return(&domain.WebhookDelivery{
+		Status:   domain.WebhookDeliveryStatusFailed,
+		Attempts: webhook.DefaultMaxAttempts,
+	}, nil)
+	store.EXPECT().UpdateWebhookDelivery(gomock.Any(), gomock.Any()).Return(nil)
+
+	delivery, err := webhook.NewService(store).ReplayDelivery(context.Background(), "id")
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryStatusPending, delivery.Status)
+	assert.Zero(t, delivery.Attempts)
+	assert.WithinDuration(t, time.Now(), delivery.NextAttemptAt, time.Second)
+
+	store.EXPECT().GetWebhookDeliveryForUpdate(gomock.Any(), "unknown").Return(nil, domain.ErrNoWebhookDelivery)
+	_, err = webhook.NewService(store).ReplayDelivery(context.Background(), "unknown")
+	assert.ErrorIs(t, err, domain.ErrNoWebhookDelivery)
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	t.Parallel()
+	store := mocks.NewMockStore(gomock.NewController(t))
+	paymentID := uuid.NewV4().String()
+	event, err := domain.NewOutboxEvent(paymentID, &paymentsV1.PaymentAuthorized{Payment: &paymentsV1.Payment{Id: paymentID}})
+	require.NoError(t, err)
+	event.ID = uuid.NewV4()
+
+	store.EXPECT().CreateWebhookDeliveries(gomock.Any(), event, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *domain.OutboxEvent, body []byte) (int64, error) {
+			var notification webhook.Notification
+			require.NoError(t, json.Unmarshal(body, &notification))
+			assert.Equal(t, event.ID.String(), notification.ID)
+			assert.Equal(t, paymentID, notification.PaymentID)
+			assert.Equal(t, "shared.payment.v1.PaymentAuthorized", notification.EventType)
+			assert.Contains(t, string(notification.Payload), paymentID)
+			return 1, nil
+		})
+	require.NoError(t, webhook.NewPublisher(store).Publish(context.Background(), event))
+}
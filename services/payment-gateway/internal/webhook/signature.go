@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureHeader carries the timestamp the notification was sent at along with its signature,
+	// e.g. t=1636370000,v1=5257a869...
+	SignatureHeader = "Webhook-Signature"
+	// IDHeader carries the id of the event, it is the same for every attempt so that merchants can drop duplicates.
+	IDHeader = "Webhook-Id"
+
+	DefaultTolerance = 5 * time.Minute
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the signature header for the body sent at timestamp. The signature is the hex HMAC-SHA256 of
+// "<unix timestamp>.<body>" keyed by the endpoint's secret, the timestamp being signed to prevent replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(signature(secret, t, body))
+}
+
+// Verify checks the signature header of a notification, rejecting it if it was signed more than tolerance from now.
+// It is what merchants are expected to do when receiving a notification.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			t = kv[1]
+		case "v1":
+			v1 = kv[1]
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return errors.Wrap(ErrInvalidSignature, "timestamp outside of tolerance")
+	}
+	got, err := hex.DecodeString(v1)
+	if err != nil || !hmac.Equal(got, signature(secret, t, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signature(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
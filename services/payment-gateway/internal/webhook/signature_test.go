@@ -0,0 +1,46 @@
+package webhook_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+	var (
+		now    = time.Unix(1636370000, 0)
+		body   = []byte(`{"id":"abc"}`)
+		header = webhook.Sign("whsec_secret", now, body)
+	)
+	assert.Regexp(t, `^t=1636370000,v1=[0-9a-f]{64}$`, header)
+
+	for _, tc := range []struct {
+		description string
+		secret      string
+		header      string
+		body        []byte
+		now         time.Time
+		err         bool
+	}{
+		{description: "should verify the signature", secret: "whsec_secret", header: header, body: body, now: now},
+		{description: "should verify within tolerance", secret: "whsec_secret", header: header, body: body, now: now.Add(4 * time.Minute)},
+		{description: "should reject a different secret", secret: "whsec_other", header: header, body: body, now: now, err: true},
+		{description: "should reject a tampered body", secret: "whsec_secret", header: header, body: []byte(`{"id":"abd"}`), now: now, err: true},
+		{description: "should reject an old timestamp", secret: "whsec_secret", header: header, body: body, now: now.Add(6 * time.Minute), err: true},
+		{description: "should reject a malformed header", secret: "whsec_secret", header: "v1=abc", body: body, now: now, err: true},
+	} {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			err := webhook.Verify(tc.secret, tc.header, tc.body, webhook.DefaultTolerance, tc.now)
+			if tc.err {
+				assert.ErrorIs(t, err, webhook.ErrInvalidSignature)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
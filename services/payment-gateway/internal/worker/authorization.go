@@ -0,0 +1,190 @@
+//go:generate mockgen -source=authorization.go -destination=mocks/mock_authorization.go -package=mocks
+
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultWorkers   = 4
+	DefaultBatchSize = 100
+	DefaultInterval  = time.Second
+	// MaxAuthorizationBackoff caps how long an authorization that keeps failing waits before it is retried.
+	MaxAuthorizationBackoff = 5 * time.Minute
+)
+
+type AuthorizationGateway interface {
+	ListPendingAuthorizations(ctx context.Context, limit uint64) ([]*paymentsV1.PaymentAction, error)
+	// ProcessAuthorization returns domain.ErrPaymentActionClaimed for an authorization another worker has claimed.
+	ProcessAuthorization(ctx context.Context, paymentActionID string) error
+}
+
+// AuthorizationPool drains pending authorizations to the issuer using a pool of workers.
+type AuthorizationPool struct {
+	gateway   AuthorizationGateway
+	workers   int
+	batchSize uint64
+	interval  time.Duration
+	backoff   *authorizationBackoff
+}
+
+func NewAuthorizationPool(gateway AuthorizationGateway, workers int, interval time.Duration) AuthorizationPool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	return AuthorizationPool{
+		gateway:   gateway,
+		workers:   workers,
+		batchSize: DefaultBatchSize,
+		interval:  interval,
+		backoff:   &authorizationBackoff{base: interval, retries: map[string]authorizationRetry{}},
+	}
+}
+
+// Run processes pending authorizations every interval until the context is cancelled. Batches are drained back to
+// back only whilst every authorization in them is sent, so that neither a failing issuer nor authorizations claimed
+// by other workers are retried without a pause.
+func (p AuthorizationPool) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				sent, err := p.ProcessBatch(ctx)
+				if err != nil {
+					log.WithError(err).Error("failed to list pending authorizations")
+					break
+				}
+				if uint64(sent) < p.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// ProcessBatch sends the oldest batch of pending authorizations to the issuer across the workers, returning how many
+// were sent once every authorization has been attempted. Authorizations claimed by another worker are skipped and not
+// counted. Failed authorizations remain pending and are retried within a later batch, backing off exponentially
+// whilst they keep failing.
+func (p AuthorizationPool) ProcessBatch(ctx context.Context) (int, error) {
+	actions, err := p.gateway.ListPendingAuthorizations(ctx, p.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	p.backoff.retain(actions)
+
+	var (
+		jobs = make(chan string)
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		sent int
+	)
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for paymentActionID := range jobs {
+				if err := p.gateway.ProcessAuthorization(ctx, paymentActionID); err != nil {
+					if errors.Is(err, domain.ErrPaymentActionClaimed) {
+						continue
+					}
+					p.backoff.failed(paymentActionID, time.Now())
+					log.WithError(err).WithField("payment_action.id", paymentActionID).Error("failed to process authorization")
+					continue
+				}
+				p.backoff.succeeded(paymentActionID)
+				mu.Lock()
+				sent++
+				mu.Unlock()
+			}
+		}()
+	}
+send:
+	for _, action := range actions {
+		if !p.backoff.due(action.Id, time.Now()) {
+			continue
+		}
+		select {
+		case jobs <- action.Id:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return sent, nil
+}
+
+// authorizationRetry is when an authorization that failed is next due to be retried.
+type authorizationRetry struct {
+	failures int
+	at       time.Time
+}
+
+// authorizationBackoff spaces out the retries of authorizations that keep failing, doubling the wait after each
+// failure up to MaxAuthorizationBackoff.
+type authorizationBackoff struct {
+	base    time.Duration
+	mu      sync.Mutex
+	retries map[string]authorizationRetry
+}
+
+func (b *authorizationBackoff) due(paymentActionID string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	retry, ok := b.retries[paymentActionID]
+	return !ok || !now.Before(retry.at)
+}
+
+func (b *authorizationBackoff) failed(paymentActionID string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	retry := b.retries[paymentActionID]
+	retry.failures++
+	wait := b.base
+	for i := 1; i < retry.failures && wait < MaxAuthorizationBackoff; i++ {
+		wait *= 2
+	}
+	if wait > MaxAuthorizationBackoff {
+		wait = MaxAuthorizationBackoff
+	}
+	retry.at = now.Add(wait)
+	b.retries[paymentActionID] = retry
+}
+
+func (b *authorizationBackoff) succeeded(paymentActionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.retries, paymentActionID)
+}
+
+// retain forgets the authorizations no longer pending, such as those processed by another instance.
+func (b *authorizationBackoff) retain(actions []*paymentsV1.PaymentAction) {
+	pending := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		pending[action.Id] = true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for paymentActionID := range b.retries {
+		if !pending[paymentActionID] {
+			delete(b.retries, paymentActionID)
+		}
+	}
+}
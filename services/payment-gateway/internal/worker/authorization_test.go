@@ -0,0 +1,172 @@
+package worker_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/domain"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizationPool_ProcessBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return error given unable to list pending authorizations", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+		)
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), uint64(worker.DefaultBatchSize)).Return(nil, errors.New("error"))
+
+		_, err := worker.NewAuthorizationPool(mockGateway, 2, time.Second).ProcessBatch(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("should process every pending authorization", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+
+			mu        sync.Mutex
+			processed []string
+		)
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), gomock.Any()).Return([]*paymentsV1.PaymentAction{
+			{Id: "1"}, {Id: "2"}, {Id: "3"},
+		}, nil)
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				processed = append(processed, id)
+				if id == "2" {
+					return errors.New("error")
+				}
+				return nil
+			}).Times(3)
+
+		n, err := worker.NewAuthorizationPool(mockGateway, 2, time.Second).ProcessBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.ElementsMatch(t, []string{"1", "2", "3"}, processed)
+	})
+
+	t.Run("should not count authorizations claimed by another worker", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+		)
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), gomock.Any()).Return([]*paymentsV1.PaymentAction{
+			{Id: "1"}, {Id: "2"},
+		}, nil)
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), "1").Return(nil)
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), "2").Return(domain.ErrPaymentActionClaimed)
+
+		n, err := worker.NewAuthorizationPool(mockGateway, 2, time.Second).ProcessBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("should back off retrying an authorization that failed", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+			pool        = worker.NewAuthorizationPool(mockGateway, 2, time.Hour)
+		)
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), gomock.Any()).Return([]*paymentsV1.PaymentAction{
+			{Id: "1"},
+		}, nil).Times(2)
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), "1").Return(errors.New("issuer unavailable")).Times(1)
+
+		n, err := pool.ProcessBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		// the retry is not due for an hour
+		n, err = pool.ProcessBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("should stop sending authorizations given the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), gomock.Any()).Return([]*paymentsV1.PaymentAction{
+			{Id: "1"}, {Id: "2"}, {Id: "3"},
+		}, nil)
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(3)
+
+		_, err := worker.NewAuthorizationPool(mockGateway, 1, time.Second).ProcessBatch(ctx)
+		require.NoError(t, err)
+	})
+}
+
+func TestAuthorizationPool_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should wait for the next interval given every authorization is claimed by another worker", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+			actions     = make([]*paymentsV1.PaymentAction, worker.DefaultBatchSize)
+			listed      int32
+		)
+		for i := range actions {
+			actions[i] = &paymentsV1.PaymentAction{Id: strconv.Itoa(i)}
+		}
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, uint64) ([]*paymentsV1.PaymentAction, error) {
+				atomic.AddInt32(&listed, 1)
+				return actions, nil
+			}).AnyTimes()
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), gomock.Any()).Return(domain.ErrPaymentActionClaimed).AnyTimes()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+		worker.NewAuthorizationPool(mockGateway, 4, 100*time.Millisecond).Run(ctx)
+		assert.LessOrEqual(t, atomic.LoadInt32(&listed), int32(2))
+	})
+
+	t.Run("should wait for the next interval given a batch makes no progress", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockAuthorizationGateway(ctrl)
+			actions     = make([]*paymentsV1.PaymentAction, worker.DefaultBatchSize)
+			listed      int32
+		)
+		for i := range actions {
+			actions[i] = &paymentsV1.PaymentAction{Id: strconv.Itoa(i)}
+		}
+		mockGateway.EXPECT().ListPendingAuthorizations(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, uint64) ([]*paymentsV1.PaymentAction, error) {
+				atomic.AddInt32(&listed, 1)
+				return actions, nil
+			}).AnyTimes()
+		mockGateway.EXPECT().ProcessAuthorization(gomock.Any(), gomock.Any()).Return(errors.New("issuer unavailable")).AnyTimes()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+		worker.NewAuthorizationPool(mockGateway, 4, 100*time.Millisecond).Run(ctx)
+		assert.LessOrEqual(t, atomic.LoadInt32(&listed), int32(2))
+	})
+}
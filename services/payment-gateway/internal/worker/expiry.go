@@ -0,0 +1,86 @@
+//go:generate mockgen -source=expiry.go -destination=mocks/mock_expiry.go -package=mocks
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultExpiryInterval = 5 * time.Minute
+
+type ExpiryGateway interface {
+	ListExpiredAuthorizations(ctx context.Context, expiredBefore time.Time, limit uint64) ([]*paymentsV1.Payment, error)
+	Void(ctx context.Context, paymentID string) (*paymentsV1.Payment, error)
+	Reverse(ctx context.Context, paymentID string, amount uint64) (*paymentsV1.Payment, error)
+	ExpireAuthorization(ctx context.Context, paymentID string) (*paymentsV1.Payment, error)
+}
+
+// ExpirySweeper releases authorizations that have expired without being fully captured. Each is voided with the
+// issuer, or has its remainder reversed if partially captured, those the issuer declines to release are marked as
+// expired as the scheme has already released the hold.
+type ExpirySweeper struct {
+	gateway   ExpiryGateway
+	batchSize uint64
+	interval  time.Duration
+}
+
+func NewExpirySweeper(gateway ExpiryGateway, interval time.Duration) ExpirySweeper {
+	if interval == 0 {
+		interval = DefaultExpiryInterval
+	}
+	return ExpirySweeper{gateway: gateway, batchSize: DefaultBatchSize, interval: interval}
+}
+
+// Run sweeps a batch of expired authorizations every interval until the context is cancelled.
+func (s ExpirySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SweepBatch(ctx); err != nil {
+				log.WithError(err).Error("failed to list expired authorizations")
+			}
+		}
+	}
+}
+
+// SweepBatch attempts to release a batch of expired authorizations, returning the size of the batch. Authorizations
+// that fail to be released are retried by a later sweep.
+func (s ExpirySweeper) SweepBatch(ctx context.Context) (int, error) {
+	payments, err := s.gateway.ListExpiredAuthorizations(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, payment := range payments {
+		logger := log.WithField("payment.id", payment.Id)
+		released, err := s.release(ctx, payment)
+		if err != nil {
+			logger.WithError(err).Error("failed to release expired authorization")
+			continue
+		}
+		if released.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED ||
+			released.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED {
+			continue
+		}
+		if _, err = s.gateway.ExpireAuthorization(ctx, payment.Id); err != nil {
+			logger.WithError(err).Error("failed to expire authorization")
+		}
+	}
+	return len(payments), nil
+}
+
+// release asks the issuer to release what the payment still holds, a partially captured payment keeping what was
+// captured.
+func (s ExpirySweeper) release(ctx context.Context, payment *paymentsV1.Payment) (*paymentsV1.Payment, error) {
+	if payment.PaymentStatus == paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED {
+		return s.gateway.Reverse(ctx, payment.Id, 0)
+	}
+	return s.gateway.Void(ctx, payment.Id)
+}
@@ -0,0 +1,81 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpirySweeper_SweepBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return error given unable to list expired authorizations", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockExpiryGateway(ctrl)
+		)
+		mockGateway.EXPECT().ListExpiredAuthorizations(gomock.Any(), gomock.Any(), uint64(worker.DefaultBatchSize)).
+			Return(nil, errors.New("error"))
+
+		_, err := worker.NewExpirySweeper(mockGateway, time.Second).SweepBatch(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("should void every expired authorization marking those not voided as expired", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockExpiryGateway(ctrl)
+		)
+		mockGateway.EXPECT().ListExpiredAuthorizations(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, expiredBefore time.Time, limit uint64) ([]*paymentsV1.Payment, error) {
+				assert.WithinDuration(t, time.Now(), expiredBefore, time.Second)
+				return []*paymentsV1.Payment{{Id: "1"}, {Id: "2"}, {Id: "3"}}, nil
+			})
+		gomock.InOrder(
+			mockGateway.EXPECT().Void(gomock.Any(), "1").
+				Return(&paymentsV1.Payment{Id: "1", PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_VOIDED}, nil),
+			mockGateway.EXPECT().Void(gomock.Any(), "2").Return(nil, errors.New("error")),
+			mockGateway.EXPECT().Void(gomock.Any(), "3").
+				Return(&paymentsV1.Payment{Id: "3", PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED}, nil),
+			mockGateway.EXPECT().ExpireAuthorization(gomock.Any(), "3").
+				Return(&paymentsV1.Payment{Id: "3", PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_EXPIRED}, nil),
+		)
+
+		n, err := worker.NewExpirySweeper(mockGateway, time.Second).SweepBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("should reverse the remainder of partially captured payments marking those not reversed as expired", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl              = gomock.NewController(t)
+			mockGateway       = mocks.NewMockExpiryGateway(ctrl)
+			partiallyCaptured = paymentsV1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_CAPTURED
+		)
+		mockGateway.EXPECT().ListExpiredAuthorizations(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*paymentsV1.Payment{{Id: "1", PaymentStatus: partiallyCaptured}, {Id: "2", PaymentStatus: partiallyCaptured}}, nil)
+		gomock.InOrder(
+			mockGateway.EXPECT().Reverse(gomock.Any(), "1", uint64(0)).
+				Return(&paymentsV1.Payment{Id: "1", PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED}, nil),
+			mockGateway.EXPECT().Reverse(gomock.Any(), "2", uint64(0)).
+				Return(&paymentsV1.Payment{Id: "2", PaymentStatus: partiallyCaptured}, nil),
+			mockGateway.EXPECT().ExpireAuthorization(gomock.Any(), "2").
+				Return(&paymentsV1.Payment{Id: "2", PaymentStatus: paymentsV1.PaymentStatus_PAYMENT_STATUS_CAPTURED}, nil),
+		)
+
+		n, err := worker.NewExpirySweeper(mockGateway, time.Second).SweepBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+}
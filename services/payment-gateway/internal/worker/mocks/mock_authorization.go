@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: authorization.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+)
+
+// MockAuthorizationGateway is a mock of AuthorizationGateway interface.
+type MockAuthorizationGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationGatewayMockRecorder
+}
+
+// MockAuthorizationGatewayMockRecorder is the mock recorder for MockAuthorizationGateway.
+type MockAuthorizationGatewayMockRecorder struct {
+	mock *MockAuthorizationGateway
+}
+
+// NewMockAuthorizationGateway creates a new mock instance.
+func NewMockAuthorizationGateway(ctrl *gomock.Controller) *MockAuthorizationGateway {
+	mock := &MockAuthorizationGateway{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationGateway) EXPECT() *MockAuthorizationGatewayMockRecorder {
+	return m.recorder
+}
+
+// ListPendingAuthorizations mocks base method.
+func (m *MockAuthorizationGateway) ListPendingAuthorizations(ctx context.Context, limit uint64) ([]*v1.PaymentAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingAuthorizations", ctx, limit)
+	ret0, _ := ret[0].([]*v1.PaymentAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingAuthorizations indicates an expected call of ListPendingAuthorizations.
+func (mr *MockAuthorizationGatewayMockRecorder) ListPendingAuthorizations(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingAuthorizations", reflect.TypeOf((*MockAuthorizationGateway)(nil).ListPendingAuthorizations), ctx, limit)
+}
+
+// ProcessAuthorization mocks base method.
+func (m *MockAuthorizationGateway) ProcessAuthorization(ctx context.Context, paymentActionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessAuthorization", ctx, paymentActionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessAuthorization indicates an expected call of ProcessAuthorization.
+func (mr *MockAuthorizationGatewayMockRecorder) ProcessAuthorization(ctx, paymentActionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAuthorization", reflect.TypeOf((*MockAuthorizationGateway)(nil).ProcessAuthorization), ctx, paymentActionID)
+}
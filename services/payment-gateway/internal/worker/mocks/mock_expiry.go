@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: expiry.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	v1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+)
+
+// MockExpiryGateway is a mock of ExpiryGateway interface.
+type MockExpiryGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpiryGatewayMockRecorder
+}
+
+// MockExpiryGatewayMockRecorder is the mock recorder for MockExpiryGateway.
+type MockExpiryGatewayMockRecorder struct {
+	mock *MockExpiryGateway
+}
+
+// NewMockExpiryGateway creates a new mock instance.
+func NewMockExpiryGateway(ctrl *gomock.Controller) *MockExpiryGateway {
+	mock := &MockExpiryGateway{ctrl: ctrl}
+	mock.recorder = &MockExpiryGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpiryGateway) EXPECT() *MockExpiryGatewayMockRecorder {
+	return m.recorder
+}
+
+// ExpireAuthorization mocks base method.
+func (m *MockExpiryGateway) ExpireAuthorization(ctx context.Context, paymentID string) (*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAuthorization", ctx, paymentID)
+	ret0, _ := ret[0].(*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireAuthorization indicates an expected call of ExpireAuthorization.
+func (mr *MockExpiryGatewayMockRecorder) ExpireAuthorization(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAuthorization", reflect.TypeOf((*MockExpiryGateway)(nil).ExpireAuthorization), ctx, paymentID)
+}
+
+// ListExpiredAuthorizations mocks base method.
+func (m *MockExpiryGateway) ListExpiredAuthorizations(ctx context.Context, expiredBefore time.Time, limit uint64) ([]*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredAuthorizations", ctx, expiredBefore, limit)
+	ret0, _ := ret[0].([]*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredAuthorizations indicates an expected call of ListExpiredAuthorizations.
+func (mr *MockExpiryGatewayMockRecorder) ListExpiredAuthorizations(ctx, expiredBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredAuthorizations", reflect.TypeOf((*MockExpiryGateway)(nil).ListExpiredAuthorizations), ctx, expiredBefore, limit)
+}
+
+// Reverse mocks base method.
+func (m *MockExpiryGateway) Reverse(ctx context.Context, paymentID string, amount uint64) (*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, paymentID, amount)
+	ret0, _ := ret[0].(*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockExpiryGatewayMockRecorder) Reverse(ctx, paymentID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockExpiryGateway)(nil).Reverse), ctx, paymentID, amount)
+}
+
+// Void mocks base method.
+func (m *MockExpiryGateway) Void(ctx context.Context, paymentID string) (*v1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, paymentID)
+	ret0, _ := ret[0].(*v1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockExpiryGatewayMockRecorder) Void(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockExpiryGateway)(nil).Void), ctx, paymentID)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recovery.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	v1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+)
+
+// MockRecoveryGateway is a mock of RecoveryGateway interface.
+type MockRecoveryGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecoveryGatewayMockRecorder
+}
+
+// MockRecoveryGatewayMockRecorder is the mock recorder for MockRecoveryGateway.
+type MockRecoveryGatewayMockRecorder struct {
+	mock *MockRecoveryGateway
+}
+
+// NewMockRecoveryGateway creates a new mock instance.
+func NewMockRecoveryGateway(ctrl *gomock.Controller) *MockRecoveryGateway {
+	mock := &MockRecoveryGateway{ctrl: ctrl}
+	mock.recorder = &MockRecoveryGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecoveryGateway) EXPECT() *MockRecoveryGatewayMockRecorder {
+	return m.recorder
+}
+
+// ListUnresolvedPaymentActions mocks base method.
+func (m *MockRecoveryGateway) ListUnresolvedPaymentActions(ctx context.Context, createdBefore time.Time, limit uint64) ([]*v1.PaymentAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnresolvedPaymentActions", ctx, createdBefore, limit)
+	ret0, _ := ret[0].([]*v1.PaymentAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnresolvedPaymentActions indicates an expected call of ListUnresolvedPaymentActions.
+func (mr *MockRecoveryGatewayMockRecorder) ListUnresolvedPaymentActions(ctx, createdBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnresolvedPaymentActions", reflect.TypeOf((*MockRecoveryGateway)(nil).ListUnresolvedPaymentActions), ctx, createdBefore, limit)
+}
+
+// RecoverPaymentAction mocks base method.
+func (m *MockRecoveryGateway) RecoverPaymentAction(ctx context.Context, paymentActionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecoverPaymentAction", ctx, paymentActionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecoverPaymentAction indicates an expected call of RecoverPaymentAction.
+func (mr *MockRecoveryGatewayMockRecorder) RecoverPaymentAction(ctx, paymentActionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecoverPaymentAction", reflect.TypeOf((*MockRecoveryGateway)(nil).RecoverPaymentAction), ctx, paymentActionID)
+}
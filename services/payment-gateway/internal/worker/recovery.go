@@ -0,0 +1,71 @@
+//go:generate mockgen -source=recovery.go -destination=mocks/mock_recovery.go -package=mocks
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultRecoveryThreshold = 5 * time.Minute
+	DefaultRecoveryInterval  = time.Minute
+)
+
+type RecoveryGateway interface {
+	ListUnresolvedPaymentActions(ctx context.Context, createdBefore time.Time, limit uint64) ([]*paymentsV1.PaymentAction, error)
+	RecoverPaymentAction(ctx context.Context, paymentActionID string) error
+}
+
+// RecoverySweeper reconciles payment actions that have been left without an outcome from the issuer
+// for longer than the threshold.
+type RecoverySweeper struct {
+	gateway   RecoveryGateway
+	threshold time.Duration
+	batchSize uint64
+	interval  time.Duration
+}
+
+func NewRecoverySweeper(gateway RecoveryGateway, threshold, interval time.Duration) RecoverySweeper {
+	if threshold == 0 {
+		threshold = DefaultRecoveryThreshold
+	}
+	if interval == 0 {
+		interval = DefaultRecoveryInterval
+	}
+	return RecoverySweeper{gateway: gateway, threshold: threshold, batchSize: DefaultBatchSize, interval: interval}
+}
+
+// Run sweeps a batch of unresolved payment actions every interval until the context is cancelled.
+func (s RecoverySweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SweepBatch(ctx); err != nil {
+				log.WithError(err).Error("failed to list unresolved payment actions")
+			}
+		}
+	}
+}
+
+// SweepBatch attempts to recover the oldest batch of payment actions older than the threshold, returning
+// the size of the batch. Actions that fail to recover are retried by a later sweep.
+func (s RecoverySweeper) SweepBatch(ctx context.Context) (int, error) {
+	actions, err := s.gateway.ListUnresolvedPaymentActions(ctx, time.Now().Add(-s.threshold), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, action := range actions {
+		if err = s.gateway.RecoverPaymentAction(ctx, action.Id); err != nil {
+			log.WithError(err).WithField("payment_action.id", action.Id).Error("failed to recover payment action")
+		}
+	}
+	return len(actions), nil
+}
@@ -0,0 +1,53 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	paymentsV1 "github.com/jacktantram/payments-api/build/go/shared/payment/v1"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker"
+	"github.com/jacktantram/payments-api/services/payment-gateway/internal/worker/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverySweeper_SweepBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return error given unable to list unresolved payment actions", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockRecoveryGateway(ctrl)
+		)
+		mockGateway.EXPECT().ListUnresolvedPaymentActions(gomock.Any(), gomock.Any(), uint64(worker.DefaultBatchSize)).
+			Return(nil, errors.New("error"))
+
+		_, err := worker.NewRecoverySweeper(mockGateway, time.Minute, time.Second).SweepBatch(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("should attempt to recover every action older than the threshold", func(t *testing.T) {
+		t.Parallel()
+		var (
+			ctrl        = gomock.NewController(t)
+			mockGateway = mocks.NewMockRecoveryGateway(ctrl)
+		)
+		mockGateway.EXPECT().ListUnresolvedPaymentActions(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, createdBefore time.Time, limit uint64) ([]*paymentsV1.PaymentAction, error) {
+				assert.WithinDuration(t, time.Now().Add(-time.Minute), createdBefore, time.Second)
+				return []*paymentsV1.PaymentAction{{Id: "1"}, {Id: "2"}}, nil
+			})
+		gomock.InOrder(
+			mockGateway.EXPECT().RecoverPaymentAction(gomock.Any(), "1").Return(errors.New("error")),
+			mockGateway.EXPECT().RecoverPaymentAction(gomock.Any(), "2").Return(nil),
+		)
+
+		n, err := worker.NewRecoverySweeper(mockGateway, time.Minute, time.Second).SweepBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+}